@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Define the structure for a rule evaluated on a fixture's best odds in two
+// consecutive snapshots. Odds are restricted to the legs of the fixture's
+// sport, which is passed so rules can validate them.
+type AlertRule struct {
+	Name  string
+	Match func(sport string, previous, current Odds) bool
+}
+
+// Define the structure for an alert raised by a matching rule
+type Alert struct {
+	Rule     string    `json:"rule"`
+	GameID   string    `json:"game_id"`
+	Sport    string    `json:"sport,omitempty"`
+	Previous Odds      `json:"previous"`
+	Current  Odds      `json:"current"`
+	At       time.Time `json:"at"`
+}
+
+// Evaluate alert rules as new snapshots arrive
+type AlertEngine struct {
+	rules    []AlertRule
+	previous map[string]Odds
+}
+
+// Create an alert engine for a set of rules
+func newAlertEngine(rules ...AlertRule) *AlertEngine {
+	return &AlertEngine{rules: rules}
+}
+
+// Evaluate every rule against the fixtures in a new snapshot. Only fixtures
+// that were also present in the previous snapshot are evaluated, so the first
+// snapshot never raises alerts. Alerts are ordered by game ID, then rule.
+func (e *AlertEngine) Evaluate(snapshot Snapshot) []Alert {
+	sports := fixtureSports(snapshot.Bookmakers)
+	current := findBestOdds(snapshot.Bookmakers)
+	for gameID, odds := range current {
+		current[gameID] = oddsForSport(odds, sports[gameID])
+	}
+	previous := e.previous
+	e.previous = current
+
+	gameIDs := make([]string, 0, len(current))
+	for gameID := range current {
+		if _, exists := previous[gameID]; exists {
+			gameIDs = append(gameIDs, gameID)
+		}
+	}
+	sort.Strings(gameIDs)
+
+	var alerts []Alert
+	for _, gameID := range gameIDs {
+		for _, rule := range e.rules {
+			if rule.Match(sports[gameID], previous[gameID], current[gameID]) {
+				alerts = append(alerts, Alert{
+					Rule:     rule.Name,
+					GameID:   gameID,
+					Sport:    sports[gameID],
+					Previous: previous[gameID],
+					Current:  current[gameID],
+					At:       snapshot.TakenAt,
+				})
+			}
+		}
+	}
+	return alerts
+}
+
+// Create a rule that fires when a fixture's arbitrage percentage drops below
+// threshold. Invalid odds have no meaningful percentage: the rule never fires
+// on them and treats a fixture moving from invalid to valid odds below the
+// threshold as crossing it.
+func arbitrageBelowRule(threshold float64) AlertRule {
+	below := func(sport string, odds Odds) bool {
+		return checkOdds(odds, sport) == nil && calculateArbitragePercentage(odds) < threshold
+	}
+	return AlertRule{
+		Name: "arbitrage-below",
+		Match: func(sport string, previous, current Odds) bool {
+			return !below(sport, previous) && below(sport, current)
+		},
+	}
+}
+
+// Print alerts, one per line
+func printAlerts(w io.Writer, alerts []Alert, out outputOptions) {
+	for _, alert := range alerts {
+		fmt.Fprintf(w, "Alert %s: game %s arbitrage percentage %.*f%% -> %.*f%%\n", alert.Rule, alert.GameID,
+			out.Precision, calculateArbitragePercentage(alert.Previous)*100, out.Precision, calculateArbitragePercentage(alert.Current)*100)
+	}
+}
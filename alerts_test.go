@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArbitrageBelowRule(t *testing.T) {
+	rule := arbitrageBelowRule(1)
+	tests := []struct {
+		name              string
+		sport             string
+		previous, current Odds
+		want              bool
+	}{
+		{name: "crosses below", previous: Odds{Win: 2, Draw: 3, Lose: 3}, current: Odds{Win: 3, Draw: 4, Lose: 4}, want: true},
+		{name: "stays below", previous: Odds{Win: 3, Draw: 4, Lose: 4}, current: Odds{Win: 3, Draw: 4.5, Lose: 4}},
+		{name: "rises above", previous: Odds{Win: 3, Draw: 4, Lose: 4}, current: Odds{Win: 2, Draw: 3, Lose: 3}},
+		{name: "stays above", previous: Odds{Win: 2, Draw: 3, Lose: 3}, current: Odds{Win: 2.1, Draw: 3, Lose: 3}},
+		{name: "invalid current odds", previous: Odds{Win: 2, Draw: 3, Lose: 3}, current: Odds{Win: 2, Draw: 3, Lose: -1}},
+		{name: "invalid previous odds", previous: Odds{Win: 2, Draw: 3, Lose: -1}, current: Odds{Win: 3, Draw: 4, Lose: 4}, want: true},
+		{name: "two-way sport", sport: "tennis", previous: Odds{Win: 1.8, Lose: 1.9}, current: Odds{Win: 2.1, Lose: 2.1}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rule.Match(tt.sport, tt.previous, tt.current); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertEngineCrossingBelowOne(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	engine := newAlertEngine(arbitrageBelowRule(1))
+	snapshots := []Snapshot{
+		oddsSnapshot(start, Odds{Win: 2, Draw: 3, Lose: 3.5}),
+		oddsSnapshot(start.Add(time.Minute), Odds{Win: 2, Draw: 3, Lose: 3.5}, Odds{Win: 2.2, Draw: 3.2, Lose: 3}),
+		oddsSnapshot(start.Add(2*time.Minute), Odds{Win: 2, Draw: 4, Lose: 4}, Odds{Win: 2.2, Draw: 3.2, Lose: 3}),
+	}
+	wantCounts := []int{0, 0, 1}
+	for i, snapshot := range snapshots {
+		alerts := engine.Evaluate(snapshot)
+		if len(alerts) != wantCounts[i] {
+			t.Fatalf("snapshot %d: got %d alerts, want %d: %+v", i, len(alerts), wantCounts[i], alerts)
+		}
+		for _, alert := range alerts {
+			if alert.GameID != "g" || alert.Rule != "arbitrage-below" || !alert.At.Equal(snapshot.TakenAt) {
+				t.Errorf("snapshot %d: unexpected alert %+v", i, alert)
+			}
+			if calculateArbitragePercentage(alert.Previous) < 1 || calculateArbitragePercentage(alert.Current) >= 1 {
+				t.Errorf("snapshot %d: alert %+v did not cross below 1", i, alert)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import "sort"
+
+// Select the opportunities to take with a limited bankroll, aiming to maximize
+// total guaranteed profit. Opportunities are considered in order of profit per
+// unit of capital and taken whenever they still fit. This greedy ordering is a
+// heuristic rather than an exact knapsack solution, so it can miss a better
+// combination when a large opportunity would use the bankroll more fully.
+func selectOpportunities(opps []ArbitrageOpportunity, bankroll float64) []ArbitrageOpportunity {
+	candidates := make([]ArbitrageOpportunity, 0, len(opps))
+	for _, opp := range opps {
+		if opp.TotalBet > 0 && opp.GuaranteedProfit > 0 {
+			candidates = append(candidates, opp)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].GuaranteedProfit/candidates[i].TotalBet > candidates[j].GuaranteedProfit/candidates[j].TotalBet
+	})
+
+	var selected []ArbitrageOpportunity
+	remaining := bankroll
+	for _, opp := range candidates {
+		if opp.TotalBet <= remaining {
+			selected = append(selected, opp)
+			remaining -= opp.TotalBet
+		}
+	}
+	return selected
+}
+
+// Sum the guaranteed profit and capital used by a set of opportunities
+func selectionTotals(opps []ArbitrageOpportunity) (profit, capital float64) {
+	for _, opp := range opps {
+		profit += opp.GuaranteedProfit
+		capital += opp.TotalBet
+	}
+	return profit, capital
+}
+
+// Keep at most max opportunities, preferring the most profitable, and return
+// how many were left out. A max of zero keeps every opportunity. The kept
+// opportunities are in order of decreasing guaranteed profit.
+func limitOpportunities(opps []ArbitrageOpportunity, max int) ([]ArbitrageOpportunity, int) {
+	if max <= 0 || len(opps) <= max {
+		return opps, 0
+	}
+	sorted := append([]ArbitrageOpportunity(nil), opps...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GuaranteedProfit > sorted[j].GuaranteedProfit
+	})
+	return sorted[:max], len(opps) - max
+}
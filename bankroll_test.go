@@ -0,0 +1,76 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Collect the game IDs of opportunities in order
+func opportunityGameIDs(opps []ArbitrageOpportunity) []string {
+	var ids []string
+	for _, opp := range opps {
+		ids = append(ids, opp.GameID)
+	}
+	return ids
+}
+
+func TestSelectOpportunities(t *testing.T) {
+	opps := []ArbitrageOpportunity{
+		{GameID: "big", TotalBet: 100, GuaranteedProfit: 3},      // 3%
+		{GameID: "efficient", TotalBet: 50, GuaranteedProfit: 2}, // 4%
+		{GameID: "small", TotalBet: 20, GuaranteedProfit: 0.5},   // 2.5%
+		{GameID: "loss", TotalBet: 10, GuaranteedProfit: -1},
+		{GameID: "empty", TotalBet: 0, GuaranteedProfit: 1},
+	}
+	tests := []struct {
+		name       string
+		bankroll   float64
+		want       []string
+		wantProfit float64
+		wantUsed   float64
+	}{
+		{name: "tight bankroll prefers profit per unit", bankroll: 100, want: []string{"efficient", "small"}, wantProfit: 2.5, wantUsed: 70},
+		{name: "room for everything", bankroll: 1000, want: []string{"efficient", "big", "small"}, wantProfit: 5.5, wantUsed: 170},
+		{name: "only the small one fits", bankroll: 30, want: []string{"small"}, wantProfit: 0.5, wantUsed: 20},
+		{name: "nothing fits", bankroll: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := selectOpportunities(opps, tt.bankroll)
+			if got := opportunityGameIDs(selected); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selected %v, want %v", got, tt.want)
+			}
+			profit, used := selectionTotals(selected)
+			if profit != tt.wantProfit || used != tt.wantUsed {
+				t.Errorf("totals = %v profit on %v, want %v on %v", profit, used, tt.wantProfit, tt.wantUsed)
+			}
+		})
+	}
+}
+
+func TestLimitOpportunities(t *testing.T) {
+	opps := []ArbitrageOpportunity{
+		{GameID: "a", GuaranteedProfit: 1},
+		{GameID: "b", GuaranteedProfit: 3},
+		{GameID: "c", GuaranteedProfit: 2},
+	}
+	tests := []struct {
+		max         int
+		want        []string
+		wantOmitted int
+	}{
+		{max: 0, want: []string{"a", "b", "c"}},
+		{max: 5, want: []string{"a", "b", "c"}},
+		{max: 2, want: []string{"b", "c"}, wantOmitted: 1},
+		{max: 1, want: []string{"b"}, wantOmitted: 2},
+	}
+	for _, tt := range tests {
+		kept, omitted := limitOpportunities(opps, tt.max)
+		if got := opportunityGameIDs(kept); !reflect.DeepEqual(got, tt.want) || omitted != tt.wantOmitted {
+			t.Errorf("limitOpportunities(%d) = %v, %d omitted, want %v, %d", tt.max, got, omitted, tt.want, tt.wantOmitted)
+		}
+	}
+	if opportunityGameIDs(opps)[0] != "a" {
+		t.Error("limitOpportunities reordered its input")
+	}
+}
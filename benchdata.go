@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Define the structure for a standardized benchmark dataset. The seed is
+// fixed so every machine generates byte-identical files from a preset.
+type benchmarkPreset struct {
+	Bookmakers int
+	Games      int
+	Seed       int64
+}
+
+// Standard benchmark datasets by name. Changing a preset's seed or size
+// changes its files, so existing presets must stay as they are and new sizes
+// get new names.
+var benchmarkPresets = map[string]benchmarkPreset{
+	"small":  {Bookmakers: 10, Games: 100, Seed: 1001},
+	"medium": {Bookmakers: 50, Games: 1000, Seed: 1002},
+	"large":  {Bookmakers: 100, Games: 10000, Seed: 1003},
+}
+
+// Return the benchmark preset names in sorted order
+func benchmarkPresetNames() []string {
+	names := make([]string, 0, len(benchmarkPresets))
+	for name := range benchmarkPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write the named benchmark datasets to files named benchmark-<preset>.json,
+// or every preset when names is "all". Names are separated by commas.
+func writeBenchmarkData(names string, limitMB uint64) ([]string, error) {
+	selected := strings.Split(names, ",")
+	if names == "all" {
+		selected = benchmarkPresetNames()
+	}
+	var files []string
+	for _, name := range selected {
+		name = strings.TrimSpace(name)
+		preset, exists := benchmarkPresets[name]
+		if !exists {
+			return files, fmt.Errorf("unknown benchmark preset %q, expected %s or all", name, strings.Join(benchmarkPresetNames(), ", "))
+		}
+		if err := checkGenerationSize(preset.Bookmakers, preset.Games, limitMB); err != nil {
+			return files, fmt.Errorf("preset %s: %w", name, err)
+		}
+		filename := fmt.Sprintf("benchmark-%s.json", name)
+		bookmakers := generateBookmakersWithSeed(preset.Bookmakers, preset.Games, preset.Seed, NamesRandom)
+		if err := saveBookmakers(bookmakers, filename, retryPolicy{}); err != nil {
+			return files, err
+		}
+		files = append(files, filename)
+	}
+	return files, nil
+}
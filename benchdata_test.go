@@ -0,0 +1,65 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Run the test from a new temporary directory, since benchmark datasets are
+// written to the working directory
+func chdirTemp(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// Return the SHA-256 of a file as hex
+func fileHash(t *testing.T, filename string) string {
+	t.Helper()
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+func TestBenchmarkPresetHashIsStable(t *testing.T) {
+	chdirTemp(t)
+	// Changing this hash breaks comparisons against published benchmark runs
+	const want = "1ba05161c9ba92c103b85f441e4c8e1e1caec410feb726964d5b061c044a238b"
+	for run := 0; run < 2; run++ {
+		files, err := writeBenchmarkData("small", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(files, []string{"benchmark-small.json"}) {
+			t.Fatalf("wrote %v, want benchmark-small.json", files)
+		}
+		if got := fileHash(t, files[0]); got != want {
+			t.Errorf("run %d: benchmark-small.json hash = %s, want %s", run, got, want)
+		}
+	}
+}
+
+func TestWriteBenchmarkDataErrors(t *testing.T) {
+	chdirTemp(t)
+	if _, err := writeBenchmarkData("small,huge", 0); err == nil || !strings.Contains(err.Error(), `unknown benchmark preset "huge"`) {
+		t.Errorf("unknown preset error = %v", err)
+	}
+	if _, err := writeBenchmarkData("large", 1); err == nil || !strings.Contains(err.Error(), "preset large") {
+		t.Errorf("oversized preset error = %v", err)
+	}
+	if got, want := benchmarkPresetNames(), []string{"large", "medium", "small"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("benchmarkPresetNames() = %v, want %v", got, want)
+	}
+}
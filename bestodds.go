@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Define how best odds are aggregated across bookmakers. All strategies
+// produce identical results, including which bookmaker wins a tie.
+type BestOddsStrategy int
+
+const (
+	// BestOddsMap looks up each game in a map as it is seen, which suits
+	// datasets with many distinct fixtures
+	BestOddsMap BestOddsStrategy = iota
+	// BestOddsSorted sorts every quote by game ID and aggregates runs of the
+	// same game, avoiding per-quote map lookups
+	BestOddsSorted
+	// BestOddsParallel splits the bookmakers across one goroutine per CPU
+	// and merges their partial results, which suits very large datasets
+	BestOddsParallel
+	// BestOddsAuto picks one of the others from the size of the data, see
+	// chooseBestOddsStrategy
+	BestOddsAuto
+)
+
+// Return the name of a best odds strategy
+func (s BestOddsStrategy) String() string {
+	switch s {
+	case BestOddsMap:
+		return "map"
+	case BestOddsSorted:
+		return "sorted"
+	case BestOddsParallel:
+		return "parallel"
+	case BestOddsAuto:
+		return "auto"
+	}
+	return fmt.Sprintf("BestOddsStrategy(%d)", int(s))
+}
+
+// Parse a best odds strategy name
+func parseBestOddsStrategy(name string) (BestOddsStrategy, error) {
+	for _, strategy := range []BestOddsStrategy{BestOddsMap, BestOddsSorted, BestOddsParallel, BestOddsAuto} {
+		if strategy.String() == name {
+			return strategy, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown best odds strategy %q, expected map, sorted, parallel or auto", name)
+}
+
+// Number of quotes from which the parallel strategy is chosen on machines
+// with more than one CPU. Below it the goroutines and the merge cost more
+// than they save.
+const parallelBestOddsQuotes = 1 << 20
+
+// Choose the best odds strategy for a dataset from its number of quotes,
+// without aggregating it. BenchmarkFindBestOdds* has the map ahead of the
+// sorted strategy for many fixtures and for few fixtures quoted by many
+// bookmakers alike, since sorting every quote costs more than the lookups it
+// saves, so the sorted strategy is never chosen and stays available through
+// -best-odds.
+func chooseBestOddsStrategy(bookmakers []Bookmaker, cpus int) BestOddsStrategy {
+	quotes := 0
+	for _, bookmaker := range bookmakers {
+		quotes += len(bookmaker.Games)
+	}
+	if quotes >= parallelBestOddsQuotes && cpus > 1 {
+		return BestOddsParallel
+	}
+	return BestOddsMap
+}
+
+// Find the best odds with their sources using the given strategy. Only the
+// parallel strategy stops early when ctx is done, returning the best odds
+// among the bookmakers it got through.
+func findBestOddsWithStrategy(ctx context.Context, bookmakers []Bookmaker, strategy BestOddsStrategy) map[string]BestOddsWithSource {
+	if strategy == BestOddsAuto {
+		strategy = chooseBestOddsStrategy(bookmakers, runtime.NumCPU())
+	}
+	switch strategy {
+	case BestOddsSorted:
+		return findBestOddsWithSourceSorted(bookmakers)
+	case BestOddsParallel:
+		return findBestOddsWithSourceParallel(ctx, bookmakers, runtime.NumCPU())
+	}
+	return findBestOddsWithSource(bookmakers)
+}
+
+// Define the structure for one bookmaker's quote for a game
+type gameQuote struct {
+	gameID    string
+	bookmaker string
+	odds      Odds
+}
+
+// Find the best odds for each game with their sources by sorting all quotes
+// by game ID. The sort is stable, so within a game quotes keep the
+// bookmakers' order and ties go to the bookmaker listed first, exactly as in
+// findBestOddsWithSource.
+func findBestOddsWithSourceSorted(bookmakers []Bookmaker) map[string]BestOddsWithSource {
+	var quotes []gameQuote
+	for _, bookmaker := range bookmakers {
+		for _, game := range bookmaker.Games {
+			if !game.Available {
+				continue
+			}
+			quotes = append(quotes, gameQuote{gameID: game.ID, bookmaker: bookmaker.Name, odds: game.Odds})
+		}
+	}
+	sort.SliceStable(quotes, func(i, j int) bool {
+		return quotes[i].gameID < quotes[j].gameID
+	})
+
+	bestOdds := make(map[string]BestOddsWithSource)
+	for start := 0; start < len(quotes); {
+		first := quotes[start]
+		best := BestOddsWithSource{
+			Odds:       first.odds,
+			WinSource:  first.bookmaker,
+			DrawSource: first.bookmaker,
+			LoseSource: first.bookmaker,
+		}
+		end := start + 1
+		for ; end < len(quotes) && quotes[end].gameID == first.gameID; end++ {
+			quote := quotes[end]
+			if quote.odds.Win > best.Odds.Win {
+				best.Odds.Win = quote.odds.Win
+				best.WinSource = quote.bookmaker
+			}
+			if quote.odds.Draw > best.Odds.Draw {
+				best.Odds.Draw = quote.odds.Draw
+				best.DrawSource = quote.bookmaker
+			}
+			if quote.odds.Lose > best.Odds.Lose {
+				best.Odds.Lose = quote.odds.Lose
+				best.LoseSource = quote.bookmaker
+			}
+		}
+		bestOdds[first.gameID] = best
+		start = end
+	}
+	return bestOdds
+}
+
+// Define the structure for a partial best odds result that remembers the
+// position of the bookmaker offering each leg, so ties can be broken the
+// same way as in a sequential scan
+type indexedBestOdds struct {
+	best                           BestOddsWithSource
+	winIndex, drawIndex, loseIndex int
+}
+
+// Take a leg from other if it has better odds, or equal odds from a
+// bookmaker listed earlier
+func betterLeg(odds, otherOdds float64, index, otherIndex int) bool {
+	return otherOdds > odds || (otherOdds == odds && otherIndex < index)
+}
+
+// Merge a partial result into the combined best odds for a game
+func (b *indexedBestOdds) merge(other indexedBestOdds) {
+	if betterLeg(b.best.Odds.Win, other.best.Odds.Win, b.winIndex, other.winIndex) {
+		b.best.Odds.Win, b.best.WinSource, b.winIndex = other.best.Odds.Win, other.best.WinSource, other.winIndex
+	}
+	if betterLeg(b.best.Odds.Draw, other.best.Odds.Draw, b.drawIndex, other.drawIndex) {
+		b.best.Odds.Draw, b.best.DrawSource, b.drawIndex = other.best.Odds.Draw, other.best.DrawSource, other.drawIndex
+	}
+	if betterLeg(b.best.Odds.Lose, other.best.Odds.Lose, b.loseIndex, other.loseIndex) {
+		b.best.Odds.Lose, b.best.LoseSource, b.loseIndex = other.best.Odds.Lose, other.best.LoseSource, other.loseIndex
+	}
+}
+
+// Find the best odds for each game with their sources using several
+// goroutines. Each goroutine scans a contiguous range of bookmakers into a
+// map of its own, so the hot loop shares no state, and only the final merge
+// into the combined map happens under a single lock. Every leg carries the
+// index of the bookmaker offering it and equal odds resolve to the lower
+// index, so the result is identical to findBestOddsWithSource whichever
+// goroutine finishes first. Each goroutine checks ctx before every
+// bookmaker and stops once it is done, leaving a partial result.
+func findBestOddsWithSourceParallel(ctx context.Context, bookmakers []Bookmaker, workers int) map[string]BestOddsWithSource {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(bookmakers) {
+		workers = len(bookmakers)
+	}
+	chunk := (len(bookmakers) + workers - 1) / max(workers, 1)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	combined := make(map[string]indexedBestOdds)
+	for start := 0; start < len(bookmakers); start += chunk {
+		end := min(start+chunk, len(bookmakers))
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			local := make(map[string]indexedBestOdds)
+			for i := start; i < end && ctx.Err() == nil; i++ {
+				bookmaker := bookmakers[i]
+				for _, game := range bookmaker.Games {
+					if !game.Available {
+						continue
+					}
+					quote := indexedBestOdds{
+						best: BestOddsWithSource{
+							Odds:       game.Odds,
+							WinSource:  bookmaker.Name,
+							DrawSource: bookmaker.Name,
+							LoseSource: bookmaker.Name,
+						},
+						winIndex:  i,
+						drawIndex: i,
+						loseIndex: i,
+					}
+					current, exists := local[game.ID]
+					if !exists {
+						local[game.ID] = quote
+						continue
+					}
+					current.merge(quote)
+					local[game.ID] = current
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for gameID, partial := range local {
+				current, exists := combined[gameID]
+				if !exists {
+					combined[gameID] = partial
+					continue
+				}
+				current.merge(partial)
+				combined[gameID] = current
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	bestOdds := make(map[string]BestOddsWithSource, len(combined))
+	for gameID, indexed := range combined {
+		bestOdds[gameID] = indexed.best
+	}
+	return bestOdds
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+)
+
+// Build bookmakers that each quote the same fixtures, in a shuffled order,
+// with rounded odds so ties between bookmakers are common. Every tenth quote
+// is suspended.
+func sharedFixtureBookmakers(numBookmakers, numFixtures int, seed int64) []Bookmaker {
+	rng := rand.New(rand.NewSource(seed))
+	bookmakers := make([]Bookmaker, numBookmakers)
+	for i := range bookmakers {
+		games := make([]Game, numFixtures)
+		for j, k := range rng.Perm(numFixtures) {
+			games[j] = Game{
+				ID:        fmt.Sprintf("fixture-%06d", k),
+				Odds:      Odds{Win: 1.5 + float64(rng.Intn(20))/10, Draw: 2.5 + float64(rng.Intn(20))/10, Lose: 1.5 + float64(rng.Intn(30))/10},
+				Available: rng.Intn(10) != 0,
+			}
+		}
+		bookmakers[i] = Bookmaker{Name: fmt.Sprintf("book-%04d", i), Games: games}
+	}
+	return bookmakers
+}
+
+// Shapes of data the best odds strategies are compared on
+var bestOddsShapes = []struct {
+	name                 string
+	bookmakers, fixtures int
+}{
+	{name: "many-fixtures", bookmakers: 20, fixtures: 20000},
+	{name: "few-fixtures", bookmakers: 2000, fixtures: 20},
+}
+
+func TestBestOddsStrategiesAgree(t *testing.T) {
+	for _, shape := range bestOddsShapes {
+		t.Run(shape.name, func(t *testing.T) {
+			bookmakers := sharedFixtureBookmakers(shape.bookmakers, shape.fixtures, 1)
+			want := findBestOddsWithSource(bookmakers)
+			for _, strategy := range []BestOddsStrategy{BestOddsSorted, BestOddsParallel, BestOddsAuto} {
+				got := findBestOddsWithStrategy(context.Background(), bookmakers, strategy)
+				if len(got) != len(want) {
+					t.Fatalf("%v: %d fixtures, want %d", strategy, len(got), len(want))
+				}
+				for gameID, best := range want {
+					if got[gameID] != best {
+						t.Fatalf("%v: %s = %+v, want %+v", strategy, gameID, got[gameID], best)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestChooseBestOddsStrategy(t *testing.T) {
+	tests := []struct {
+		name       string
+		bookmakers []Bookmaker
+		want       BestOddsStrategy
+	}{
+		{name: "empty", want: BestOddsMap},
+		{name: "many fixtures", bookmakers: sharedFixtureBookmakers(20, 2000, 1), want: BestOddsMap},
+		{name: "few fixtures quoted by many bookmakers", bookmakers: sharedFixtureBookmakers(500, 5, 1), want: BestOddsMap},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chooseBestOddsStrategy(tt.bookmakers, runtime.NumCPU()); got != tt.want {
+				t.Errorf("chooseBestOddsStrategy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	// Very large datasets go parallel when there is more than one CPU. Only
+	// the number of games counts, so the bookmakers can share them.
+	games := make([]Game, parallelBestOddsQuotes/8)
+	large := make([]Bookmaker, 8)
+	for i := range large {
+		large[i].Games = games
+	}
+	if got := chooseBestOddsStrategy(large, 4); got != BestOddsParallel {
+		t.Errorf("large dataset on 4 CPUs: got %v, want parallel", got)
+	}
+	if got := chooseBestOddsStrategy(large, 1); got == BestOddsParallel {
+		t.Errorf("large dataset on 1 CPU: got parallel")
+	}
+}
+
+func TestParseBestOddsStrategy(t *testing.T) {
+	for _, strategy := range []BestOddsStrategy{BestOddsMap, BestOddsSorted, BestOddsParallel, BestOddsAuto} {
+		got, err := parseBestOddsStrategy(strategy.String())
+		if err != nil || got != strategy {
+			t.Errorf("parseBestOddsStrategy(%q) = %v, %v", strategy.String(), got, err)
+		}
+	}
+	if _, err := parseBestOddsStrategy("fastest"); err == nil {
+		t.Error("parseBestOddsStrategy(\"fastest\") succeeded, want an error")
+	}
+}
+
+func benchmarkFindBestOdds(b *testing.B, strategy BestOddsStrategy) {
+	for _, shape := range bestOddsShapes {
+		bookmakers := sharedFixtureBookmakers(shape.bookmakers, shape.fixtures, 1)
+		b.Run(shape.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				findBestOddsWithStrategy(context.Background(), bookmakers, strategy)
+			}
+		})
+	}
+}
+
+func BenchmarkFindBestOddsMap(b *testing.B)      { benchmarkFindBestOdds(b, BestOddsMap) }
+func BenchmarkFindBestOddsSorted(b *testing.B)   { benchmarkFindBestOdds(b, BestOddsSorted) }
+func BenchmarkFindBestOddsParallel(b *testing.B) { benchmarkFindBestOdds(b, BestOddsParallel) }
+func BenchmarkFindBestOddsAuto(b *testing.B)     { benchmarkFindBestOdds(b, BestOddsAuto) }
+
+// Run with -race: the workers must not share state outside the final merge,
+// and whichever finishes first the result must match the sequential scan
+func TestFindBestOddsWithSourceParallelMatchesSequential(t *testing.T) {
+	bookmakers := sharedFixtureBookmakers(200, 1000, 2)
+	want := findBestOddsWithSource(bookmakers)
+	for _, workers := range []int{1, 2, 7, 16, 500} {
+		t.Run(fmt.Sprint(workers), func(t *testing.T) {
+			got := findBestOddsWithSourceParallel(context.Background(), bookmakers, workers)
+			if len(got) != len(want) {
+				t.Fatalf("%d fixtures, want %d", len(got), len(want))
+			}
+			for gameID, best := range want {
+				if got[gameID] != best {
+					t.Fatalf("%s = %+v, want %+v", gameID, got[gameID], best)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Define the structure for how sharp a bookmaker's prices are across the dataset
+type BookmakerStats struct {
+	Name             string  `json:"name"`
+	Games            int     `json:"games"`
+	AverageOverround float64 `json:"average_overround"`
+	Legs             int     `json:"legs"`
+	BestLegs         int     `json:"best_legs"`
+	BestLegShare     float64 `json:"best_leg_share"`
+}
+
+// Calculate the probability implied by decimal odds
+func impliedProbability(odds float64) float64 {
+	return 1 / odds
+}
+
+// Calculate a bookmaker's margin on a set of odds: how far the implied
+// probabilities of all outcomes sum above 1
+func overround(odds Odds) float64 {
+	return calculateArbitragePercentage(odds) - 1
+}
+
+// Aggregate per-bookmaker pricing statistics, ranked with the bookmaker most
+// often offering the best leg first and lower average overround breaking ties.
+// Tied best prices are credited to the bookmaker listed first, as in
+// findBestOddsWithSource.
+func bookmakerReport(bookmakers []Bookmaker) []BookmakerStats {
+	index := make(map[string]int, len(bookmakers))
+	var stats []BookmakerStats
+
+	for _, bookmaker := range bookmakers {
+		i, exists := index[bookmaker.Name]
+		if !exists {
+			index[bookmaker.Name] = len(stats)
+			stats = append(stats, BookmakerStats{Name: bookmaker.Name})
+			i = len(stats) - 1
+		}
+		for _, game := range bookmaker.Games {
+			odds := oddsForSport(game.Odds, game.Sport)
+			if checkOdds(odds, game.Sport) != nil {
+				continue
+			}
+			stats[i].Games++
+			stats[i].AverageOverround += overround(odds)
+			stats[i].Legs += 2
+			if odds.Draw != 0 {
+				stats[i].Legs++
+			}
+		}
+	}
+
+	sports := fixtureSports(bookmakers)
+	for gameID, best := range findBestOddsWithSource(bookmakers) {
+		// Fixtures left out of the leg counts above earn no best legs either
+		if checkOdds(oddsForSport(best.Odds, sports[gameID]), sports[gameID]) != nil {
+			continue
+		}
+		sources := []string{best.WinSource, best.LoseSource}
+		if outcomesForSport(sports[gameID]).HasDraw {
+			sources = append(sources, best.DrawSource)
+		}
+		for _, source := range sources {
+			stats[index[source]].BestLegs++
+		}
+	}
+
+	for i := range stats {
+		if stats[i].Games > 0 {
+			stats[i].AverageOverround /= float64(stats[i].Games)
+		}
+		if stats[i].Legs > 0 {
+			stats[i].BestLegShare = float64(stats[i].BestLegs) / float64(stats[i].Legs)
+		}
+	}
+	sort.SliceStable(stats, func(a, b int) bool {
+		if stats[a].BestLegShare != stats[b].BestLegShare {
+			return stats[a].BestLegShare > stats[b].BestLegShare
+		}
+		return stats[a].AverageOverround < stats[b].AverageOverround
+	})
+	return stats
+}
+
+// Print bookmaker statistics as a ranked table
+func printBookmakerReport(w io.Writer, stats []BookmakerStats) {
+	fmt.Fprintf(w, "%-4s %-30s %8s %10s %10s\n", "Rank", "Bookmaker", "Games", "Overround", "Best legs")
+	for i, stat := range stats {
+		fmt.Fprintf(w, "%-4d %-30s %8d %9.2f%% %9.2f%%\n", i+1, stat.Name, stat.Games, stat.AverageOverround*100, stat.BestLegShare*100)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestOverround(t *testing.T) {
+	tests := []struct {
+		odds Odds
+		want float64
+	}{
+		{odds: Odds{Win: 3, Draw: 3, Lose: 3}, want: 0},
+		{odds: Odds{Win: 2, Draw: 4, Lose: 4}, want: 0},
+		{odds: Odds{Win: 2, Draw: 2, Lose: 4}, want: 0.25},
+		{odds: Odds{Win: 1.8, Lose: 1.8}, want: 2/1.8 - 1},
+	}
+	for _, tt := range tests {
+		if got := overround(tt.odds); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("overround(%+v) = %v, want %v", tt.odds, got, tt.want)
+		}
+	}
+	if got := impliedProbability(4); got != 0.25 {
+		t.Errorf("impliedProbability(4) = %v, want 0.25", got)
+	}
+}
+
+func TestBookmakerReportRanksSharpestFirst(t *testing.T) {
+	bookmakers := []Bookmaker{
+		{Name: "soft", Games: []Game{
+			{ID: "g1", Odds: Odds{Win: 1.9, Draw: 3.2, Lose: 3.6}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 1.7, Draw: 3.3, Lose: 4.0}, Available: true},
+		}},
+		{Name: "sharp", Games: []Game{
+			{ID: "g1", Odds: Odds{Win: 2.1, Draw: 3.5, Lose: 4.0}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 1.9, Draw: 3.6, Lose: 4.5}, Available: true},
+			// Invalid odds are left out of the averages
+			{ID: "g3", Odds: Odds{Win: 0.5, Draw: 3, Lose: 3}, Available: true},
+		}},
+		{Name: "middle", Games: []Game{
+			{ID: "g1", Odds: Odds{Win: 2.0, Draw: 3.4, Lose: 3.8}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 1.8, Draw: 3.5, Lose: 4.2}, Available: true},
+		}},
+	}
+	stats := bookmakerReport(bookmakers)
+	if len(stats) != 3 {
+		t.Fatalf("got %d bookmakers, want 3", len(stats))
+	}
+	sharp := stats[0]
+	if sharp.Name != "sharp" || sharp.Games != 2 || sharp.Legs != 6 || sharp.BestLegs != 6 || sharp.BestLegShare != 1 {
+		t.Errorf("first ranked = %+v, want sharp with every best leg", sharp)
+	}
+	wantOverround := (overround(bookmakers[1].Games[0].Odds) + overround(bookmakers[1].Games[1].Odds)) / 2
+	if math.Abs(sharp.AverageOverround-wantOverround) > 1e-9 {
+		t.Errorf("sharp average overround = %v, want %v", sharp.AverageOverround, wantOverround)
+	}
+	// Neither other bookmaker offers a best leg, so the lower margin ranks higher
+	if stats[1].Name != "middle" || stats[2].Name != "soft" {
+		t.Errorf("ranking = %s, %s, %s, want sharp, middle, soft", stats[0].Name, stats[1].Name, stats[2].Name)
+	}
+
+	var buf bytes.Buffer
+	printBookmakerReport(&buf, stats)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "1    sharp ") || !strings.HasSuffix(lines[1], " 100.00%") {
+		t.Errorf("report =\n%s", buf.String())
+	}
+}
+
+func TestBookmakerReportCreditsTiesToFirstListed(t *testing.T) {
+	odds := Odds{Win: 2, Draw: 3.5, Lose: 4}
+	bookmakers := []Bookmaker{
+		{Name: "first", Games: []Game{{ID: "g1", Odds: odds, Available: true}}},
+		{Name: "second", Games: []Game{{ID: "g1", Odds: odds, Available: true}}},
+	}
+	stats := bookmakerReport(bookmakers)
+	if stats[0].Name != "first" || stats[0].BestLegs != 3 || stats[1].BestLegs != 0 {
+		t.Errorf("bookmakerReport() = %+v, want every tied leg credited to first", stats)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Define the structure for a bet that has been placed
+type PlacedBet struct {
+	GameID    string    `json:"game_id"`
+	Bookmaker string    `json:"bookmaker"`
+	Outcome   string    `json:"outcome"`
+	Odds      float64   `json:"odds"`
+	Stake     float64   `json:"stake"`
+	PlacedAt  time.Time `json:"placed_at"`
+}
+
+// Define the structure for a placed bet compared against the closing line
+type CLVResult struct {
+	Bet         PlacedBet `json:"bet"`
+	ClosingOdds float64   `json:"closing_odds"`
+	CLV         float64   `json:"clv"`
+}
+
+// Record placed bets and compare them with closing odds
+type CLVTracker struct {
+	mu   sync.Mutex
+	bets []PlacedBet
+}
+
+// Calculate the closing line value of a bet as a percentage. A positive value
+// means the bet was placed at better odds than the market closed at.
+func closingLineValue(betOdds, closingOdds float64) float64 {
+	return (betOdds/closingOdds - 1) * 100
+}
+
+// Record a placed bet
+func (t *CLVTracker) Record(bet PlacedBet) error {
+	if _, err := oddsForOutcome(Odds{}, bet.Outcome); err != nil {
+		return err
+	}
+	if bet.Odds <= 1 {
+		return fmt.Errorf("bet on game %s has invalid odds %.2f", bet.GameID, bet.Odds)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.bets = append(t.bets, bet)
+	return nil
+}
+
+// Evaluate every recorded bet against the closing snapshot. The closing line
+// is the best price available for the bet's outcome across all bookmakers in
+// the snapshot; bets whose fixture is missing from the snapshot are skipped.
+func (t *CLVTracker) Evaluate(closing Snapshot) []CLVResult {
+	bestOdds := findBestOdds(closing.Bookmakers)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var results []CLVResult
+	for _, bet := range t.bets {
+		odds, exists := bestOdds[bet.GameID]
+		if !exists {
+			continue
+		}
+		closingOdds, _ := oddsForOutcome(odds, bet.Outcome)
+		if closingOdds <= 0 {
+			continue
+		}
+		results = append(results, CLVResult{
+			Bet:         bet,
+			ClosingOdds: closingOdds,
+			CLV:         closingLineValue(bet.Odds, closingOdds),
+		})
+	}
+	return results
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestClosingLineValue(t *testing.T) {
+	tests := []struct {
+		betOdds, closingOdds float64
+		want                 float64
+	}{
+		{betOdds: 2.1, closingOdds: 2.0, want: 5},
+		{betOdds: 1.8, closingOdds: 2.0, want: -10},
+		{betOdds: 3.0, closingOdds: 3.0, want: 0},
+		{betOdds: 4.0, closingOdds: 2.5, want: 60},
+	}
+	for _, tt := range tests {
+		if got := closingLineValue(tt.betOdds, tt.closingOdds); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("closingLineValue(%v, %v) = %v, want %v", tt.betOdds, tt.closingOdds, got, tt.want)
+		}
+	}
+}
+
+func TestCLVTrackerRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		bet     PlacedBet
+		wantErr bool
+	}{
+		{name: "valid", bet: PlacedBet{GameID: "g", Outcome: OutcomeDraw, Odds: 3.4}},
+		{name: "unknown outcome", bet: PlacedBet{GameID: "g", Outcome: "over", Odds: 3.4}, wantErr: true},
+		{name: "odds of one", bet: PlacedBet{GameID: "g", Outcome: OutcomeWin, Odds: 1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tracker CLVTracker
+			if err := tracker.Record(tt.bet); (err != nil) != tt.wantErr {
+				t.Errorf("Record() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCLVTrackerEvaluate(t *testing.T) {
+	placedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var tracker CLVTracker
+	bets := []PlacedBet{
+		{GameID: "g1", Bookmaker: "a", Outcome: OutcomeWin, Odds: 2.2, Stake: 10, PlacedAt: placedAt},
+		{GameID: "g1", Bookmaker: "a", Outcome: OutcomeLose, Odds: 3.6, Stake: 10, PlacedAt: placedAt},
+		{GameID: "missing", Bookmaker: "a", Outcome: OutcomeWin, Odds: 2, Stake: 10, PlacedAt: placedAt},
+	}
+	for _, bet := range bets {
+		if err := tracker.Record(bet); err != nil {
+			t.Fatal(err)
+		}
+	}
+	closing := Snapshot{TakenAt: placedAt.Add(time.Hour), Bookmakers: []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.9, Draw: 3.5, Lose: 4}, Available: true}}},
+		{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 3.4, Lose: 4.5}, Available: true}}},
+	}}
+
+	results := tracker.Evaluate(closing)
+	want := []CLVResult{
+		// Closing lines are the best prices across bookmakers
+		{Bet: bets[0], ClosingOdds: 2, CLV: 10},
+		{Bet: bets[1], ClosingOdds: 4.5, CLV: -20},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i := range want {
+		if results[i].Bet != want[i].Bet || results[i].ClosingOdds != want[i].ClosingOdds || math.Abs(results[i].CLV-want[i].CLV) > 1e-9 {
+			t.Errorf("result %d = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import "os"
+
+// ANSI escape sequences used to highlight text output
+const (
+	ansiGreen = "\x1b[32m"
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
+)
+
+// Report whether a file is an interactive terminal rather than a pipe or
+// regular file
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Wrap text in green when profit is positive and red otherwise, if enabled
+func colorProfit(text string, profit float64, enabled bool) string {
+	if !enabled {
+		return text
+	}
+	if profit > 0 {
+		return ansiGreen + text + ansiReset
+	}
+	return ansiRed + text + ansiReset
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestColorProfit(t *testing.T) {
+	tests := []struct {
+		profit  float64
+		enabled bool
+		want    string
+	}{
+		{profit: 5, enabled: true, want: ansiGreen + "profit" + ansiReset},
+		{profit: -1, enabled: true, want: ansiRed + "profit" + ansiReset},
+		{profit: 0, enabled: true, want: ansiRed + "profit" + ansiReset},
+		{profit: 5, want: "profit"},
+	}
+	for _, tt := range tests {
+		if got := colorProfit("profit", tt.profit, tt.enabled); got != tt.want {
+			t.Errorf("colorProfit(%v, %v) = %q, want %q", tt.profit, tt.enabled, got, tt.want)
+		}
+	}
+}
+
+func TestIsTerminalFalseWhenNotATTY(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	defer writer.Close()
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	for name, f := range map[string]*os.File{"pipe": writer, "regular file": file} {
+		if isTerminal(f) {
+			t.Errorf("isTerminal(%s) = true", name)
+		}
+	}
+}
+
+func TestNoColorCodesWithoutATTY(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	// -color only takes effect on a terminal, as run() decides it
+	colorFlag := true
+	out := defaultOutputOptions
+	out.Color = colorFlag && isTerminal(file)
+
+	opportunity := newArbitrageOpportunity("g1", Odds{Win: 2.6, Draw: 3.6, Lose: 4.2}, 100)
+	var buf bytes.Buffer
+	printArbitrageOpportunity(&buf, opportunity, out)
+	data, err := json.Marshal(opportunity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, output := range map[string]string{"text": buf.String(), "JSON": string(data)} {
+		if strings.Contains(output, "\x1b[") {
+			t.Errorf("%s output has ANSI codes: %q", name, output)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import "fmt"
+
+// Assemble a bookmaker from a columnar feed giving parallel arrays of game
+// IDs and home, draw and away odds. Every array must have the same length;
+// draw may be nil for two-way markets, leaving draw odds zero.
+func bookmakerFromColumns(name string, ids []string, home, draw, away []float64) (Bookmaker, error) {
+	if len(home) != len(ids) || len(away) != len(ids) || (draw != nil && len(draw) != len(ids)) {
+		return Bookmaker{}, fmt.Errorf("%w: %s: columns differ in length: %d ids, %d home, %d draw and %d away odds",
+			ErrParse, name, len(ids), len(home), len(draw), len(away))
+	}
+	bookmaker := Bookmaker{Name: name, Games: make([]Game, 0, len(ids))}
+	for i, id := range ids {
+		game := Game{ID: id, Odds: Odds{Win: home[i], Lose: away[i]}, Available: true}
+		if draw != nil {
+			game.Odds.Draw = draw[i]
+		}
+		bookmaker.Games = append(bookmaker.Games, game)
+	}
+	return bookmaker, nil
+}
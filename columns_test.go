@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBookmakerFromColumns(t *testing.T) {
+	ids := []string{"g1", "g2"}
+	tests := []struct {
+		name             string
+		home, draw, away []float64
+		want             []Game
+		wantErr          bool
+	}{
+		{name: "three-way", home: []float64{2, 2.5}, draw: []float64{3, 3.2}, away: []float64{4, 2.8}, want: []Game{
+			{ID: "g1", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 2.5, Draw: 3.2, Lose: 2.8}, Available: true},
+		}},
+		{name: "two-way without draws", home: []float64{1.8, 1.9}, away: []float64{2, 1.95}, want: []Game{
+			{ID: "g1", Odds: Odds{Win: 1.8, Lose: 2}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 1.9, Lose: 1.95}, Available: true},
+		}},
+		{name: "short home", home: []float64{2}, draw: []float64{3, 3.2}, away: []float64{4, 2.8}, wantErr: true},
+		{name: "long away", home: []float64{2, 2.5}, draw: []float64{3, 3.2}, away: []float64{4, 2.8, 3}, wantErr: true},
+		{name: "short draw", home: []float64{2, 2.5}, draw: []float64{3}, away: []float64{4, 2.8}, wantErr: true},
+		{name: "empty draw is not nil", home: []float64{2, 2.5}, draw: []float64{}, away: []float64{4, 2.8}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bookmakerFromColumns("feed", ids, tt.home, tt.draw, tt.away)
+			if tt.wantErr {
+				if !errors.Is(err, ErrParse) {
+					t.Errorf("error = %v, want %v", err, ErrParse)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := (Bookmaker{Name: "feed", Games: tt.want}); !reflect.DeepEqual(got, want) {
+				t.Errorf("bookmakerFromColumns() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
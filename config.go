@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Reliability assumed for bookmakers without one configured
+const defaultReliability = 1.0
+
+// Define the structure for per-bookmaker settings
+type BookmakerConfig struct {
+	// Reliability from 0 to 1 of the bookmaker honoring winning bets
+	Reliability *float64 `json:"reliability,omitempty"`
+	// Link template for the bookmaker's game pages, such as
+	// https://{book}/game/{id}
+	LinkTemplate string `json:"link_template,omitempty"`
+	// Currency the bookmaker quotes stakes in, such as EUR
+	Currency string `json:"currency,omitempty"`
+	// Largest total payout the bookmaker accepts on a single bet
+	MaxPayout float64 `json:"max_payout,omitempty"`
+	// Largest stake of the account on a single bet keyed by sport, such as
+	// {"soccer": 500, "default": 200}
+	MaxStakes map[string]float64 `json:"max_stakes,omitempty"`
+	// Smallest stake the bookmaker accepts on a single bet, such as 2
+	MinStake float64 `json:"min_stake,omitempty"`
+}
+
+// Define the structure for the configuration file, keyed by bookmaker name
+type Config struct {
+	Bookmakers map[string]BookmakerConfig `json:"bookmakers"`
+	// Value of each currency in US dollars, replacing the default table
+	Rates staticRates `json:"rates,omitempty"`
+}
+
+// Return the exchange rate source to use, preferring configured rates
+func (cfg Config) rateSource() RateSource {
+	if len(cfg.Rates) > 0 {
+		return cfg.Rates
+	}
+	return defaultRates
+}
+
+// Read the configuration from a JSON file
+func loadConfig(filename string) (Config, error) {
+	var cfg Config
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return cfg, newFileError(filename, err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, &FileError{Path: filename, Err: ErrParse, Cause: err}
+	}
+	return cfg, nil
+}
+
+// Apply configured settings to bookmakers. Bookmakers without a configured
+// reliability keep the one from their data, or are treated as fully reliable
+// when their data has none. A configured link template, currency, payout cap,
+// set of stake limits or minimum stake replaces any from the data.
+func applyConfig(bookmakers []Bookmaker, cfg Config) {
+	for i := range bookmakers {
+		bookmakerCfg := cfg.Bookmakers[bookmakers[i].Name]
+		switch {
+		case bookmakerCfg.Reliability != nil:
+			bookmakers[i].Reliability = *bookmakerCfg.Reliability
+		case bookmakers[i].Reliability == 0:
+			bookmakers[i].Reliability = defaultReliability
+		}
+		if bookmakerCfg.LinkTemplate != "" {
+			bookmakers[i].LinkTemplate = bookmakerCfg.LinkTemplate
+		}
+		if bookmakerCfg.Currency != "" {
+			bookmakers[i].Currency = bookmakerCfg.Currency
+		}
+		if bookmakerCfg.MaxPayout > 0 {
+			bookmakers[i].MaxPayout = bookmakerCfg.MaxPayout
+		}
+		if len(bookmakerCfg.MaxStakes) > 0 {
+			bookmakers[i].MaxStakes = bookmakerCfg.MaxStakes
+		}
+		if bookmakerCfg.MinStake > 0 {
+			bookmakers[i].MinStake = bookmakerCfg.MinStake
+		}
+	}
+}
+
+// Map bookmaker names to their reliability
+func bookmakerReliabilities(bookmakers []Bookmaker) map[string]float64 {
+	reliabilities := make(map[string]float64, len(bookmakers))
+	for _, bookmaker := range bookmakers {
+		reliabilities[bookmaker.Name] = bookmaker.Reliability
+	}
+	return reliabilities
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyConfigReliability(t *testing.T) {
+	low := 0.4
+	bookmakers := []Bookmaker{
+		{Name: "configured", Reliability: 0.9},
+		{Name: "from data", Reliability: 0.7},
+		{Name: "unset"},
+	}
+	applyConfig(bookmakers, Config{Bookmakers: map[string]BookmakerConfig{"configured": {Reliability: &low}}})
+	want := map[string]float64{"configured": 0.4, "from data": 0.7, "unset": defaultReliability}
+	if got := bookmakerReliabilities(bookmakers); len(got) != len(want) {
+		t.Fatalf("bookmakerReliabilities() = %v, want %v", got, want)
+	} else {
+		for name, reliability := range want {
+			if got[name] != reliability {
+				t.Errorf("%s reliability = %v, want %v", name, got[name], reliability)
+			}
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(filename, []byte(`{"bookmakers":{"sketchy":{"reliability":0.3}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadConfig(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reliability := cfg.Bookmakers["sketchy"].Reliability; reliability == nil || *reliability != 0.3 {
+		t.Errorf("sketchy reliability = %v, want 0.3", reliability)
+	}
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("loadConfig() of a missing file succeeded")
+	}
+}
+
+func TestLowReliabilityLegDisqualifies(t *testing.T) {
+	// The sketchy bookmaker offers the best win odds, so the opportunity
+	// depends on it
+	bookmakers := []Bookmaker{
+		{Name: "solid", Games: []Game{{ID: "g1", Odds: Odds{Win: 2.6, Draw: 3.6, Lose: 4.2}, Available: true}}},
+		{Name: "sketchy", Games: []Game{{ID: "g1", Odds: Odds{Win: 3.3, Draw: 3.3, Lose: 3.3}, Available: true}}},
+	}
+	low := 0.5
+	applyConfig(bookmakers, Config{Bookmakers: map[string]BookmakerConfig{"sketchy": {Reliability: &low}}})
+
+	detected, _ := detectArbitrageOpportunities(context.Background(), bookmakers, scanOptions{TotalBet: 100, ArbThreshold: 1})
+	if len(detected) != 1 {
+		t.Fatalf("got %d opportunities, want 1", len(detected))
+	}
+	if detected[0].WinBookmaker != "sketchy" || detected[0].Reliability != 0.5 {
+		t.Fatalf("win leg at %s with reliability %v, want sketchy at 0.5", detected[0].WinBookmaker, detected[0].Reliability)
+	}
+
+	tests := []struct {
+		threshold float64
+		want      int
+	}{
+		{threshold: 0, want: 1},
+		{threshold: 0.5, want: 1},
+		{threshold: 0.8, want: 0},
+	}
+	for _, tt := range tests {
+		opts := scanOptions{TotalBet: 100, ArbThreshold: 1, Filters: []OpportunityFilter{MinReliability(tt.threshold)}}
+		if got := applyScanOptions(detected, opts); len(got) != tt.want {
+			t.Errorf("at threshold %v got %d opportunities, want %d", tt.threshold, len(got), tt.want)
+		}
+	}
+}
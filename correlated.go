@@ -0,0 +1,69 @@
+package main
+
+import "strings"
+
+// Market of opportunities without an explicit market: the match result
+const marketMatchResult = "1x2"
+
+// Define a combination of markets on the same fixture whose outcomes are
+// correlated. Bookmakers void or restrict bets combining them, so an
+// opportunity mixing them is not a real arbitrage.
+type CorrelationRule struct {
+	Markets []string
+	Reason  string
+}
+
+// Market combinations known to be correlated. The match result markets
+// scanned today are independent legs of one event, so none of these match
+// yet; they apply once opportunities combine other markets.
+var defaultCorrelationRules = []CorrelationRule{
+	{Markets: []string{"match_winner", "both_teams_to_score"}, Reason: "same-game result and goals markets are settled together"},
+	{Markets: []string{"over_under", "correct_score"}, Reason: "a correct score decides the totals market"},
+}
+
+// Return the markets an opportunity combines. Combined markets are joined
+// with "+", as in draw_no_bet+draw.
+func opportunityMarkets(opportunity ArbitrageOpportunity) []string {
+	if opportunity.Market == "" {
+		return []string{marketMatchResult}
+	}
+	return strings.Split(opportunity.Market, "+")
+}
+
+// Return the first rule whose markets are all combined by an opportunity
+func correlatedRule(opportunity ArbitrageOpportunity, rules []CorrelationRule) (CorrelationRule, bool) {
+	markets := make(map[string]bool)
+	for _, market := range opportunityMarkets(opportunity) {
+		markets[market] = true
+	}
+	for _, rule := range rules {
+		matched := len(rule.Markets) > 0
+		for _, market := range rule.Markets {
+			if !markets[market] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return rule, true
+		}
+	}
+	return CorrelationRule{}, false
+}
+
+// Warn about opportunities that combine correlated markets, also rejecting
+// them when exclude is set
+func CorrelatedMarkets(rules []CorrelationRule, exclude bool) OpportunityFilter {
+	return func(opportunity ArbitrageOpportunity) bool {
+		rule, correlated := correlatedRule(opportunity, rules)
+		if !correlated {
+			return true
+		}
+		if exclude {
+			warn("game %s: excluding correlated markets %s: %s", opportunity.GameID, strings.Join(rule.Markets, "+"), rule.Reason)
+			return false
+		}
+		warn("game %s: markets %s are correlated and may be voided: %s", opportunity.GameID, strings.Join(rule.Markets, "+"), rule.Reason)
+		return true
+	}
+}
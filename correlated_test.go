@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Run fn and return what it wrote to stderr
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	file, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	saved := os.Stderr
+	defer func() { os.Stderr = saved }()
+	os.Stderr = file
+	fn()
+	out, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestOpportunityMarkets(t *testing.T) {
+	tests := []struct {
+		market string
+		want   []string
+	}{
+		{market: "", want: []string{marketMatchResult}},
+		{market: marketDrawNoBet, want: []string{"draw_no_bet", "draw"}},
+	}
+	for _, tt := range tests {
+		if got := opportunityMarkets(ArbitrageOpportunity{Market: tt.market}); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("opportunityMarkets(%q) = %v, want %v", tt.market, got, tt.want)
+		}
+	}
+}
+
+func TestCorrelatedMarkets(t *testing.T) {
+	tests := []struct {
+		name        string
+		market      string
+		exclude     bool
+		wantPass    bool
+		wantWarning string
+	}{
+		{name: "match result is a no-op", wantPass: true},
+		{name: "draw no bet combination is a no-op", market: marketDrawNoBet, wantPass: true},
+		{name: "flagged combination warns", market: "match_winner+both_teams_to_score", wantPass: true,
+			wantWarning: "Warning: game g1: markets match_winner+both_teams_to_score are correlated and may be voided: same-game result and goals markets are settled together\n"},
+		{name: "flagged combination excluded", market: "correct_score+over_under", exclude: true,
+			wantWarning: "Warning: game g1: excluding correlated markets over_under+correct_score: a correct score decides the totals market\n"},
+		{name: "partial combination is not flagged", market: "match_winner+over_under", wantPass: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := CorrelatedMarkets(defaultCorrelationRules, tt.exclude)
+			var passed bool
+			warning := captureStderr(t, func() {
+				passed = filter(ArbitrageOpportunity{GameID: "g1", Market: tt.market})
+			})
+			if passed != tt.wantPass {
+				t.Errorf("passed = %v, want %v", passed, tt.wantPass)
+			}
+			if warning != tt.wantWarning {
+				t.Errorf("warning = %q, want %q", warning, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestCorrelatedRuleWithoutMarkets(t *testing.T) {
+	if _, correlated := correlatedRule(ArbitrageOpportunity{}, []CorrelationRule{{Reason: "empty"}}); correlated {
+		t.Error("a rule without markets matched")
+	}
+}
+
+func TestScanWarnsAboutCorrelatedCombination(t *testing.T) {
+	rules := []CorrelationRule{{Markets: []string{"draw_no_bet", "draw"}, Reason: "test rule"}}
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, DrawNoBet: &Odds{Win: 2.5, Lose: 2.5}, Available: true}}},
+		{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 5, Lose: 4}, Available: true}}},
+	}
+	warning := captureStderr(t, func() {
+		opts := scanOptions{TotalBet: 100, Filters: []OpportunityFilter{CorrelatedMarkets(rules, false)}}
+		if got := findDrawNoBetArbitrage(bookmakers, opts); len(got) != 1 {
+			t.Errorf("found %d draw no bet opportunities, want 1", len(got))
+		}
+	})
+	if !strings.Contains(warning, "markets draw_no_bet+draw are correlated") {
+		t.Errorf("warning = %q, want the correlated markets", warning)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Columns of the bookmakers CSV format, in their default order
+var csvColumns = []string{"bookmaker", "game_id", "team_a", "team_b", "win", "draw", "lose", "event_at"}
+
+// Columns that may be left out of a CSV file, following the required ones by default
+var csvOptionalColumns = []string{"sport", "available"}
+
+// Read bookmakers data from a CSV file with one game per row. A header row is
+// detected when its first field names a known column and may list the columns
+// in any order; without one, rows must use the default column order.
+func readBookmakersFromCSV(filename string) ([]Bookmaker, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, newFileError(filename, err)
+	}
+	defer file.Close()
+	bookmakers, err := decodeBookmakersCSV(file)
+	if err != nil {
+		return nil, &FileError{Path: filename, Err: ErrParse, Cause: err}
+	}
+	if len(bookmakers) == 0 {
+		return nil, &FileError{Path: filename, Err: ErrNoData}
+	}
+	return bookmakers, nil
+}
+
+// Decode bookmakers data from CSV rows, grouping games by bookmaker in the
+// order bookmakers first appear
+func decodeBookmakersCSV(r io.Reader) ([]Bookmaker, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var bookmakers []Bookmaker
+	index := make(map[string]int)
+	columns := make(map[string]int, len(csvColumns)+len(csvOptionalColumns))
+	for i, name := range append(csvColumns, csvOptionalColumns...) {
+		columns[name] = i
+	}
+
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+
+		if row == 1 && isCSVHeader(record) {
+			columns, err = csvHeaderColumns(record)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		game, name, err := parseCSVGame(record, columns)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		i, exists := index[name]
+		if !exists {
+			index[name] = len(bookmakers)
+			bookmakers = append(bookmakers, Bookmaker{Name: name})
+			i = len(bookmakers) - 1
+		}
+		bookmakers[i].Games = append(bookmakers[i].Games, game)
+	}
+	return bookmakers, nil
+}
+
+// Report whether a CSV record is a header row
+func isCSVHeader(record []string) bool {
+	if len(record) == 0 {
+		return false
+	}
+	first := strings.ToLower(strings.TrimSpace(record[0]))
+	for _, name := range append(csvColumns, csvOptionalColumns...) {
+		if first == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Map column names to their positions in a header row, requiring every column
+// that is not optional
+func csvHeaderColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	var missing []string
+	for _, name := range csvColumns {
+		if _, exists := columns[name]; !exists {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("header is missing columns: %s", strings.Join(missing, ", "))
+	}
+	return columns, nil
+}
+
+// Parse a game and its bookmaker name from a CSV record
+func parseCSVGame(record []string, columns map[string]int) (Game, string, error) {
+	field := func(name string) (string, error) {
+		i := columns[name]
+		if i >= len(record) {
+			return "", fmt.Errorf("missing %s column", name)
+		}
+		return strings.TrimSpace(record[i]), nil
+	}
+	odds := func(name string) (float64, error) {
+		value, err := field(name)
+		if err != nil {
+			return 0, err
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, fmt.Errorf("invalid %s odds %q", name, value)
+		}
+		return f, nil
+	}
+
+	var game Game
+	var name string
+	var err error
+	if name, err = field("bookmaker"); err != nil {
+		return Game{}, "", err
+	}
+	if game.ID, err = field("game_id"); err != nil {
+		return Game{}, "", err
+	}
+	if game.TeamA, err = field("team_a"); err != nil {
+		return Game{}, "", err
+	}
+	if game.TeamB, err = field("team_b"); err != nil {
+		return Game{}, "", err
+	}
+	if game.Odds.Win, err = odds("win"); err != nil {
+		return Game{}, "", err
+	}
+	if game.Odds.Draw, err = odds("draw"); err != nil {
+		return Game{}, "", err
+	}
+	if game.Odds.Lose, err = odds("lose"); err != nil {
+		return Game{}, "", err
+	}
+	if game.EventAt, err = field("event_at"); err != nil {
+		return Game{}, "", err
+	}
+	if i, exists := columns["sport"]; exists && i < len(record) {
+		game.Sport = strings.TrimSpace(record[i])
+	}
+	game.Available = true
+	if i, exists := columns["available"]; exists && i < len(record) && strings.TrimSpace(record[i]) != "" {
+		value := strings.TrimSpace(record[i])
+		if game.Available, err = strconv.ParseBool(value); err != nil {
+			return Game{}, "", fmt.Errorf("invalid available value %q", value)
+		}
+	}
+	return game, name, nil
+}
+
+// Write bookmakers data to a CSV file with a header row
+func writeBookmakersToCSV(bookmakers []Bookmaker, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return newFileError(filename, err)
+	}
+	if err := encodeBookmakersCSV(file, bookmakers); err != nil {
+		file.Close()
+		return newFileError(filename, err)
+	}
+	if err := file.Close(); err != nil {
+		return newFileError(filename, err)
+	}
+	return nil
+}
+
+// Encode bookmakers data as CSV rows with a header row
+func encodeBookmakersCSV(w io.Writer, bookmakers []Bookmaker) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append(csvColumns, csvOptionalColumns...)); err != nil {
+		return err
+	}
+	for _, bookmaker := range bookmakers {
+		for _, game := range bookmaker.Games {
+			if err := writer.Write(csvRecord(bookmaker.Name, game)); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Format a game as a CSV record in the default column order
+func csvRecord(bookmaker string, game Game) []string {
+	return []string{
+		bookmaker,
+		game.ID,
+		game.TeamA,
+		game.TeamB,
+		strconv.FormatFloat(game.Odds.Win, 'f', -1, 64),
+		strconv.FormatFloat(game.Odds.Draw, 'f', -1, 64),
+		strconv.FormatFloat(game.Odds.Lose, 'f', -1, 64),
+		game.EventAt,
+		game.Sport,
+		strconv.FormatBool(game.Available),
+	}
+}
+
+// Report whether a filename refers to a CSV file
+func isCSVFile(filename string) bool {
+	return strings.EqualFold(filepath.Ext(filename), ".csv")
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{
+			{ID: "g1", TeamA: "Home", TeamB: "Away", Odds: Odds{Win: 2.05, Draw: 3.3, Lose: 3.75}, EventAt: "2024-05-01T18:30:00Z", Available: true},
+			{ID: "g2", TeamA: "Comma, FC", TeamB: "\"Quoted\"", Odds: Odds{Win: 1.0 / 3 * 7, Lose: 1.5}, Sport: "tennis", Available: false},
+		}},
+		{Name: "b", Games: []Game{
+			{ID: "g1", TeamA: "Home", TeamB: "Away", Odds: Odds{Win: 2.1, Draw: 3.2, Lose: 3.6}, EventAt: "2024-05-01T18:30:00Z", Available: true},
+		}},
+	}
+	filename := filepath.Join(t.TempDir(), "bookmakers.csv")
+	if err := writeBookmakersToCSV(bookmakers, filename); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readBookmakersFromCSV(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, bookmakers) {
+		t.Errorf("round trip gave %+v, want %+v", got, bookmakers)
+	}
+}
+
+func TestDecodeBookmakersCSV(t *testing.T) {
+	want := []Bookmaker{{Name: "a", Games: []Game{
+		{ID: "g1", TeamA: "H", TeamB: "A", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, EventAt: "2024-05-01", Available: true},
+	}}}
+	tests := []struct {
+		name    string
+		input   string
+		want    []Bookmaker
+		wantErr string
+	}{
+		{name: "default order without a header", input: "a,g1,H,A,2,3,4,2024-05-01\n", want: want},
+		{name: "header in another order", input: "game_id,Bookmaker,win,draw,lose,team_a,team_b,event_at\ng1,a,2,3,4,H,A,2024-05-01\n", want: want},
+		{name: "header missing columns", input: "bookmaker,game_id,win\na,g1,2\n", wantErr: "header is missing columns: team_a, team_b, draw, lose, event_at"},
+		{name: "malformed odds", input: "a,g1,H,A,2,x,4,2024-05-01\n", wantErr: `row 1: invalid draw odds "x"`},
+		{name: "infinite odds", input: "a,g1,H,A,2,3,4,2024-05-01\na,g2,H,A,Inf,3,4,2024-05-01\n", wantErr: `row 2: invalid win odds "Inf"`},
+		{name: "short row", input: "a,g1,H,A,2,3\n", wantErr: "row 1: missing lose column"},
+		{name: "bad available", input: "a,g1,H,A,2,3,4,2024-05-01,,maybe\n", wantErr: `row 1: invalid available value "maybe"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeBookmakersCSV(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzDecodeBookmakersCSV(f *testing.F) {
+	for _, seed := range []string{
+		"a,g1,Home,Away,2.05,3.3,3.75,2024-05-01T18:30:00Z\n",
+		"bookmaker,game_id,team_a,team_b,win,draw,lose,event_at,sport,available\nb,g1,A,B,1.7,0,2.2,,tennis,false\n",
+		"lose,win,draw,event_at,team_b,team_a,game_id,bookmaker\n3,2,4,,B,A,g1,c\n",
+		"a,g1,\"Comma, FC\",\"\"\"Quoted\"\"\",NaN,Inf,-1,\n",
+		"a,g1,A,B,2\n",
+		"game_id\n",
+		"a,g1,A,B,1e309,3,4,x,soccer,maybe\n",
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		bookmakers, err := decodeBookmakersCSV(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		for _, bookmaker := range bookmakers {
+			for _, game := range bookmaker.Games {
+				for _, odds := range []float64{game.Odds.Win, game.Odds.Draw, game.Odds.Lose} {
+					if math.IsNaN(odds) || math.IsInf(odds, 0) {
+						t.Fatalf("decoded non-finite odds %v for game %q", odds, game.ID)
+					}
+				}
+			}
+		}
+		// Whatever decodes must survive being written back out unchanged
+		var buf bytes.Buffer
+		if err := encodeBookmakersCSV(&buf, bookmakers); err != nil {
+			t.Fatal(err)
+		}
+		again, err := decodeBookmakersCSV(&buf)
+		if err != nil {
+			t.Fatalf("re-decoding the encoded data: %v", err)
+		}
+		if !reflect.DeepEqual(again, bookmakers) {
+			t.Fatalf("round trip = %+v, want %+v", again, bookmakers)
+		}
+	})
+}
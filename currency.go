@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Define a source of exchange rates. Rate returns how many units of the to
+// currency one unit of the from currency buys.
+type RateSource interface {
+	Rate(from, to string) (float64, error)
+}
+
+// Define an exchange rate source backed by a fixed table of each currency's
+// value in US dollars
+type staticRates map[string]float64
+
+// Default exchange rates used when no other source is configured
+var defaultRates = staticRates{
+	"USD": 1,
+	"EUR": 1.08,
+	"GBP": 1.27,
+	"CHF": 1.12,
+	"CAD": 0.73,
+	"AUD": 0.66,
+	"JPY": 0.0067,
+}
+
+// Return the exchange rate between two currencies in the table
+func (r staticRates) Rate(from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return 1, nil
+	}
+	fromUSD, exists := r[from]
+	if !exists {
+		return 0, fmt.Errorf("no exchange rate for %s", from)
+	}
+	toUSD, exists := r[to]
+	if !exists {
+		return 0, fmt.Errorf("no exchange rate for %s", to)
+	}
+	return fromUSD / toUSD, nil
+}
+
+// Convert an amount between currencies using a rate source
+func convertAmount(amount float64, from, to string, rates RateSource) (float64, error) {
+	rate, err := rates.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// Define the structure for the currency a bookmaker quotes in and the rate
+// from the base currency to it
+type localCurrency struct {
+	Currency string
+	Rate     float64
+}
+
+// Look up the rate from the base currency to each bookmaker's currency.
+// Bookmakers without a currency are taken to quote in the base currency and
+// are left out. A bookmaker whose currency has no rate is an error, since its
+// stakes could not be reported.
+func bookmakerCurrencies(bookmakers []Bookmaker, base string, rates RateSource) (map[string]localCurrency, error) {
+	currencies := make(map[string]localCurrency)
+	for _, bookmaker := range bookmakers {
+		if bookmaker.Currency == "" || strings.EqualFold(bookmaker.Currency, base) {
+			continue
+		}
+		rate, err := rates.Rate(base, bookmaker.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("bookmaker %s: %w", bookmaker.Name, err)
+		}
+		currencies[bookmaker.Name] = localCurrency{Currency: strings.ToUpper(bookmaker.Currency), Rate: rate}
+	}
+	return currencies, nil
+}
+
+// Define the structure for a leg's stake in its bookmaker's own currency
+type LocalStake struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+}
+
+// Record the stake to place in local currency for each leg at a bookmaker
+// quoting in a currency other than the base one. All other monetary values
+// stay in the base currency so opportunities remain comparable.
+func localizeStakes(opportunity ArbitrageOpportunity, base string, currencies map[string]localCurrency) ArbitrageOpportunity {
+	if base == "" {
+		return opportunity
+	}
+	opportunity.Currency = strings.ToUpper(base)
+	opportunity.LocalStakes = nil
+	for _, leg := range opportunityLegs(opportunity) {
+		currency, exists := currencies[leg.Bookmaker]
+		if !exists {
+			continue
+		}
+		if opportunity.LocalStakes == nil {
+			opportunity.LocalStakes = make(map[string]LocalStake)
+		}
+		opportunity.LocalStakes[leg.Outcome] = LocalStake{Currency: currency.Currency, Amount: leg.Stake * currency.Rate}
+	}
+	return opportunity
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// Euros at a known 1.10 dollars
+var testRates = staticRates{"USD": 1, "EUR": 1.1}
+
+func TestConvertAmount(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		from, to string
+		want     float64
+		wantErr  bool
+	}{
+		{amount: 100, from: "EUR", to: "USD", want: 110},
+		{amount: 110, from: "usd", to: "eur", want: 100},
+		{amount: 42, from: "EUR", to: "eur", want: 42},
+		{amount: 100, from: "GBP", to: "USD", wantErr: true},
+		{amount: 100, from: "USD", to: "GBP", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := convertAmount(tt.amount, tt.from, tt.to, testRates)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("convertAmount(%v, %s, %s) error = %v, want error %v", tt.amount, tt.from, tt.to, err, tt.wantErr)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("convertAmount(%v, %s, %s) = %v, want %v", tt.amount, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestBookmakerCurrencies(t *testing.T) {
+	bookmakers := []Bookmaker{{Name: "home"}, {Name: "base", Currency: "usd"}, {Name: "euro", Currency: "eur"}}
+	currencies, err := bookmakerCurrencies(bookmakers, "USD", testRates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(currencies) != 1 || currencies["euro"].Currency != "EUR" || math.Abs(currencies["euro"].Rate-1/1.1) > 1e-9 {
+		t.Errorf("bookmakerCurrencies() = %+v, want only euro at 1/1.1", currencies)
+	}
+
+	bookmakers = append(bookmakers, Bookmaker{Name: "pound", Currency: "GBP"})
+	if _, err := bookmakerCurrencies(bookmakers, "USD", testRates); err == nil || !strings.Contains(err.Error(), "bookmaker pound: no exchange rate for GBP") {
+		t.Errorf("missing rate error = %v", err)
+	}
+}
+
+func TestLocalizeStakesFromEURToUSD(t *testing.T) {
+	// The win leg is at a bookmaker quoting in euros, the others in dollars
+	opportunity := ArbitrageOpportunity{
+		Odds:          Odds{Win: 2, Draw: 4, Lose: 5},
+		WinBookmaker:  "euro",
+		DrawBookmaker: "home",
+		LoseBookmaker: "home",
+		WinStake:      55,
+		DrawStake:     27.5,
+		LoseStake:     22,
+		TotalBet:      104.5,
+	}
+	currencies, err := bookmakerCurrencies([]Bookmaker{{Name: "euro", Currency: "EUR"}, {Name: "home"}}, "usd", testRates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := localizeStakes(opportunity, "usd", currencies)
+	if got.Currency != "USD" || got.WinStake != 55 || got.TotalBet != 104.5 {
+		t.Errorf("base amounts changed: %+v", got)
+	}
+	if len(got.LocalStakes) != 1 || got.LocalStakes[OutcomeWin].Currency != "EUR" || math.Abs(got.LocalStakes[OutcomeWin].Amount-50) > 1e-9 {
+		t.Errorf("local stakes = %+v, want 50 EUR on the win leg", got.LocalStakes)
+	}
+
+	var buf strings.Builder
+	printArbitrageOpportunity(&buf, got, defaultOutputOptions)
+	for _, want := range []string{"Stakes (USD): Win: 55.00", "Local stakes: Win: 50.00 EUR\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, buf.String())
+		}
+	}
+
+	if unchanged := localizeStakes(opportunity, "", currencies); unchanged.Currency != "" || unchanged.LocalStakes != nil {
+		t.Errorf("without a base currency got %+v", unchanged)
+	}
+}
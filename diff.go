@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Define the structure for a game identified by its bookmaker
+type GameRef struct {
+	Bookmaker string `json:"bookmaker"`
+	GameID    string `json:"game_id"`
+}
+
+// Define the structure for a change in a bookmaker's odds for a game
+type OddsChange struct {
+	Bookmaker string `json:"bookmaker"`
+	GameID    string `json:"game_id"`
+	Old       Odds   `json:"old"`
+	New       Odds   `json:"new"`
+}
+
+// Define the structure for the differences between two sets of bookmakers
+// data, such as two scraping runs
+type BookmakerDiff struct {
+	AddedBookmakers   []string     `json:"added_bookmakers,omitempty"`
+	RemovedBookmakers []string     `json:"removed_bookmakers,omitempty"`
+	AddedGames        []GameRef    `json:"added_games,omitempty"`
+	RemovedGames      []GameRef    `json:"removed_games,omitempty"`
+	OddsChanges       []OddsChange `json:"odds_changes,omitempty"`
+}
+
+// Report whether two sets of bookmakers data are the same
+func (d BookmakerDiff) Empty() bool {
+	return len(d.AddedBookmakers) == 0 && len(d.RemovedBookmakers) == 0 &&
+		len(d.AddedGames) == 0 && len(d.RemovedGames) == 0 && len(d.OddsChanges) == 0
+}
+
+// Compare two sets of bookmakers data. Bookmakers are matched by name and
+// fixtures by game ID, as when merging files. Games of added or removed
+// bookmakers are not listed separately. Results are sorted by bookmaker and
+// game ID.
+func diffBookmakers(old, new []Bookmaker) BookmakerDiff {
+	oldBooks := mergeBookmakers(old)
+	newBooks := mergeBookmakers(new)
+	oldByName := make(map[string]Bookmaker, len(oldBooks))
+	for _, bookmaker := range oldBooks {
+		oldByName[bookmaker.Name] = bookmaker
+	}
+	newByName := make(map[string]Bookmaker, len(newBooks))
+	for _, bookmaker := range newBooks {
+		newByName[bookmaker.Name] = bookmaker
+	}
+
+	var diff BookmakerDiff
+	for _, bookmaker := range oldBooks {
+		if _, exists := newByName[bookmaker.Name]; !exists {
+			diff.RemovedBookmakers = append(diff.RemovedBookmakers, bookmaker.Name)
+		}
+	}
+	for _, bookmaker := range newBooks {
+		previous, exists := oldByName[bookmaker.Name]
+		if !exists {
+			diff.AddedBookmakers = append(diff.AddedBookmakers, bookmaker.Name)
+			continue
+		}
+		oldGames := make(map[string]Game, len(previous.Games))
+		for _, game := range previous.Games {
+			oldGames[game.ID] = game
+		}
+		newGames := make(map[string]bool, len(bookmaker.Games))
+		for _, game := range bookmaker.Games {
+			newGames[game.ID] = true
+			oldGame, exists := oldGames[game.ID]
+			switch {
+			case !exists:
+				diff.AddedGames = append(diff.AddedGames, GameRef{Bookmaker: bookmaker.Name, GameID: game.ID})
+			case oldGame.Odds != game.Odds:
+				diff.OddsChanges = append(diff.OddsChanges, OddsChange{Bookmaker: bookmaker.Name, GameID: game.ID, Old: oldGame.Odds, New: game.Odds})
+			}
+		}
+		for _, game := range previous.Games {
+			if !newGames[game.ID] {
+				diff.RemovedGames = append(diff.RemovedGames, GameRef{Bookmaker: bookmaker.Name, GameID: game.ID})
+			}
+		}
+	}
+
+	sort.Strings(diff.AddedBookmakers)
+	sort.Strings(diff.RemovedBookmakers)
+	sortGameRefs(diff.AddedGames)
+	sortGameRefs(diff.RemovedGames)
+	sort.Slice(diff.OddsChanges, func(i, j int) bool {
+		a, b := diff.OddsChanges[i], diff.OddsChanges[j]
+		if a.Bookmaker != b.Bookmaker {
+			return a.Bookmaker < b.Bookmaker
+		}
+		return a.GameID < b.GameID
+	})
+	return diff
+}
+
+// Sort game references by bookmaker, then game ID
+func sortGameRefs(refs []GameRef) {
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Bookmaker != refs[j].Bookmaker {
+			return refs[i].Bookmaker < refs[j].Bookmaker
+		}
+		return refs[i].GameID < refs[j].GameID
+	})
+}
+
+// Print the differences between two sets of bookmakers data
+func printBookmakerDiff(w io.Writer, diff BookmakerDiff, out outputOptions) {
+	if diff.Empty() {
+		fmt.Fprintln(w, "No differences")
+		return
+	}
+	for _, name := range diff.AddedBookmakers {
+		fmt.Fprintf(w, "+ bookmaker %s\n", name)
+	}
+	for _, name := range diff.RemovedBookmakers {
+		fmt.Fprintf(w, "- bookmaker %s\n", name)
+	}
+	for _, ref := range diff.AddedGames {
+		fmt.Fprintf(w, "+ %s: game %s\n", ref.Bookmaker, ref.GameID)
+	}
+	for _, ref := range diff.RemovedGames {
+		fmt.Fprintf(w, "- %s: game %s\n", ref.Bookmaker, ref.GameID)
+	}
+	format := func(odds Odds) string {
+		return fmt.Sprintf("%.*f/%.*f/%.*f", out.OddsPrecision, odds.Win, out.OddsPrecision, odds.Draw, out.OddsPrecision, odds.Lose)
+	}
+	for _, change := range diff.OddsChanges {
+		fmt.Fprintf(w, "~ %s: game %s: %s -> %s\n", change.Bookmaker, change.GameID, format(change.Old), format(change.New))
+	}
+}
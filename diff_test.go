@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// Two scraping runs: g1 at b moves and b adds g2
+func diffRuns() (old, new []Bookmaker) {
+	old = []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, Available: true}}},
+		{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2.1, Draw: 3, Lose: 3.5}, Available: true}}},
+	}
+	new = []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, Available: true}}},
+		{Name: "b", Games: []Game{
+			{ID: "g1", Odds: Odds{Win: 2.25, Draw: 3, Lose: 3.5}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 1.8, Draw: 3.4, Lose: 4.5}, Available: true},
+		}},
+	}
+	return old, new
+}
+
+func TestDiffBookmakers(t *testing.T) {
+	old, new := diffRuns()
+	tests := []struct {
+		name     string
+		old, new []Bookmaker
+		want     BookmakerDiff
+	}{
+		{name: "identical", old: old, new: old},
+		{name: "one odds change and one added game", old: old, new: new, want: BookmakerDiff{
+			AddedGames:  []GameRef{{Bookmaker: "b", GameID: "g2"}},
+			OddsChanges: []OddsChange{{Bookmaker: "b", GameID: "g1", Old: Odds{Win: 2.1, Draw: 3, Lose: 3.5}, New: Odds{Win: 2.25, Draw: 3, Lose: 3.5}}},
+		}},
+		{name: "reversed", old: new, new: old, want: BookmakerDiff{
+			RemovedGames: []GameRef{{Bookmaker: "b", GameID: "g2"}},
+			OddsChanges:  []OddsChange{{Bookmaker: "b", GameID: "g1", Old: Odds{Win: 2.25, Draw: 3, Lose: 3.5}, New: Odds{Win: 2.1, Draw: 3, Lose: 3.5}}},
+		}},
+		{name: "bookmakers added and removed", old: old[:1], new: new[1:], want: BookmakerDiff{
+			AddedBookmakers:   []string{"b"},
+			RemovedBookmakers: []string{"a"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffBookmakers(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffBookmakers() = %+v, want %+v", got, tt.want)
+			}
+			if got.Empty() != (tt.name == "identical") {
+				t.Errorf("Empty() = %v", got.Empty())
+			}
+		})
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	old, new := diffRuns()
+	code, stdout, _ := runCLI(t, "-diff", writeTestData(t, old), writeTestData(t, new))
+	if code != exitNoOpportunities {
+		t.Errorf("exit code = %d, want %d", code, exitNoOpportunities)
+	}
+	want := "+ b: game g2\n~ b: game g1: 2.10/3.00/3.50 -> 2.25/3.00/3.50\n"
+	if stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+
+	var buf bytes.Buffer
+	printBookmakerDiff(&buf, diffBookmakers(old, old), defaultOutputOptions)
+	if buf.String() != "No differences\n" {
+		t.Errorf("identical files print %q", buf.String())
+	}
+
+	if code, _, _ := runCLI(t, "-diff", writeTestData(t, old)); code != exitError {
+		t.Errorf("exit code with one file = %d, want %d", code, exitError)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// Market of opportunities combining a draw no bet market with a draw bet
+const marketDrawNoBet = "draw_no_bet+draw"
+
+// Calculate the stakes for a draw no bet market, refunding both sides on a
+// draw, combined with a straight bet on the draw. Backing home and away draw
+// no bet to pay P each leaves their stakes refunded on a draw, so the draw
+// bet only has to make up the rest of P. Profit is positive exactly when the
+// draw no bet odds imply less than 100% between them.
+func drawNoBetStakes(home, away, draw, totalBet float64) (homeStake, awayStake, drawStake, profit float64) {
+	gap := 1 - 1/home - 1/away
+	perPayout := 1/home + 1/away + gap/draw
+	payout := totalBet / perPayout
+	homeStake = payout / home
+	awayStake = payout / away
+	drawStake = payout * gap / draw
+	return homeStake, awayStake, drawStake, payout - totalBet
+}
+
+// Find hedged positions combining each bookmaker's draw no bet market with
+// the best draw odds at another bookmaker. The win and lose legs of each
+// opportunity are the draw no bet sides. These positions refund stakes on a
+// draw, so only the scan's filters apply to them; the stake adjustments and
+// verification assume straight 1X2 bets.
+func findDrawNoBetArbitrage(bookmakers []Bookmaker, opts scanOptions) []ArbitrageOpportunity {
+	totalBet := opts.TotalBet
+	type drawQuote struct {
+		odds      float64
+		bookmaker string
+	}
+	// Keep the two best draw prices per game so every bookmaker can be
+	// paired with the best draw elsewhere
+	bestDraws := make(map[string][2]drawQuote)
+	for _, bookmaker := range bookmakers {
+		for _, game := range bookmaker.Games {
+			if !game.Available || !(game.Odds.Draw > 1) {
+				continue
+			}
+			quotes := bestDraws[game.ID]
+			quote := drawQuote{odds: game.Odds.Draw, bookmaker: bookmaker.Name}
+			switch {
+			case quote.odds > quotes[0].odds:
+				quotes[0], quotes[1] = quote, quotes[0]
+			case quote.odds > quotes[1].odds && quote.bookmaker != quotes[0].bookmaker:
+				quotes[1] = quote
+			}
+			bestDraws[game.ID] = quotes
+		}
+	}
+
+	var opportunities []ArbitrageOpportunity
+	for _, bookmaker := range bookmakers {
+		for _, game := range bookmaker.Games {
+			dnb := game.DrawNoBet
+			if !game.Available || dnb == nil || !(dnb.Win > 1) || !(dnb.Lose > 1) {
+				continue
+			}
+			draw := bestDraws[game.ID][0]
+			if draw.bookmaker == bookmaker.Name {
+				draw = bestDraws[game.ID][1]
+			}
+			if draw.bookmaker == "" {
+				continue
+			}
+			homeStake, awayStake, drawStake, profit := drawNoBetStakes(dnb.Win, dnb.Lose, draw.odds, totalBet)
+			if !(profit > 0) {
+				continue
+			}
+			opportunity := ArbitrageOpportunity{
+				GameID:              game.ID,
+				Sport:               game.Sport,
+				Market:              marketDrawNoBet,
+				WinBookmaker:        bookmaker.Name,
+				DrawBookmaker:       draw.bookmaker,
+				LoseBookmaker:       bookmaker.Name,
+				Odds:                Odds{Win: dnb.Win, Draw: draw.odds, Lose: dnb.Lose},
+				ArbitragePercentage: totalBet / (totalBet + profit),
+				TotalBet:            totalBet,
+				WinStake:            homeStake,
+				DrawStake:           drawStake,
+				LoseStake:           awayStake,
+				GuaranteedProfit:    profit,
+			}
+			opportunity.ReturnOnCapital = returnOnCapital(opportunity, totalBet)
+			opportunity.ID = opportunityID(opportunity)
+			if passesFilters(opportunity, opts.Filters) {
+				opportunities = append(opportunities, opportunity)
+			}
+		}
+	}
+	sortOpportunities(opportunities, opts.Sort)
+	return opportunities
+}
+
+// Print draw no bet combinations
+func printDrawNoBetArbitrage(w io.Writer, opportunities []ArbitrageOpportunity, out outputOptions) {
+	for _, opportunity := range opportunities {
+		fmt.Fprintln(w, "Draw no bet at", opportunity.WinBookmaker, "hedged with the draw at", opportunity.DrawBookmaker)
+		printArbitrageOpportunity(w, opportunity, out)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDrawNoBetStakes(t *testing.T) {
+	// Draw no bet sides at 2.5 imply 80% between them, leaving 20% of the
+	// payout for the draw at 5 to cover: 100 / 0.84 pays about 119.05
+	homeStake, awayStake, drawStake, profit := drawNoBetStakes(2.5, 2.5, 5, 100)
+	if got := homeStake + awayStake + drawStake; math.Abs(got-100) > 1e-9 {
+		t.Errorf("stakes total %v, want 100", got)
+	}
+	payout := 100 / 0.84
+	if math.Abs(profit-(payout-100)) > 1e-9 {
+		t.Errorf("profit = %v, want %v", profit, payout-100)
+	}
+	// Home and away win outright; on a draw both are refunded and the draw
+	// bet pays out
+	returns := map[string]float64{
+		"home": homeStake * 2.5,
+		"away": awayStake * 2.5,
+		"draw": homeStake + awayStake + drawStake*5,
+	}
+	for outcome, got := range returns {
+		if math.Abs(got-payout) > 1e-9 {
+			t.Errorf("%s returns %v, want %v", outcome, got, payout)
+		}
+	}
+}
+
+func TestFindDrawNoBetArbitrage(t *testing.T) {
+	dnb := &Odds{Win: 2.5, Lose: 2.5}
+	tests := []struct {
+		name       string
+		bookmakers []Bookmaker
+		wantDraw   string
+		wantOdds   float64
+	}{
+		{
+			name: "draw at another bookmaker",
+			bookmakers: []Bookmaker{
+				{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, DrawNoBet: dnb, Available: true}}},
+				{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 5, Lose: 4}, Available: true}}},
+			},
+			wantDraw: "b",
+			wantOdds: 5,
+		},
+		{
+			name: "own best draw skipped for the next best",
+			bookmakers: []Bookmaker{
+				{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 6, Lose: 4}, DrawNoBet: dnb, Available: true}}},
+				{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 5, Lose: 4}, Available: true}}},
+			},
+			wantDraw: "b",
+			wantOdds: 5,
+		},
+		{
+			name: "suspended draw ignored",
+			bookmakers: []Bookmaker{
+				{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, DrawNoBet: dnb, Available: true}}},
+				{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 5, Lose: 4}}}},
+			},
+		},
+		{
+			name: "draw no bet priced over 100%",
+			bookmakers: []Bookmaker{
+				{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, DrawNoBet: &Odds{Win: 1.8, Lose: 1.9}, Available: true}}},
+				{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 5, Lose: 4}, Available: true}}},
+			},
+		},
+		{
+			name: "single bookmaker",
+			bookmakers: []Bookmaker{
+				{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 5, Lose: 4}, DrawNoBet: dnb, Available: true}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findDrawNoBetArbitrage(tt.bookmakers, scanOptions{TotalBet: 100})
+			if tt.wantDraw == "" {
+				if len(got) != 0 {
+					t.Fatalf("found %d opportunities, want none", len(got))
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Fatalf("found %d opportunities, want 1", len(got))
+			}
+			opportunity := got[0]
+			if opportunity.Market != marketDrawNoBet {
+				t.Errorf("Market = %q, want %q", opportunity.Market, marketDrawNoBet)
+			}
+			if opportunity.WinBookmaker != "a" || opportunity.LoseBookmaker != "a" || opportunity.DrawBookmaker != tt.wantDraw {
+				t.Errorf("bookmakers = %s/%s/%s, want a/%s/a", opportunity.WinBookmaker,
+					opportunity.DrawBookmaker, opportunity.LoseBookmaker, tt.wantDraw)
+			}
+			if opportunity.Odds.Draw != tt.wantOdds {
+				t.Errorf("draw odds = %v, want %v", opportunity.Odds.Draw, tt.wantOdds)
+			}
+			if !(opportunity.GuaranteedProfit > 0) {
+				t.Errorf("GuaranteedProfit = %v, want positive", opportunity.GuaranteedProfit)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/bxcodec/faker/v3"
+)
+
+// Seed of the dataset the doctor check generates around its planted fixture
+const doctorSeed = 42
+
+// ID of the fixture the doctor check plants an arbitrage in
+const doctorGameID = "doctor-planted-arbitrage"
+
+// Guaranteed profit on a 100 stake of the planted arbitrage, whose best
+// odds are 2.10, 4.20 and 5.50: 100/(1/2.10 + 1/4.20 + 1/5.50) - 100
+const doctorExpectedProfit = 11.594202898550733
+
+// Run the whole pipeline on a tiny generated dataset with a planted
+// arbitrage and check that it is detected with the expected legs and profit,
+// writing PASS or FAIL to w. It reports whether the check passed.
+func runDoctor(w io.Writer) bool {
+	fail := func(format string, args ...interface{}) bool {
+		fmt.Fprintf(w, "FAIL: "+format+"\n", args...)
+		return false
+	}
+
+	bookmakers := generateBookmakersWithSeed(3, 5, doctorSeed, NamesRandom)
+	planted := []Odds{
+		{Win: 2.10, Draw: 3.00, Lose: 3.00},
+		{Win: 1.50, Draw: 4.20, Lose: 5.50},
+		{Win: 1.80, Draw: 3.50, Lose: 4.00},
+	}
+	for i := range bookmakers {
+		bookmakers[i].Games = append(bookmakers[i].Games, Game{
+			ID:        doctorGameID,
+			TeamA:     "Doctor Home",
+			TeamB:     "Doctor Away",
+			Odds:      planted[i],
+			EventAt:   seededEventAtLimit.Format(faker.BaseDateFormat),
+			Available: true,
+		})
+	}
+
+	dir, err := ioutil.TempDir("", "sba-doctor")
+	if err != nil {
+		return fail("creating a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "bookmakers.json")
+	if err := saveBookmakers(bookmakers, filename, retryPolicy{}); err != nil {
+		return fail("writing the dataset: %v", err)
+	}
+	loaded, err := loadBookmakers(filename, loadOptions{})
+	if err != nil {
+		return fail("reading the dataset back: %v", err)
+	}
+	loaded = removeSelfMatches(loaded)
+	applyConfig(loaded, Config{})
+
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1, Verify: true}
+	detected, _ := detectArbitrageOpportunities(context.Background(), loaded, opts)
+	for _, opportunity := range applyScanOptions(detected, opts) {
+		if opportunity.GameID != doctorGameID {
+			continue
+		}
+		want := [3]string{bookmakers[0].Name, bookmakers[1].Name, bookmakers[1].Name}
+		got := [3]string{opportunity.WinBookmaker, opportunity.DrawBookmaker, opportunity.LoseBookmaker}
+		if got != want {
+			return fail("planted arbitrage used bookmakers %v, expected %v", got, want)
+		}
+		if math.Abs(opportunity.GuaranteedProfit-doctorExpectedProfit) > verifyEpsilon {
+			return fail("planted arbitrage profit is %.4f, expected %.4f", opportunity.GuaranteedProfit, doctorExpectedProfit)
+		}
+		fmt.Fprintf(w, "PASS: planted arbitrage detected with %.2f guaranteed profit\n", opportunity.GuaranteedProfit)
+		return true
+	}
+	return fail("planted arbitrage was not detected")
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRunDoctorPasses(t *testing.T) {
+	var buf bytes.Buffer
+	if !runDoctor(&buf) {
+		t.Fatalf("doctor failed on a healthy build: %s", buf.String())
+	}
+	if want := "PASS: planted arbitrage detected with 11.59 guaranteed profit\n"; buf.String() != want {
+		t.Errorf("doctor output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestDoctorExpectedProfit(t *testing.T) {
+	want := 100/(1/2.10+1/4.20+1/5.50) - 100
+	if math.Abs(doctorExpectedProfit-want) > 1e-9 {
+		t.Errorf("doctorExpectedProfit = %v, want %v", doctorExpectedProfit, want)
+	}
+}
+
+func TestRunDoctorFlag(t *testing.T) {
+	code, stdout, _ := runCLI(t, "-doctor")
+	if code != exitNoOpportunities || !strings.HasPrefix(stdout, "PASS: ") {
+		t.Errorf("-doctor exited %d with %q, want 0 and PASS", code, stdout)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+)
+
+// Errors describing why loading, validating or computing failed. They are
+// wrapped with context by FileError and GameError and can be checked with
+// errors.Is.
+var (
+	ErrInvalidOdds  = errors.New("invalid odds")
+	ErrFileNotFound = errors.New("file not found")
+	ErrParse        = errors.New("parse error")
+	ErrNoData       = errors.New("no data")
+	ErrNoArbitrage  = errors.New("odds are not an arbitrage")
+	ErrInvalidBet   = errors.New("invalid total bet")
+	ErrGameNotFound = errors.New("game not found")
+)
+
+// Define the structure for an error concerning a file
+type FileError struct {
+	Path  string
+	Err   error
+	Cause error
+}
+
+// Describe a file error
+func (e *FileError) Error() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %v: %v", e.Path, e.Err, e.Cause)
+	}
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Return the errors wrapped by a file error
+func (e *FileError) Unwrap() []error {
+	if e.Cause != nil {
+		return []error{e.Err, e.Cause}
+	}
+	return []error{e.Err}
+}
+
+// Wrap an error from reading or writing a file, classifying missing files
+func newFileError(path string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return &FileError{Path: path, Err: ErrFileNotFound, Cause: err}
+	}
+	return &FileError{Path: path, Err: err}
+}
+
+// Define the structure for an error concerning a bookmaker's game
+type GameError struct {
+	Bookmaker string
+	GameID    string
+	Err       error
+}
+
+// Describe a game error
+func (e *GameError) Error() string {
+	if e.Bookmaker != "" {
+		return fmt.Sprintf("%s: game %s: %v", e.Bookmaker, e.GameID, e.Err)
+	}
+	return fmt.Sprintf("game %s: %v", e.GameID, e.Err)
+}
+
+// Return the error wrapped by a game error
+func (e *GameError) Unwrap() error {
+	return e.Err
+}
+
+// Check that every leg of a set of odds is a valid decimal price above 1.
+// The draw leg must be zero for sports without a draw.
+func checkOdds(odds Odds, sport string) error {
+	legs := []struct {
+		outcome string
+		value   float64
+	}{{OutcomeWin, odds.Win}, {OutcomeDraw, odds.Draw}, {OutcomeLose, odds.Lose}}
+	if !outcomesForSport(sport).HasDraw {
+		if odds.Draw != 0 {
+			return fmt.Errorf("%w: %s has no draw but draw odds are %v", ErrInvalidOdds, sport, odds.Draw)
+		}
+		legs = append(legs[:1], legs[2])
+	}
+	for _, leg := range legs {
+		if !(leg.value > 1) {
+			return fmt.Errorf("%w: %s odds %v must be above 1", ErrInvalidOdds, leg.outcome, leg.value)
+		}
+		if math.IsInf(leg.value, 1) {
+			return fmt.Errorf("%w: %s odds must be finite", ErrInvalidOdds, leg.outcome)
+		}
+	}
+	return nil
+}
+
+// Check that a total bet can be split into stakes. Zero would produce all
+// zero stakes and negative totals nonsensical ones.
+func checkTotalBet(totalBet float64) error {
+	if !(totalBet > 0) || math.IsInf(totalBet, 1) {
+		return fmt.Errorf("%w: %v must be a positive amount", ErrInvalidBet, totalBet)
+	}
+	return nil
+}
+
+// Check a bookmaker's odds for a game, identifying the game on failure
+func validateOdds(bookmaker string, game Game) error {
+	if err := checkOdds(game.Odds, game.Sport); err != nil {
+		return &GameError{Bookmaker: bookmaker, GameID: game.ID, Err: err}
+	}
+	return nil
+}
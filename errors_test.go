@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadBookmakersErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+	tests := []struct {
+		name     string
+		filename string
+		want     error
+	}{
+		{name: "missing file", filename: filepath.Join(dir, "missing.json"), want: ErrFileNotFound},
+		{name: "malformed JSON", filename: write("bad.json", `[{"name": `), want: ErrParse},
+		{name: "trailing data", filename: write("trailing.json", `[{"name": "a"}] []`), want: ErrParse},
+		{name: "empty array", filename: write("empty.json", `[]`), want: ErrNoData},
+		{name: "malformed CSV odds", filename: write("bad.csv", "a,g1,H,A,2,x,4,2024-05-01\n"), want: ErrParse},
+		{name: "empty CSV", filename: write("empty.csv", ""), want: ErrNoData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadBookmakers(tt.filename, loadOptions{})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+			var fileErr *FileError
+			if !errors.As(err, &fileErr) || fileErr.Path != tt.filename {
+				t.Errorf("error %v does not carry the path %s", err, tt.filename)
+			}
+		})
+	}
+}
+
+func TestValidateOddsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		game    Game
+		wantErr bool
+	}{
+		{name: "valid", game: Game{ID: "g", Odds: Odds{Win: 2, Draw: 3, Lose: 4}}},
+		{name: "odds of one", game: Game{ID: "g", Odds: Odds{Win: 1, Draw: 3, Lose: 4}}, wantErr: true},
+		{name: "missing draw", game: Game{ID: "g", Odds: Odds{Win: 2, Lose: 4}}, wantErr: true},
+		{name: "two-way without draw", game: Game{ID: "g", Sport: "tennis", Odds: Odds{Win: 2, Lose: 2}}},
+		{name: "two-way with draw", game: Game{ID: "g", Sport: "tennis", Odds: Odds{Win: 2, Draw: 3, Lose: 2}}, wantErr: true},
+		{name: "infinite", game: Game{ID: "g", Odds: Odds{Win: 2, Draw: 3, Lose: math.Inf(1)}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOdds("book", tt.game)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("validateOdds() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidOdds) {
+				t.Fatalf("error = %v, want ErrInvalidOdds", err)
+			}
+			var gameErr *GameError
+			if !errors.As(err, &gameErr) || gameErr.Bookmaker != "book" || gameErr.GameID != "g" {
+				t.Errorf("error %v does not identify the game", err)
+			}
+		})
+	}
+}
+
+func TestCheckTotalBet(t *testing.T) {
+	for _, totalBet := range []float64{0, -10, math.Inf(1)} {
+		if err := checkTotalBet(totalBet); !errors.Is(err, ErrInvalidBet) {
+			t.Errorf("checkTotalBet(%v) = %v, want ErrInvalidBet", totalBet, err)
+		}
+	}
+	if err := checkTotalBet(100); err != nil {
+		t.Errorf("checkTotalBet(100) = %v", err)
+	}
+}
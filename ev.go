@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+)
+
+// Define the structure for the expected value of backing one outcome at its best odds
+type OutcomeEV struct {
+	GameID      string  `json:"game_id"`
+	Outcome     string  `json:"outcome"`
+	Bookmaker   string  `json:"bookmaker"`
+	Odds        float64 `json:"odds"`
+	Probability float64 `json:"probability"`
+	EV          float64 `json:"ev"`
+}
+
+// Calculate the expected profit of a stake at decimal odds given our own
+// estimate of the outcome's probability. Unlike arbitrage this is not
+// guaranteed: a positive value only pays off on average.
+func expectedValue(odds float64, myProbability float64, stake float64) float64 {
+	return stake * (odds*myProbability - 1)
+}
+
+// Read our own outcome probability estimates from a JSON file mapping game
+// IDs to win, draw and lose probabilities
+func loadProbabilities(filename string) (map[string]Odds, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, newFileError(filename, err)
+	}
+	var probabilities map[string]Odds
+	if err := json.Unmarshal(data, &probabilities); err != nil {
+		return nil, &FileError{Path: filename, Err: ErrParse, Cause: err}
+	}
+	return probabilities, nil
+}
+
+// Rank every outcome we have a probability for by the expected value of
+// backing it at its best available odds, highest first
+func rankOutcomesByEV(bookmakers []Bookmaker, probabilities map[string]Odds, stake float64) []OutcomeEV {
+	var outcomes []OutcomeEV
+	for gameID, best := range findBestOddsWithSource(bookmakers) {
+		probability, exists := probabilities[gameID]
+		if !exists {
+			continue
+		}
+		for _, leg := range []struct {
+			outcome     string
+			odds        float64
+			bookmaker   string
+			probability float64
+		}{
+			{OutcomeWin, best.Odds.Win, best.WinSource, probability.Win},
+			{OutcomeDraw, best.Odds.Draw, best.DrawSource, probability.Draw},
+			{OutcomeLose, best.Odds.Lose, best.LoseSource, probability.Lose},
+		} {
+			if leg.odds <= 1 || leg.probability <= 0 {
+				continue
+			}
+			outcomes = append(outcomes, OutcomeEV{
+				GameID:      gameID,
+				Outcome:     leg.outcome,
+				Bookmaker:   leg.bookmaker,
+				Odds:        leg.odds,
+				Probability: leg.probability,
+				EV:          expectedValue(leg.odds, leg.probability, stake),
+			})
+		}
+	}
+	sort.Slice(outcomes, func(i, j int) bool {
+		if outcomes[i].EV != outcomes[j].EV {
+			return outcomes[i].EV > outcomes[j].EV
+		}
+		if outcomes[i].GameID != outcomes[j].GameID {
+			return outcomes[i].GameID < outcomes[j].GameID
+		}
+		return outcomes[i].Outcome < outcomes[j].Outcome
+	})
+	return outcomes
+}
+
+// Print outcomes ranked by expected value
+func printOutcomeEVs(w io.Writer, outcomes []OutcomeEV, stake float64, out outputOptions) {
+	fmt.Fprintf(w, "Expected value of a %.*f stake on each outcome at its best odds:\n", out.Precision, stake)
+	for _, outcome := range outcomes {
+		ev := fmt.Sprintf("%.*f", out.Precision, outcome.EV)
+		fmt.Fprintf(w, "%s %s at %s: odds %.*f, probability %.2f%%, EV %s\n",
+			outcome.GameID, outcome.Outcome, outcome.Bookmaker, out.OddsPrecision, outcome.Odds,
+			outcome.Probability*100, colorProfit(ev, outcome.EV, out.Color))
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpectedValue(t *testing.T) {
+	tests := []struct {
+		name                     string
+		odds, probability, stake float64
+		want                     float64
+	}{
+		{name: "positive EV", odds: 2.5, probability: 0.5, stake: 100, want: 25},
+		{name: "negative EV", odds: 1.5, probability: 0.5, stake: 100, want: -25},
+		{name: "fair odds", odds: 4, probability: 0.25, stake: 10, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expectedValue(tt.odds, tt.probability, tt.stake); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("expectedValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankOutcomesByEV(t *testing.T) {
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{
+			{ID: "g1", Odds: Odds{Win: 2.5, Draw: 3, Lose: 3}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, Available: true},
+		}},
+		{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2.2, Draw: 3.4, Lose: 2.8}, Available: true}}},
+	}
+	// g2 has no estimate and the g1 lose estimate of zero is left out
+	probabilities := map[string]Odds{"g1": {Win: 0.5, Draw: 0.25}}
+
+	got := rankOutcomesByEV(bookmakers, probabilities, 100)
+	want := []OutcomeEV{
+		{GameID: "g1", Outcome: OutcomeWin, Bookmaker: "a", Odds: 2.5, Probability: 0.5, EV: 25},
+		{GameID: "g1", Outcome: OutcomeDraw, Bookmaker: "b", Odds: 3.4, Probability: 0.25, EV: -15},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("rankOutcomesByEV() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].GameID != want[i].GameID || got[i].Outcome != want[i].Outcome || got[i].Bookmaker != want[i].Bookmaker ||
+			got[i].Odds != want[i].Odds || math.Abs(got[i].EV-want[i].EV) > 1e-9 {
+			t.Errorf("outcome %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	printOutcomeEVs(&buf, got, 100, defaultOutputOptions)
+	if !strings.Contains(buf.String(), "g1 win at a: odds 2.50, probability 50.00%, EV 25.00\n") {
+		t.Errorf("report =\n%s", buf.String())
+	}
+}
+
+func TestLoadProbabilities(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "probabilities.json")
+	if err := os.WriteFile(filename, []byte(`{"g1":{"win":0.5,"draw":0.3,"lose":0.2}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	probabilities, err := loadProbabilities(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := probabilities["g1"], (Odds{Win: 0.5, Draw: 0.3, Lose: 0.2}); got != want {
+		t.Errorf("g1 probabilities = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+)
+
+// Layouts accepted for game event times, tried in order. Generated data uses
+// faker's date layout, 2006-01-02, while hand-maintained files often carry a
+// time of day. Times without a zone are taken to be UTC.
+var eventAtLayouts = []string{
+	time.RFC3339,
+	faker.BaseDateFormat,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+}
+
+// Parse a game's event time in any of the accepted layouts
+func parseEventAt(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range eventAtLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized event time %q", s)
+}
+
+// Rewrite every parseable event time as RFC3339 in UTC so later consumers only
+// deal with one layout. Event times that cannot be parsed are left untouched
+// for validation to report. Returns how many event times were rewritten and
+// how many non-empty ones could not be parsed.
+func normalizeEventTimes(bookmakers []Bookmaker) (converted, unparsed int) {
+	for i := range bookmakers {
+		for j := range bookmakers[i].Games {
+			game := &bookmakers[i].Games[j]
+			t, err := parseEventAt(game.EventAt)
+			if err != nil {
+				if strings.TrimSpace(game.EventAt) != "" {
+					unparsed++
+				}
+				continue
+			}
+			if normalized := t.UTC().Format(time.RFC3339); normalized != game.EventAt {
+				game.EventAt = normalized
+				converted++
+			}
+		}
+	}
+	return converted, unparsed
+}
+
+// Rewrite a bookmakers file in place with its event times normalized to
+// RFC3339, for files written before event times were normalized on load.
+// Returns how many event times were converted and how many could not be
+// parsed; the file is only rewritten when something was converted. Odds are
+// read as written so net odds files are not rewritten as decimal, and the
+// file is replaced atomically.
+func migrateEventTimes(filename string, opts loadOptions, retry retryPolicy) (converted, unparsed int, err error) {
+	opts.OddsBasis = OddsDecimal
+	opts.RawEventTimes = true
+	bookmakers, err := loadBookmakers(filename, opts)
+	if err != nil {
+		return 0, 0, err
+	}
+	converted, unparsed = normalizeEventTimes(bookmakers)
+	if converted == 0 {
+		return 0, unparsed, nil
+	}
+	return converted, unparsed, saveBookmakersAtomically(bookmakers, filename, retry)
+}
+
+// Drop the games starting before cutoff at every bookmaker, such as games
+// already played or starting too soon for bets to be placed in time. Games
+// without a parseable event time are kept. Also returns the number of
+// distinct fixtures dropped.
+func excludeGamesStartingBefore(bookmakers []Bookmaker, cutoff time.Time) (kept []Bookmaker, excluded int) {
+	dropped := make(map[string]bool)
+	kept = make([]Bookmaker, 0, len(bookmakers))
+	for _, bookmaker := range bookmakers {
+		games := make([]Game, 0, len(bookmaker.Games))
+		for _, game := range bookmaker.Games {
+			if t, err := parseEventAt(game.EventAt); err == nil && t.Before(cutoff) {
+				dropped[game.ID] = true
+				continue
+			}
+			games = append(games, game)
+		}
+		bookmaker.Games = games
+		kept = append(kept, bookmaker)
+	}
+	return kept, len(dropped)
+}
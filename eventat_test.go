@@ -0,0 +1,196 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v3"
+)
+
+func TestParseEventAt(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "2024-05-01T18:30:00Z", want: time.Date(2024, 5, 1, 18, 30, 0, 0, time.UTC)},
+		{in: "2024-05-01", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{in: "2024-05-01 18:30:05", want: time.Date(2024, 5, 1, 18, 30, 5, 0, time.UTC)},
+		{in: "2024-05-01T18:30:05", want: time.Date(2024, 5, 1, 18, 30, 5, 0, time.UTC)},
+		{in: " 2024-05-01 18:30 ", want: time.Date(2024, 5, 1, 18, 30, 0, 0, time.UTC)},
+		{in: "01/05/2024", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseEventAt(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseEventAt(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("parseEventAt(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseEventAtFakerDates(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		date := faker.Date()
+		got, err := parseEventAt(date)
+		if err != nil {
+			t.Fatalf("parseEventAt(%q) error = %v", date, err)
+		}
+		if got.Format(faker.BaseDateFormat) != date {
+			t.Errorf("parseEventAt(%q) = %v", date, got)
+		}
+	}
+	for _, bookmaker := range generateBookmakersWithSeed(2, 20, 3, NamesRandom) {
+		for _, game := range bookmaker.Games {
+			if _, err := parseEventAt(game.EventAt); err != nil {
+				t.Errorf("generated event time %q: %v", game.EventAt, err)
+			}
+		}
+	}
+}
+
+func TestNormalizeEventTimes(t *testing.T) {
+	bookmakers := []Bookmaker{{Name: "a", Games: []Game{
+		{ID: "g1", EventAt: "2024-05-01"},
+		{ID: "g2", EventAt: "2024-05-01 18:30:05"},
+		{ID: "g3", EventAt: "2024-05-01T20:30:00+02:00"},
+		{ID: "g4", EventAt: "2024-05-01T18:30:00Z"},
+		{ID: "g5", EventAt: "next tuesday"},
+		{ID: "g6"},
+	}}}
+	converted, unparsed := normalizeEventTimes(bookmakers)
+	if converted != 3 || unparsed != 1 {
+		t.Errorf("normalizeEventTimes() = %d, %d, want 3 converted and 1 unparsed", converted, unparsed)
+	}
+	want := []string{"2024-05-01T00:00:00Z", "2024-05-01T18:30:05Z", "2024-05-01T18:30:00Z", "2024-05-01T18:30:00Z", "next tuesday", ""}
+	for i, game := range bookmakers[0].Games {
+		if game.EventAt != want[i] {
+			t.Errorf("game %s event time = %q, want %q", game.ID, game.EventAt, want[i])
+		}
+	}
+}
+
+func TestLoadNormalizesEventTimes(t *testing.T) {
+	filename := writeTestData(t, []Bookmaker{{Name: "a", Games: []Game{{ID: "g1", EventAt: "2024-05-01", Available: true}}}})
+	bookmakers, err := loadBookmakers(filename, loadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bookmakers[0].Games[0].EventAt; got != "2024-05-01T00:00:00Z" {
+		t.Errorf("loaded event time = %q, want RFC3339", got)
+	}
+	raw, err := loadBookmakers(filename, loadOptions{RawEventTimes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := raw[0].Games[0].EventAt; got != "2024-05-01" {
+		t.Errorf("raw event time = %q, want it as written", got)
+	}
+}
+
+func TestMigrateEventTimesKeepsOddsBasis(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "net.json")
+	bookmakers := []Bookmaker{{Name: "a", Games: []Game{
+		{ID: "g1", EventAt: "2024-05-01 18:30", Odds: Odds{Win: 1.25, Draw: 2.5, Lose: 3.75}, Available: true},
+		{ID: "g2", EventAt: "soon", Odds: Odds{Win: 0.5, Draw: 2, Lose: 4}, Available: true},
+	}}}
+	if err := writeBookmakersToFile(bookmakers, filename); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filename, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	converted, unparsed, err := migrateEventTimes(filename, loadOptions{OddsBasis: OddsNet}, retryPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted != 1 || unparsed != 1 {
+		t.Errorf("converted, unparsed = %d, %d, want 1, 1", converted, unparsed)
+	}
+
+	got, err := loadBookmakers(filename, loadOptions{RawEventTimes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Games[0].EventAt != "2024-05-01T18:30:00Z" {
+		t.Errorf("EventAt = %q, want RFC3339", got[0].Games[0].EventAt)
+	}
+	if got[0].Games[1].EventAt != "soon" {
+		t.Errorf("unparseable EventAt = %q, want it left alone", got[0].Games[1].EventAt)
+	}
+	// Net odds must be written back as net, not converted to decimal
+	if got[0].Games[0].Odds != bookmakers[0].Games[0].Odds {
+		t.Errorf("odds = %+v, want %+v", got[0].Games[0].Odds, bookmakers[0].Games[0].Odds)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("mode = %v, want 0640", info.Mode().Perm())
+	}
+}
+
+func TestExcludeGamesStartingBefore(t *testing.T) {
+	now := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{
+			{ID: "started", EventAt: at(-time.Hour)},
+			{ID: "soon", EventAt: at(2 * time.Minute)},
+			{ID: "later", EventAt: at(time.Hour)},
+			{ID: "undated", EventAt: "next tuesday"},
+		}},
+		{Name: "b", Games: []Game{{ID: "soon", EventAt: at(2 * time.Minute)}}},
+	}
+	tests := []struct {
+		lead         time.Duration
+		wantKept     []string
+		wantExcluded int
+	}{
+		{lead: 0, wantKept: []string{"later", "soon", "undated"}, wantExcluded: 1},
+		{lead: 5 * time.Minute, wantKept: []string{"later", "undated"}, wantExcluded: 2},
+		{lead: 2 * time.Hour, wantKept: []string{"undated"}, wantExcluded: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lead.String(), func(t *testing.T) {
+			kept, excluded := excludeGamesStartingBefore(bookmakers, now.Add(tt.lead))
+			if excluded != tt.wantExcluded {
+				t.Errorf("excluded %d fixtures, want %d", excluded, tt.wantExcluded)
+			}
+			if got := fixtureIDs(kept[0]); !reflect.DeepEqual(got, tt.wantKept) {
+				t.Errorf("kept %v, want %v", got, tt.wantKept)
+			}
+		})
+	}
+}
+
+func TestRunMinLeadExcludesImminentGames(t *testing.T) {
+	bookmakers := plantedBookmakers()
+	soon := time.Now().Add(2 * time.Minute).UTC().Format(time.RFC3339)
+	for i := range bookmakers {
+		bookmakers[i].Games[0].EventAt = soon
+	}
+	file := writeTestData(t, bookmakers)
+
+	code, stdout, _ := runCLI(t, "-min-lead", "1m", "-file", file)
+	if code != exitOpportunities || !strings.Contains(stdout, "found for game planted") {
+		t.Errorf("at a 1m lead exit code = %d, stdout = %q, want the game reported", code, stdout)
+	}
+	code, stdout, stderr := runCLI(t, "-min-lead", "5m", "-file", file)
+	if code != exitNoOpportunities || strings.Contains(stdout, "found for game planted") {
+		t.Errorf("at a 5m lead exit code = %d, stdout = %q, want the game excluded", code, stdout)
+	}
+	if !strings.Contains(stderr, "Warning: excluded 1 fixtures starting within 5m0s") {
+		t.Errorf("stderr = %q, want the excluded count", stderr)
+	}
+}
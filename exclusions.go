@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Define the structure for an external exclusion list, mapping game IDs to
+// the reason a fixture's odds should not be trusted, such as an injury to
+// a key player
+type ExclusionList map[string]string
+
+// Read an exclusion list from a JSON object mapping game IDs to reasons
+func loadExclusionList(filename string) (ExclusionList, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, newFileError(filename, err)
+	}
+	var exclusions ExclusionList
+	if err := json.Unmarshal(data, &exclusions); err != nil {
+		return nil, &FileError{Path: filename, Err: ErrParse, Cause: err}
+	}
+	return exclusions, nil
+}
+
+// Reject opportunities on fixtures in the exclusion list
+func ExcludeFixtures(exclusions ExclusionList) OpportunityFilter {
+	return func(opportunity ArbitrageOpportunity) bool {
+		_, excluded := exclusions[opportunity.GameID]
+		return !excluded
+	}
+}
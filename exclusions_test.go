@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadExclusionList(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    ExclusionList
+		wantErr error
+	}{
+		{name: "flagged fixture", content: `{"g2": "striker injured"}`, want: ExclusionList{"g2": "striker injured"}},
+		{name: "empty list", content: `{}`, want: ExclusionList{}},
+		{name: "malformed", content: `["g2"]`, wantErr: ErrParse},
+		{name: "missing file", wantErr: ErrFileNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, tt.name+".json")
+			if tt.content != "" {
+				if err := os.WriteFile(filename, []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := loadExclusionList(filename)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadExclusionList = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludeFixtures(t *testing.T) {
+	exclusions := ExclusionList{"g2": "striker injured"}
+	if got, want := passingGameIDs(ExcludeFixtures(exclusions)), []string{"g1", "g3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("passing = %v, want %v", got, want)
+	}
+	if got, want := passingGameIDs(ExcludeFixtures(nil)), []string{"g1", "g2", "g3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("without exclusions passing = %v, want %v", got, want)
+	}
+
+	// Both streamed fixtures are arbitrages; flagging one leaves the other
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1, Filters: []OpportunityFilter{ExcludeFixtures(ExclusionList{"g1": "manager sacked"})}}
+	detected, _ := detectArbitrageOpportunities(context.Background(), streamedBookmakers(), opts)
+	if got, want := opportunityGameIDs(applyScanOptions(detected, opts)), []string{"g2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reported %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Define how the bookmaker's margin is removed from a set of odds
+type DevigMethod int
+
+const (
+	// DevigProportional scales every implied probability by the same factor
+	// so they sum to 1, also known as multiplicative normalization
+	DevigProportional DevigMethod = iota
+	// DevigShin assumes the margin protects against insider trading, which
+	// takes proportionally more from longshots than from favourites
+	DevigShin
+)
+
+// Return the name of a de-vigging method
+func (m DevigMethod) String() string {
+	switch m {
+	case DevigProportional:
+		return "proportional"
+	case DevigShin:
+		return "shin"
+	}
+	return fmt.Sprintf("DevigMethod(%d)", int(m))
+}
+
+// Parse a de-vigging method name
+func parseDevigMethod(name string) (DevigMethod, error) {
+	for _, method := range []DevigMethod{DevigProportional, DevigShin} {
+		if method.String() == name {
+			return method, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown de-vig method %q, expected proportional or shin", name)
+}
+
+// Estimate the fair odds of a bookmaker's prices by removing the margin with
+// the proportional method. Zero draw odds mean a two-way market and stay zero.
+func fairOdds(odds Odds) Odds {
+	return fairOddsWith(odds, DevigProportional)
+}
+
+// Estimate the fair odds of a bookmaker's prices by removing the margin with
+// the given method, so the implied probabilities sum to 1
+func fairOddsWith(odds Odds, method DevigMethod) Odds {
+	implied := []float64{1 / odds.Win, 1 / odds.Lose}
+	if odds.Draw != 0 {
+		implied = append(implied, 1/odds.Draw)
+	}
+
+	var fair []float64
+	switch method {
+	case DevigShin:
+		fair = shinProbabilities(implied)
+	default:
+		fair = proportionalProbabilities(implied)
+	}
+
+	result := Odds{Win: 1 / fair[0], Lose: 1 / fair[1]}
+	if odds.Draw != 0 {
+		result.Draw = 1 / fair[2]
+	}
+	return result
+}
+
+// Split a total bet the way a perfectly efficient market would, staking each
+// outcome in proportion to its probability at the de-vigged fair odds. Every
+// outcome then pays back exactly the total bet, for zero profit. Removing the
+// margin proportionally keeps the odds' ratios, so the split matches an
+// arbitrage's stakes on the same odds: the arbitrage's edge is entirely in
+// paying more than the fair odds, not in how the bet is split.
+func fairStakes(odds Odds, totalBet float64) StakeAllocation {
+	return calculateStakes(fairOdds(odds), totalBet)
+}
+
+// Normalize implied probabilities so they sum to 1
+func proportionalProbabilities(implied []float64) []float64 {
+	var booksum float64
+	for _, p := range implied {
+		booksum += p
+	}
+	fair := make([]float64, len(implied))
+	for i, p := range implied {
+		fair[i] = p / booksum
+	}
+	return fair
+}
+
+// Convert implied probabilities to Shin's estimate of the true ones. The
+// share z of insider money is found by bisection so the probabilities sum to
+// 1; without a margin z is zero and the implied probabilities are returned.
+func shinProbabilities(implied []float64) []float64 {
+	var booksum float64
+	for _, p := range implied {
+		booksum += p
+	}
+	if booksum <= 1 {
+		return proportionalProbabilities(implied)
+	}
+
+	probabilities := func(z float64) ([]float64, float64) {
+		fair := make([]float64, len(implied))
+		var sum float64
+		for i, p := range implied {
+			fair[i] = (math.Sqrt(z*z+4*(1-z)*p*p/booksum) - z) / (2 * (1 - z))
+			sum += fair[i]
+		}
+		return fair, sum
+	}
+	low, high := 0.0, 1.0
+	for i := 0; i < 100; i++ {
+		z := (low + high) / 2
+		if _, sum := probabilities(z); sum > 1 {
+			low = z
+		} else {
+			high = z
+		}
+	}
+	fair, _ := probabilities((low + high) / 2)
+	return fair
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFairOdds(t *testing.T) {
+	tests := []struct {
+		name string
+		odds Odds
+		want Odds
+	}{
+		// The classic even-money market at 1.90 each carries a 5.26% margin
+		{name: "two-way at 1.90", odds: Odds{Win: 1.9, Lose: 1.9}, want: Odds{Win: 2, Lose: 2}},
+		// Implied probabilities of 0.5, 0.2857 and 0.25 sum to 1.0357, and
+		// normalizing them stretches every price by that factor
+		{name: "three-way", odds: Odds{Win: 2, Draw: 3.5, Lose: 4}, want: Odds{Win: 2 * 29 / 28.0, Draw: 3.5 * 29 / 28.0, Lose: 4 * 29 / 28.0}},
+		{name: "already fair", odds: Odds{Win: 2, Draw: 4, Lose: 4}, want: Odds{Win: 2, Draw: 4, Lose: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fairOdds(tt.odds); !oddsClose(got, tt.want) {
+				t.Errorf("fairOdds(%+v) = %+v, want %+v", tt.odds, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFairOddsShin(t *testing.T) {
+	// A symmetric market has nothing to shade between its outcomes
+	if got, want := fairOddsWith(Odds{Win: 1.9, Lose: 1.9}, DevigShin), (Odds{Win: 2, Lose: 2}); !oddsClose(got, want) {
+		t.Errorf("symmetric Shin odds = %+v, want %+v", got, want)
+	}
+
+	odds := Odds{Win: 1.5, Draw: 4, Lose: 7}
+	shin := fairOddsWith(odds, DevigShin)
+	proportional := fairOdds(odds)
+	if sum := 1/shin.Win + 1/shin.Draw + 1/shin.Lose; math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Shin probabilities sum to %v, want 1", sum)
+	}
+	// Shin takes more of the margin from the longshot than the favourite
+	if !(shin.Win < proportional.Win) || !(shin.Lose > proportional.Lose) {
+		t.Errorf("Shin odds %+v do not shade the longshot more than proportional %+v", shin, proportional)
+	}
+}
+
+func TestFairStakesBreakEven(t *testing.T) {
+	odds := Odds{Win: 2, Draw: 3.5, Lose: 4}
+	stakes := fairStakes(odds, 100)
+	fair := fairOdds(odds)
+	for _, payout := range []float64{stakes.Win * fair.Win, stakes.Draw * fair.Draw, stakes.Lose * fair.Lose} {
+		if math.Abs(payout-100) > 1e-9 {
+			t.Errorf("fair payout = %v, want the total bet of 100", payout)
+		}
+	}
+}
+
+func TestFairStakesAgainstArbitrage(t *testing.T) {
+	// A 5/6 book: the arbitrage and the fair market split 100 as 40/30/30,
+	// but only the arbitrage's odds pay back 120
+	tests := []struct {
+		name       string
+		odds       Odds
+		wantStakes StakeAllocation
+		wantPayout float64
+	}{
+		{name: "three-way", odds: Odds{Win: 3, Draw: 4, Lose: 4}, wantStakes: StakeAllocation{Win: 40, Draw: 30, Lose: 30}, wantPayout: 120},
+		{name: "two-way", odds: Odds{Win: 2.5, Lose: 2.5}, wantStakes: StakeAllocation{Win: 50, Lose: 50}, wantPayout: 125},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opportunity := newArbitrageOpportunity("g1", tt.odds, 100)
+			fair := fairStakes(tt.odds, 100)
+			for _, outcome := range []string{OutcomeWin, OutcomeDraw, OutcomeLose} {
+				if want := tt.wantStakes.Stake(outcome); math.Abs(fair.Stake(outcome)-want) > 1e-9 || math.Abs(opportunity.Stakes().Stake(outcome)-want) > 1e-9 {
+					t.Errorf("%s stakes: fair %v, arbitrage %v, want %v", outcome, fair.Stake(outcome), opportunity.Stakes().Stake(outcome), want)
+				}
+			}
+			if win, _, _ := opportunity.Stakes().Payout(tt.odds); math.Abs(win-tt.wantPayout) > 1e-9 {
+				t.Errorf("arbitrage payout = %v, want %v", win, tt.wantPayout)
+			}
+			if win, _, _ := fair.Payout(fairOdds(tt.odds)); math.Abs(win-100) > 1e-9 {
+				t.Errorf("fair payout = %v, want 100", win)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	out := defaultOutputOptions
+	out.ShowFair = true
+	printArbitrageOpportunity(&buf, newArbitrageOpportunity("g1", Odds{Win: 3, Draw: 4, Lose: 4}, 100), out)
+	if want := "Fair stakes: Win: 40.00, Draw: 30.00, Lose: 30.00, paying back 100.00 at fair odds for no profit\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output does not show the fair split:\n%s", buf.String())
+	}
+}
+
+func TestParseDevigMethod(t *testing.T) {
+	for _, method := range []DevigMethod{DevigProportional, DevigShin} {
+		got, err := parseDevigMethod(method.String())
+		if err != nil || got != method {
+			t.Errorf("parseDevigMethod(%q) = %v, %v", method.String(), got, err)
+		}
+	}
+	if _, err := parseDevigMethod("power"); err == nil {
+		t.Error("parseDevigMethod(\"power\") succeeded, want an error")
+	}
+}
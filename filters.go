@@ -0,0 +1,95 @@
+package main
+
+import "strings"
+
+// Define a filter deciding whether an opportunity should be reported.
+// Filters compose: an opportunity is reported only when every filter in a
+// scan accepts it.
+type OpportunityFilter func(ArbitrageOpportunity) bool
+
+// Report whether an opportunity passes every filter, checking them in order
+// and stopping at the first rejection
+func passesFilters(opportunity ArbitrageOpportunity, filters []OpportunityFilter) bool {
+	for _, filter := range filters {
+		if !filter(opportunity) {
+			return false
+		}
+	}
+	return true
+}
+
+// Accept opportunities guaranteeing at least this profit
+func MinProfit(profit float64) OpportunityFilter {
+	return func(opportunity ArbitrageOpportunity) bool {
+		return opportunity.GuaranteedProfit >= profit
+	}
+}
+
+// Accept opportunities where no single leg's stake exceeds this amount
+func MaxLegStake(stake float64) OpportunityFilter {
+	return func(opportunity ArbitrageOpportunity) bool {
+		return largestLegStake(opportunity) <= stake
+	}
+}
+
+// Accept opportunities whose least reliable bookmaker is at least this
+// reliable
+func MinReliability(reliability float64) OpportunityFilter {
+	return func(opportunity ArbitrageOpportunity) bool {
+		return opportunity.Reliability >= reliability
+	}
+}
+
+// Accept opportunities with every leg within the odds range; zero disables a
+// bound. The range is checked after best odds selection, so an out-of-range
+// leg disqualifies the whole opportunity rather than falling back to another
+// bookmaker's lower price for that leg.
+func OddsRange(minOdds, maxOdds float64) OpportunityFilter {
+	return func(opportunity ArbitrageOpportunity) bool {
+		return legsWithinOddsRange(opportunity, minOdds, maxOdds)
+	}
+}
+
+// Accept opportunities with every leg at one of the named bookmakers
+func IncludeBooks(names ...string) OpportunityFilter {
+	included := make(map[string]bool, len(names))
+	for _, name := range names {
+		included[name] = true
+	}
+	return func(opportunity ArbitrageOpportunity) bool {
+		for _, leg := range opportunityLegs(opportunity) {
+			if !included[leg.Bookmaker] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Accept opportunities with no leg at any of the named bookmakers
+func ExcludeBooks(names ...string) OpportunityFilter {
+	excluded := make(map[string]bool, len(names))
+	for _, name := range names {
+		excluded[name] = true
+	}
+	return func(opportunity ArbitrageOpportunity) bool {
+		for _, leg := range opportunityLegs(opportunity) {
+			if excluded[leg.Bookmaker] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Accept opportunities in one of the named sports, ignoring case
+func Sports(names ...string) OpportunityFilter {
+	return func(opportunity ArbitrageOpportunity) bool {
+		for _, name := range names {
+			if strings.EqualFold(opportunity.Sport, name) {
+				return true
+			}
+		}
+		return false
+	}
+}
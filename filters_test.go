@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// Opportunities on three fixtures with legs at different bookmakers
+func filterOpportunities() []ArbitrageOpportunity {
+	return []ArbitrageOpportunity{
+		{GameID: "g1", Sport: "soccer", Odds: Odds{Win: 1.5, Draw: 5, Lose: 8}, WinBookmaker: "a", DrawBookmaker: "b", LoseBookmaker: "a",
+			WinStake: 66, DrawStake: 20, LoseStake: 14, GuaranteedProfit: 0.5, Reliability: 1},
+		{GameID: "g2", Sport: "Tennis", Odds: Odds{Win: 2.2, Lose: 2.2}, WinBookmaker: "b", LoseBookmaker: "c",
+			WinStake: 50, LoseStake: 50, GuaranteedProfit: 10, Reliability: 0.6},
+		{GameID: "g3", Sport: "soccer", Odds: Odds{Win: 2.6, Draw: 3.6, Lose: 4.2}, WinBookmaker: "a", DrawBookmaker: "b", LoseBookmaker: "b",
+			WinStake: 43, DrawStake: 31, LoseStake: 26, GuaranteedProfit: 11, Reliability: 0.9},
+	}
+}
+
+// Return the game IDs of the opportunities passing filters
+func passingGameIDs(filters ...OpportunityFilter) []string {
+	var ids []string
+	for _, opportunity := range filterOpportunities() {
+		if passesFilters(opportunity, filters) {
+			ids = append(ids, opportunity.GameID)
+		}
+	}
+	return ids
+}
+
+func TestBuiltInFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter OpportunityFilter
+		want   []string
+	}{
+		{name: "min profit", filter: MinProfit(10), want: []string{"g2", "g3"}},
+		{name: "max leg stake", filter: MaxLegStake(50), want: []string{"g2", "g3"}},
+		{name: "min reliability", filter: MinReliability(0.9), want: []string{"g1", "g3"}},
+		{name: "odds range", filter: OddsRange(2, 5), want: []string{"g2", "g3"}},
+		{name: "include books", filter: IncludeBooks("a", "b"), want: []string{"g1", "g3"}},
+		{name: "exclude books", filter: ExcludeBooks("c"), want: []string{"g1", "g3"}},
+		{name: "sports", filter: Sports("tennis"), want: []string{"g2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := passingGameIDs(tt.filter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("passing = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChainedFilters(t *testing.T) {
+	if got, want := passingGameIDs(), []string{"g1", "g2", "g3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("without filters passing = %v, want %v", got, want)
+	}
+	if got, want := passingGameIDs(MinProfit(5), Sports("soccer")), []string{"g3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chained passing = %v, want %v", got, want)
+	}
+
+	// Filters run in order and stop at the first rejection
+	var calls []string
+	record := func(name string, accept bool) OpportunityFilter {
+		return func(ArbitrageOpportunity) bool {
+			calls = append(calls, name)
+			return accept
+		}
+	}
+	passesFilters(filterOpportunities()[0], []OpportunityFilter{record("first", true), record("second", false), record("third", true)})
+	if want := []string{"first", "second"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("filters called %v, want %v", calls, want)
+	}
+}
+
+func TestDetectionAppliesFilters(t *testing.T) {
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1}
+	detected, _ := detectArbitrageOpportunities(context.Background(), streamedBookmakers(), opts)
+	custom := func(opportunity ArbitrageOpportunity) bool { return opportunity.GameID != "g1" }
+	opts.Filters = []OpportunityFilter{MinProfit(0), custom}
+	got := applyScanOptions(detected, opts)
+	if len(got) != 1 || got[0].GameID != "g2" {
+		t.Errorf("applyScanOptions() kept %+v, want only g2", got)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+// Read a list of game IDs, one per line. Blank lines and surrounding spaces
+// are ignored.
+func loadGameIDs(filename string) ([]string, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, newFileError(filename, err)
+	}
+	var gameIDs []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if gameID := strings.TrimSpace(line); gameID != "" {
+			gameIDs = append(gameIDs, gameID)
+		}
+	}
+	if len(gameIDs) == 0 {
+		return nil, &FileError{Path: filename, Err: ErrNoData}
+	}
+	return gameIDs, nil
+}
+
+// Keep only the games with the given IDs at every bookmaker, so detection
+// never looks at other fixtures. Also returns the IDs no bookmaker lists, in
+// the order they were given.
+func restrictToGames(bookmakers []Bookmaker, gameIDs []string) (restricted []Bookmaker, missing []string) {
+	wanted := make(map[string]bool, len(gameIDs))
+	for _, gameID := range gameIDs {
+		wanted[gameID] = false
+	}
+	restricted = make([]Bookmaker, 0, len(bookmakers))
+	for _, bookmaker := range bookmakers {
+		var games []Game
+		for _, game := range bookmaker.Games {
+			if _, exists := wanted[game.ID]; exists {
+				wanted[game.ID] = true
+				games = append(games, game)
+			}
+		}
+		bookmaker.Games = games
+		restricted = append(restricted, bookmaker)
+	}
+	for _, gameID := range gameIDs {
+		if !wanted[gameID] {
+			missing = append(missing, gameID)
+			wanted[gameID] = true
+		}
+	}
+	return restricted, missing
+}
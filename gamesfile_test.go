@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Write a games file with the given content to a temporary directory
+func writeGamesFile(t *testing.T, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "games.txt")
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadGameIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr error
+	}{
+		{name: "one per line", content: "g1\ng3\n", want: []string{"g1", "g3"}},
+		{name: "blank lines and spaces", content: "\n  g1 \r\n\n\tg3\n", want: []string{"g1", "g3"}},
+		{name: "empty", content: "\n \n", wantErr: ErrNoData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadGameIDs(writeGamesFile(t, tt.content))
+			if !errors.Is(err, tt.wantErr) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadGameIDs() = %v, %v, want %v, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+	if _, err := loadGameIDs(filepath.Join(t.TempDir(), "missing.txt")); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("missing file error = %v, want %v", err, ErrFileNotFound)
+	}
+}
+
+// Bookmakers quoting three fixtures that are all arbitrages
+func restrictionBookmakers() []Bookmaker {
+	var a, b []Game
+	for _, id := range []string{"g1", "g2", "g3"} {
+		a = append(a, Game{ID: id, TeamA: "A", TeamB: "B", Odds: Odds{Win: 3, Draw: 3.2, Lose: 2}, Available: true})
+		b = append(b, Game{ID: id, TeamA: "A", TeamB: "B", Odds: Odds{Win: 2, Draw: 4, Lose: 4}, Available: true})
+	}
+	return []Bookmaker{{Name: "a", Games: a}, {Name: "b", Games: b}}
+}
+
+func TestRestrictToGames(t *testing.T) {
+	restricted, missing := restrictToGames(restrictionBookmakers(), []string{"g3", "unknown", "g1", "unknown"})
+	if want := []string{"unknown"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing = %v, want %v", missing, want)
+	}
+	for _, bookmaker := range restricted {
+		if got, want := fixtureIDs(bookmaker), []string{"g1", "g3"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s kept %v, want %v", bookmaker.Name, got, want)
+		}
+	}
+	detected, _ := detectArbitrageOpportunities(context.Background(), restricted, scanOptions{TotalBet: 100, ArbThreshold: 1})
+	if got, want := opportunityGameIDs(detected), []string{"g1", "g3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("detected %v, want %v", got, want)
+	}
+}
+
+func TestRunGamesFile(t *testing.T) {
+	data := writeTestData(t, restrictionBookmakers())
+	code, stdout, stderr := runCLI(t, "-games-file", writeGamesFile(t, "g1\ng3\nunknown\n"), "-file", data)
+	if code != exitOpportunities {
+		t.Errorf("exit code = %d, want %d", code, exitOpportunities)
+	}
+	if strings.Count(stdout, "Arbitrage opportunity found") != 2 || strings.Contains(stdout, "game g2") {
+		t.Errorf("stdout does not report exactly g1 and g3:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "Warning: game unknown: "+ErrGameNotFound.Error()) {
+		t.Errorf("stderr = %q, want unknown reported as not found", stderr)
+	}
+}
@@ -2,4 +2,27 @@ module github.com/cvele/sports-betting-arbitrage
 
 go 1.21.6
 
-require github.com/bxcodec/faker/v3 v3.8.1 // indirect
+require (
+	github.com/bxcodec/faker/v3 v3.8.1
+	github.com/nats-io/nats.go v1.36.0
+	github.com/xuri/excelize/v2 v2.8.1
+	go.uber.org/goleak v1.3.0
+	google.golang.org/grpc v1.64.0
+	google.golang.org/protobuf v1.33.0
+)
+
+require (
+	github.com/klauspost/compress v1.17.2 // indirect
+	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
+	github.com/nats-io/nkeys v0.4.7 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	github.com/richardlehane/mscfb v1.0.4 // indirect
+	github.com/richardlehane/msoleps v1.0.3 // indirect
+	github.com/xuri/efp v0.0.0-20231025114914-d1ff6096ae53 // indirect
+	github.com/xuri/nfp v0.0.0-20230919160717-d98342af3f05 // indirect
+	golang.org/x/crypto v0.21.0 // indirect
+	golang.org/x/net v0.22.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240318140521-94a12d6c2237 // indirect
+)
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Rewrite golden files with the current output instead of comparing, for when
+// output changes on purpose: go test -run Golden -update
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata with the current output")
+
+// Compare output with the golden file of that name in testdata, or rewrite
+// the file when -update is set
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	filename := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(filename, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from testdata/%s, rerun with -update if the change is intended\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// Bookmakers listing arbitrage fixtures in reverse game ID order, so output
+// only comes out sorted if the formatter sorts it
+func goldenBookmakers() []Bookmaker {
+	var a, b []Game
+	for i := 5; i >= 1; i-- {
+		id := fmt.Sprintf("g%d", i)
+		a = append(a, Game{ID: id, TeamA: "Home", TeamB: "Away", Odds: Odds{Win: 2.5 + float64(i)/10, Draw: 3.3, Lose: 3.3}, Available: true})
+		b = append(b, Game{ID: id, TeamA: "Home", TeamB: "Away", Odds: Odds{Win: 2.6, Draw: 3.6, Lose: 4.2}, Available: true})
+	}
+	return []Bookmaker{{Name: "a", Games: a}, {Name: "b", Games: b}}
+}
+
+func TestTextOutputGolden(t *testing.T) {
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1}
+	var first []byte
+	for run := 0; run < 5; run++ {
+		var buf bytes.Buffer
+		findArbitrageOpportunities(context.Background(), &buf, goldenBookmakers(), opts, defaultOutputOptions, nil)
+		if run == 0 {
+			first = buf.Bytes()
+		} else if !bytes.Equal(buf.Bytes(), first) {
+			t.Fatalf("run %d output differs from the first run", run)
+		}
+	}
+	checkGolden(t, "text_output.golden", first)
+}
+
+// Generate a seeded dataset whose bookmakers quote the same fixtures.
+// Generated bookmakers each get their own fixture IDs, so the IDs of the
+// first bookmaker are reused for the others.
+func seededGoldenBookmakers() []Bookmaker {
+	bookmakers := generateBookmakersWithSeed(3, 8, 42, NamesRandom)
+	for i := 1; i < len(bookmakers); i++ {
+		for j := range bookmakers[i].Games {
+			bookmakers[i].Games[j].ID = bookmakers[0].Games[j].ID
+			bookmakers[i].Games[j].TeamA = bookmakers[0].Games[j].TeamA
+			bookmakers[i].Games[j].TeamB = bookmakers[0].Games[j].TeamB
+		}
+	}
+	return bookmakers
+}
+
+func TestOutputFormatsGolden(t *testing.T) {
+	bookmakers := seededGoldenBookmakers()
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1}
+	scannedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	opportunities := collectOpportunities(context.Background(), bookmakers, opts, false)
+	if len(opportunities) == 0 {
+		t.Fatal("the seeded dataset has no arbitrage to compare output on")
+	}
+
+	tests := []struct {
+		golden string
+		write  func(w *bytes.Buffer) error
+	}{
+		{golden: "seeded_text.golden", write: func(w *bytes.Buffer) error {
+			findArbitrageOpportunities(context.Background(), w, bookmakers, opts, defaultOutputOptions, nil)
+			return nil
+		}},
+		{golden: "seeded_json.golden", write: func(w *bytes.Buffer) error {
+			_, err := writeOpportunitiesJSON(context.Background(), w, bookmakers, opts, false, nil)
+			return err
+		}},
+		{golden: "seeded_ndjson.golden", write: func(w *bytes.Buffer) error {
+			return streamOpportunitiesNDJSON(w, sendOpportunities(context.Background(), opportunities), scannedAt)
+		}},
+		{golden: "seeded_markdown.golden", write: func(w *bytes.Buffer) error {
+			return writeOpportunitiesMarkdown(w, opportunities, defaultOutputOptions)
+		}},
+		{golden: "seeded_data_csv.golden", write: func(w *bytes.Buffer) error {
+			return encodeBookmakersCSV(w, bookmakers)
+		}},
+		{golden: "seeded_data_json.golden", write: func(w *bytes.Buffer) error {
+			filename := filepath.Join(t.TempDir(), "bookmakers.json")
+			if err := writeBookmakersToFile(bookmakers, filename); err != nil {
+				return err
+			}
+			data, err := os.ReadFile(filename)
+			w.Write(data)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, tt.golden, buf.Bytes())
+		})
+	}
+}
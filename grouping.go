@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Header of the group holding opportunities without a sport
+const noSportGroup = "No sport"
+
+// Define the structure for the opportunities on one sport
+type SportGroup struct {
+	Sport         string                 `json:"sport"`
+	Opportunities []ArbitrageOpportunity `json:"opportunities"`
+}
+
+// Group opportunities by sport, with sports in name order and opportunities
+// without a sport last. Each group is sorted by guaranteed profit, highest
+// first.
+func groupOpportunitiesBySport(opportunities []ArbitrageOpportunity) []SportGroup {
+	bySport := make(map[string][]ArbitrageOpportunity)
+	for _, opportunity := range opportunities {
+		bySport[opportunity.Sport] = append(bySport[opportunity.Sport], opportunity)
+	}
+	groups := make([]SportGroup, 0, len(bySport))
+	for sport, grouped := range bySport {
+		sortOpportunities(grouped, SortByProfit)
+		groups = append(groups, SportGroup{Sport: sport, Opportunities: grouped})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Sport == "") != (groups[j].Sport == "") {
+			return groups[j].Sport == ""
+		}
+		return groups[i].Sport < groups[j].Sport
+	})
+	return groups
+}
+
+// Print opportunities in sections under a header per sport
+func printOpportunitiesBySport(w io.Writer, opportunities []ArbitrageOpportunity, out outputOptions) {
+	for _, group := range groupOpportunitiesBySport(opportunities) {
+		header := group.Sport
+		if header == "" {
+			header = noSportGroup
+		}
+		fmt.Fprintf(w, "== %s: %d opportunities ==\n\n", header, len(group.Opportunities))
+		for _, opportunity := range group.Opportunities {
+			printArbitrageOpportunity(w, opportunity, out)
+		}
+	}
+}
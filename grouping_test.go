@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// Opportunities across two sports and one without a sport
+func sportOpportunities() []ArbitrageOpportunity {
+	return []ArbitrageOpportunity{
+		{GameID: "t1", Sport: "tennis", GuaranteedProfit: 1},
+		{GameID: "s1", Sport: "soccer", GuaranteedProfit: 2},
+		{GameID: "u1", GuaranteedProfit: 9},
+		{GameID: "t2", Sport: "tennis", GuaranteedProfit: 5},
+		{GameID: "s2", Sport: "soccer", GuaranteedProfit: 3},
+	}
+}
+
+func TestGroupOpportunitiesBySport(t *testing.T) {
+	groups := groupOpportunitiesBySport(sportOpportunities())
+	want := []struct {
+		sport   string
+		gameIDs []string
+	}{
+		{sport: "soccer", gameIDs: []string{"s2", "s1"}},
+		{sport: "tennis", gameIDs: []string{"t2", "t1"}},
+		{sport: "", gameIDs: []string{"u1"}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for i, group := range groups {
+		if group.Sport != want[i].sport || !reflect.DeepEqual(opportunityGameIDs(group.Opportunities), want[i].gameIDs) {
+			t.Errorf("group %d = %q %v, want %q %v", i, group.Sport, opportunityGameIDs(group.Opportunities), want[i].sport, want[i].gameIDs)
+		}
+	}
+	if got := groupOpportunitiesBySport(nil); len(got) != 0 {
+		t.Errorf("grouping nothing gave %+v", got)
+	}
+}
+
+func TestPrintOpportunitiesBySport(t *testing.T) {
+	var buf bytes.Buffer
+	printOpportunitiesBySport(&buf, sportOpportunities(), defaultOutputOptions)
+	sections := regexp.MustCompile(`(?m)^== (.+) ==$|^Arbitrage opportunity found for game (\S+)$`).FindAllStringSubmatch(buf.String(), -1)
+	var got []string
+	for _, match := range sections {
+		got = append(got, match[1]+match[2])
+	}
+	want := []string{"soccer: 2 opportunities", "s2", "s1", "tennis: 2 opportunities", "t2", "t1", noSportGroup + ": 1 opportunities", "u1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sections = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/cvele/sports-betting-arbitrage/proto/oddspb"
+)
+
+//go:generate protoc --go_out=. --go_opt=module=github.com/cvele/sports-betting-arbitrage --go-grpc_out=. --go-grpc_opt=module=github.com/cvele/sports-betting-arbitrage proto/odds.proto
+
+// Define the options for ingesting odds from the gRPC odds service
+type grpcSourceOptions struct {
+	Address string
+	// Attempts to make before giving up, and the delay before the first
+	// retry, which doubles after each further failure
+	MaxAttempts int
+	BaseDelay   time.Duration
+	// Extra options for the connection, such as a custom dialer
+	DialOptions []grpc.DialOption
+}
+
+// Convert odds received from the odds service, which may be unset
+func oddsFromProto(odds *oddspb.Odds) Odds {
+	return Odds{Win: odds.GetWin(), Draw: odds.GetDraw(), Lose: odds.GetLose()}
+}
+
+// Convert a game received from the odds service, which is available unless
+// marked suspended
+func gameFromProto(game *oddspb.Game) Game {
+	converted := Game{
+		ID:        game.GetId(),
+		TeamA:     game.GetTeamA(),
+		TeamB:     game.GetTeamB(),
+		Odds:      oddsFromProto(game.GetOdds()),
+		EventAt:   game.GetEventAt(),
+		Sport:     game.GetSport(),
+		League:    game.GetLeague(),
+		Metadata:  game.GetMetadata(),
+		Available: !game.GetSuspended(),
+	}
+	if game.GetDrawNoBet() != nil {
+		drawNoBet := oddsFromProto(game.GetDrawNoBet())
+		converted.DrawNoBet = &drawNoBet
+	}
+	return converted
+}
+
+// Convert a bookmaker received from the odds service with its games
+func bookmakerFromProto(bookmaker *oddspb.Bookmaker) Bookmaker {
+	converted := Bookmaker{
+		Name:         bookmaker.GetName(),
+		Reliability:  bookmaker.GetReliability(),
+		LinkTemplate: bookmaker.GetLinkTemplate(),
+		Currency:     bookmaker.GetCurrency(),
+		MaxPayout:    bookmaker.GetMaxPayout(),
+		MaxStakes:    bookmaker.GetMaxStakes(),
+		MinStake:     bookmaker.GetMinStake(),
+	}
+	for _, game := range bookmaker.GetGames() {
+		converted.Games = append(converted.Games, gameFromProto(game))
+	}
+	return converted
+}
+
+// Receive every bookmaker from one call of the streaming RPC
+func streamBookmakersOnce(ctx context.Context, conn *grpc.ClientConn) ([]Bookmaker, error) {
+	// The stream's goroutines only exit once it is drained or its context is
+	// cancelled, so cancel it on every return
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := oddspb.NewOddsServiceClient(conn).StreamBookmakers(ctx, &oddspb.StreamBookmakersRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var bookmakers []Bookmaker
+	for {
+		bookmaker, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return bookmakers, nil
+			}
+			return nil, err
+		}
+		bookmakers = append(bookmakers, bookmakerFromProto(bookmaker))
+	}
+}
+
+// Report whether a gRPC error is worth retrying, such as a dropped
+// connection or an interrupted stream
+func isRetryableGRPCError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted, codes.ResourceExhausted, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+		return true
+	}
+	return false
+}
+
+// Read bookmakers from the gRPC odds service. An interrupted stream is
+// restarted from the beginning, discarding what it had sent, after a delay
+// that doubles with each failed attempt.
+func readBookmakersFromGRPC(ctx context.Context, opts grpcSourceOptions) ([]Bookmaker, error) {
+	dialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts.DialOptions...)
+	conn, err := grpc.NewClient(opts.Address, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", opts.Address, err)
+	}
+	defer conn.Close()
+
+	attempts := max(opts.MaxAttempts, 1)
+	delay := opts.BaseDelay
+	for attempt := 1; ; attempt++ {
+		bookmakers, err := streamBookmakersOnce(ctx, conn)
+		if err == nil {
+			if len(bookmakers) == 0 {
+				return nil, fmt.Errorf("%s: %w", opts.Address, ErrNoData)
+			}
+			return bookmakers, nil
+		}
+		if attempt >= attempts || !isRetryableGRPCError(err) {
+			return nil, fmt.Errorf("streaming bookmakers from %s: %w", opts.Address, err)
+		}
+		warn("streaming bookmakers from %s failed (attempt %d of %d), retrying in %v: %v", opts.Address, attempt, attempts, delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		delay *= 2
+	}
+}
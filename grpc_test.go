@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/cvele/sports-betting-arbitrage/proto/oddspb"
+)
+
+// Convert odds to the form sent by the odds service
+func oddsToProto(odds Odds) *oddspb.Odds {
+	return &oddspb.Odds{Win: odds.Win, Draw: odds.Draw, Lose: odds.Lose}
+}
+
+// Convert a bookmaker to the form sent by the odds service
+func bookmakerToProto(bookmaker Bookmaker) *oddspb.Bookmaker {
+	converted := &oddspb.Bookmaker{
+		Name:         bookmaker.Name,
+		Reliability:  bookmaker.Reliability,
+		LinkTemplate: bookmaker.LinkTemplate,
+		Currency:     bookmaker.Currency,
+		MaxPayout:    bookmaker.MaxPayout,
+		MaxStakes:    bookmaker.MaxStakes,
+		MinStake:     bookmaker.MinStake,
+	}
+	for _, game := range bookmaker.Games {
+		sent := &oddspb.Game{
+			Id:        game.ID,
+			TeamA:     game.TeamA,
+			TeamB:     game.TeamB,
+			Odds:      oddsToProto(game.Odds),
+			EventAt:   game.EventAt,
+			Sport:     game.Sport,
+			Suspended: !game.Available,
+			League:    game.League,
+			Metadata:  game.Metadata,
+		}
+		if game.DrawNoBet != nil {
+			sent.DrawNoBet = oddsToProto(*game.DrawNoBet)
+		}
+		converted.Games = append(converted.Games, sent)
+	}
+	return converted
+}
+
+// Define an in-process odds service streaming fixed bookmakers. The first
+// failures calls are interrupted with err after sending one bookmaker.
+type fakeOddsService struct {
+	oddspb.UnimplementedOddsServiceServer
+	bookmakers []Bookmaker
+	failures   int
+	err        error
+
+	mu    sync.Mutex
+	calls int
+}
+
+// Stream the bookmakers, failing the first calls
+func (s *fakeOddsService) StreamBookmakers(_ *oddspb.StreamBookmakersRequest, stream oddspb.OddsService_StreamBookmakersServer) error {
+	s.mu.Lock()
+	s.calls++
+	fail := s.calls <= s.failures
+	s.mu.Unlock()
+	for i, bookmaker := range s.bookmakers {
+		if fail && i == 1 {
+			return s.err
+		}
+		if err := stream.Send(bookmakerToProto(bookmaker)); err != nil {
+			return err
+		}
+	}
+	if fail {
+		return s.err
+	}
+	return nil
+}
+
+// Serve an odds service over an in-memory listener, returning the options
+// that dial it
+func serveOddsService(t *testing.T, service *fakeOddsService) grpcSourceOptions {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	oddspb.RegisterOddsServiceServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }
+	return grpcSourceOptions{
+		Address:     "passthrough:///bufnet",
+		MaxAttempts: 3,
+		DialOptions: []grpc.DialOption{grpc.WithContextDialer(dialer)},
+	}
+}
+
+// Bookmakers using every field carried by the odds service
+func grpcBookmakers() []Bookmaker {
+	return []Bookmaker{
+		{Name: "a", Reliability: 0.9, LinkTemplate: "https://a.example/{id}", Currency: "EUR",
+			MaxPayout: 5000, MaxStakes: map[string]float64{"default": 200}, MinStake: 1,
+			Games: []Game{{ID: "g1", TeamA: "Home", TeamB: "Away", Odds: Odds{Win: 2.55, Draw: 3.1, Lose: 3.05},
+				EventAt: "2024-05-01T19:00:00Z", Sport: "soccer", League: "Premier League",
+				DrawNoBet: &Odds{Win: 1.85, Lose: 2.1}, Metadata: map[string]string{"news": "keeper injured"}, Available: true}}},
+		{Name: "b", Games: []Game{
+			{ID: "g1", TeamA: "Home", TeamB: "Away", Odds: Odds{Win: 2.4, Draw: 3.6, Lose: 3.2}, Available: true},
+			{ID: "g2", TeamA: "Left", TeamB: "Right", Odds: Odds{Win: 1.9, Lose: 1.95}, Sport: "tennis"},
+		}},
+	}
+}
+
+func TestReadBookmakersFromGRPC(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	interrupted := status.Error(codes.Unavailable, "connection reset")
+	tests := []struct {
+		name      string
+		service   *fakeOddsService
+		want      []Bookmaker
+		wantErr   error
+		wantCode  codes.Code
+		wantCalls int
+	}{
+		{name: "every field", service: &fakeOddsService{bookmakers: grpcBookmakers()}, want: grpcBookmakers(), wantCalls: 1},
+		{name: "interrupted stream restarts", service: &fakeOddsService{bookmakers: grpcBookmakers(), failures: 2, err: interrupted},
+			want: grpcBookmakers(), wantCalls: 3},
+		{name: "gives up after max attempts", service: &fakeOddsService{bookmakers: grpcBookmakers(), failures: 3, err: interrupted},
+			wantCode: codes.Unavailable, wantCalls: 3},
+		{name: "permanent error is not retried", service: &fakeOddsService{bookmakers: grpcBookmakers(), failures: 1, err: status.Error(codes.PermissionDenied, "no")},
+			wantCode: codes.PermissionDenied, wantCalls: 1},
+		{name: "empty stream", service: &fakeOddsService{}, wantErr: ErrNoData, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := serveOddsService(t, tt.service)
+			got, err := readBookmakersFromGRPC(context.Background(), opts)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantCode != codes.OK:
+				if status.Code(errors.Unwrap(err)) != tt.wantCode {
+					t.Errorf("error = %v, want code %v", err, tt.wantCode)
+				}
+			case err != nil:
+				t.Fatal(err)
+			case !reflect.DeepEqual(got, tt.want):
+				t.Errorf("received %+v, want %+v", got, tt.want)
+			}
+			if tt.service.calls != tt.wantCalls {
+				t.Errorf("service called %d times, want %d", tt.service.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestReadBookmakersFromGRPCCancelled(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	opts := serveOddsService(t, &fakeOddsService{bookmakers: grpcBookmakers(), failures: 1, err: status.Error(codes.Unavailable, "down")})
+	opts.BaseDelay = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if _, err := readBookmakersFromGRPC(ctx, opts); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Width of the longest bar in a printed histogram
+const histogramWidth = 40
+
+// Count values into equal-width buckets spanning the smallest to the largest
+// value. The largest value falls into the last bucket.
+func bucketize(values []float64, buckets int) (counts []int, min, max float64) {
+	if buckets < 1 || len(values) == 0 {
+		return nil, 0, 0
+	}
+	min, max = values[0], values[0]
+	for _, value := range values {
+		if value < min {
+			min = value
+		}
+		if value > max {
+			max = value
+		}
+	}
+
+	counts = make([]int, buckets)
+	width := (max - min) / float64(buckets)
+	for _, value := range values {
+		i := buckets - 1
+		if width > 0 {
+			i = int((value - min) / width)
+			if i >= buckets {
+				i = buckets - 1
+			}
+		}
+		counts[i]++
+	}
+	return counts, min, max
+}
+
+// Collect the arbitrage percentage of every fixture's best odds
+func arbitragePercentages(bookmakers []Bookmaker) []float64 {
+	var percentages []float64
+	sports := fixtureSports(bookmakers)
+	for gameID, odds := range findBestOdds(bookmakers) {
+		odds = oddsForSport(odds, sports[gameID])
+		if checkOdds(odds, sports[gameID]) == nil {
+			percentages = append(percentages, calculateArbitragePercentage(odds))
+		}
+	}
+	return percentages
+}
+
+// Count fixtures into buckets of arbitrage percentage, spanning the lowest to
+// the highest percentage in the dataset
+func arbitrageDistribution(bookmakers []Bookmaker, buckets int) []int {
+	counts, _, _ := bucketize(arbitragePercentages(bookmakers), buckets)
+	return counts
+}
+
+// Print bucket counts as an ASCII histogram with each bucket's range
+func printHistogram(w io.Writer, counts []int, min, max float64) {
+	largest := 0
+	for _, count := range counts {
+		if count > largest {
+			largest = count
+		}
+	}
+	width := (max - min) / float64(len(counts))
+	for i, count := range counts {
+		bar := 0
+		if largest > 0 {
+			bar = count * histogramWidth / largest
+		}
+		low := min + float64(i)*width
+		fmt.Fprintf(w, "%8.4f - %8.4f | %-*s %d\n", low, low+width, histogramWidth, strings.Repeat("#", bar), count)
+	}
+}
+
+// Print the distribution of arbitrage percentages across fixtures, with the
+// fraction of fixtures that are arbitrage opportunities
+func printArbitrageDistribution(w io.Writer, bookmakers []Bookmaker, buckets int) {
+	percentages := arbitragePercentages(bookmakers)
+	counts, min, max := bucketize(percentages, buckets)
+	if counts == nil {
+		fmt.Fprintln(w, "No fixtures to build a distribution from")
+		return
+	}
+
+	below := 0
+	for _, percentage := range percentages {
+		if percentage < 1 {
+			below++
+		}
+	}
+	fmt.Fprintln(w, "Arbitrage percentage distribution:")
+	printHistogram(w, counts, min, max)
+	fmt.Fprintf(w, "Below 1.00: %d of %d fixtures (%.2f%%)\n", below, len(percentages), float64(below)/float64(len(percentages))*100)
+}
+
+// Collect the guaranteed profit of every opportunity
+func guaranteedProfits(opps []ArbitrageOpportunity) []float64 {
+	profits := make([]float64, len(opps))
+	for i, opp := range opps {
+		profits[i] = opp.GuaranteedProfit
+	}
+	return profits
+}
+
+// Count opportunities into buckets of guaranteed profit, spanning the
+// smallest to the largest profit among them
+func profitHistogram(opps []ArbitrageOpportunity, buckets int) []int {
+	counts, _, _ := bucketize(guaranteedProfits(opps), buckets)
+	return counts
+}
+
+// Print the distribution of guaranteed profit across opportunities
+func printProfitDistribution(w io.Writer, opps []ArbitrageOpportunity, buckets int) {
+	counts, min, max := bucketize(guaranteedProfits(opps), buckets)
+	if counts == nil {
+		fmt.Fprintln(w, "No opportunities to build a profit distribution from")
+		return
+	}
+	fmt.Fprintln(w, "Guaranteed profit distribution:")
+	printHistogram(w, counts, min, max)
+}
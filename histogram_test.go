@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBucketize(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		buckets  int
+		want     []int
+		min, max float64
+	}{
+		{name: "no values", buckets: 3},
+		{name: "no buckets", values: []float64{1, 2}},
+		{name: "even spread", values: []float64{0, 1, 2, 3, 4, 5, 6, 7}, buckets: 4, want: []int{2, 2, 2, 2}, min: 0, max: 7},
+		{name: "largest in last bucket", values: []float64{0, 10}, buckets: 5, want: []int{1, 0, 0, 0, 1}, min: 0, max: 10},
+		{name: "all equal", values: []float64{3, 3, 3}, buckets: 2, want: []int{0, 3}, min: 3, max: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts, min, max := bucketize(tt.values, tt.buckets)
+			if !reflect.DeepEqual(counts, tt.want) || min != tt.min || max != tt.max {
+				t.Errorf("bucketize() = %v, %v, %v, want %v, %v, %v", counts, min, max, tt.want, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+// One bookmaker quoting fixtures with arbitrage percentages of about 0.83,
+// 0.95, 1.07 and 1.17, plus one with invalid odds that is left out
+func distributionBookmakers() []Bookmaker {
+	return []Bookmaker{{Name: "a", Games: []Game{
+		{ID: "g1", Odds: Odds{Win: 3, Draw: 4, Lose: 4}, Available: true},
+		{ID: "g2", Odds: Odds{Win: 3, Draw: 3, Lose: 3.5}, Available: true},
+		{ID: "g3", Odds: Odds{Win: 2, Draw: 3, Lose: 3}, Available: true},
+		{ID: "g4", Odds: Odds{Win: 2.5, Draw: 3, Lose: 3}, Available: true},
+		{ID: "bad", Odds: Odds{Win: 2.5, Draw: 0.5, Lose: 3}, Available: true},
+	}}}
+}
+
+func TestArbitrageDistribution(t *testing.T) {
+	tests := []struct {
+		buckets int
+		want    []int
+	}{
+		{buckets: 1, want: []int{4}},
+		{buckets: 2, want: []int{2, 2}},
+		{buckets: 4, want: []int{1, 1, 1, 1}},
+	}
+	for _, tt := range tests {
+		if got := arbitrageDistribution(distributionBookmakers(), tt.buckets); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("arbitrageDistribution(%d) = %v, want %v", tt.buckets, got, tt.want)
+		}
+	}
+}
+
+func TestPrintArbitrageDistribution(t *testing.T) {
+	var buf bytes.Buffer
+	printArbitrageDistribution(&buf, distributionBookmakers(), 2)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want a title, 2 buckets and a summary:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[1], "  0.8333 -   1.0000 | ") || !strings.HasSuffix(lines[1], " 2") {
+		t.Errorf("first bucket = %q", lines[1])
+	}
+	if want := "Below 1.00: 2 of 4 fixtures (50.00%)"; lines[3] != want {
+		t.Errorf("summary = %q, want %q", lines[3], want)
+	}
+
+	buf.Reset()
+	printArbitrageDistribution(&buf, nil, 2)
+	if want := "No fixtures to build a distribution from\n"; buf.String() != want {
+		t.Errorf("empty output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestProfitHistogram(t *testing.T) {
+	// Mostly tiny arbitrages with one large one
+	opps := []ArbitrageOpportunity{
+		{GameID: "g1", GuaranteedProfit: 0.5},
+		{GameID: "g2", GuaranteedProfit: 1},
+		{GameID: "g3", GuaranteedProfit: 1.5},
+		{GameID: "g4", GuaranteedProfit: 2},
+		{GameID: "g5", GuaranteedProfit: 10.5},
+	}
+	tests := []struct {
+		buckets int
+		want    []int
+	}{
+		{buckets: 0},
+		{buckets: 1, want: []int{5}},
+		{buckets: 2, want: []int{4, 1}},
+		{buckets: 5, want: []int{4, 0, 0, 0, 1}},
+	}
+	for _, tt := range tests {
+		if got := profitHistogram(opps, tt.buckets); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("profitHistogram(%d) = %v, want %v", tt.buckets, got, tt.want)
+		}
+	}
+	if got := profitHistogram(nil, 3); got != nil {
+		t.Errorf("profitHistogram(nil) = %v, want nil", got)
+	}
+
+	var buf bytes.Buffer
+	printProfitDistribution(&buf, opps, 2)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || lines[0] != "Guaranteed profit distribution:" {
+		t.Fatalf("got %q, want a title and 2 buckets", buf.String())
+	}
+	if !strings.HasPrefix(lines[1], "  0.5000 -   5.5000 | ") || !strings.HasSuffix(lines[1], " 4") {
+		t.Errorf("first bucket = %q", lines[1])
+	}
+	buf.Reset()
+	printProfitDistribution(&buf, nil, 2)
+	if want := "No opportunities to build a profit distribution from\n"; buf.String() != want {
+		t.Errorf("empty output = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Define the structure for the bookmaker odds observed at a point in time
+type Snapshot struct {
+	TakenAt    time.Time   `json:"taken_at"`
+	Bookmakers []Bookmaker `json:"bookmakers"`
+}
+
+// Define the structure for a series of snapshots ordered by time
+type History struct {
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// Add a snapshot to the history, keeping snapshots ordered by time
+func (h *History) Add(snapshot Snapshot) {
+	i := sort.Search(len(h.Snapshots), func(i int) bool {
+		return h.Snapshots[i].TakenAt.After(snapshot.TakenAt)
+	})
+	h.Snapshots = append(h.Snapshots, Snapshot{})
+	copy(h.Snapshots[i+1:], h.Snapshots[i:])
+	h.Snapshots[i] = snapshot
+}
+
+// Return the most recent snapshot, if any
+func (h *History) Latest() (Snapshot, bool) {
+	if len(h.Snapshots) == 0 {
+		return Snapshot{}, false
+	}
+	return h.Snapshots[len(h.Snapshots)-1], true
+}
+
+// Count the fixtures whose best odds across bookmakers are an arbitrage in
+// a snapshot, skipping fixtures with invalid odds as detection does
+func snapshotArbitrageCount(snapshot Snapshot) int {
+	opportunities, _ := detectArbitrageOpportunities(context.Background(), snapshot.Bookmakers, scanOptions{ArbThreshold: 1})
+	return len(opportunities)
+}
+
+// Calculate how many arbitrage opportunities appear per snapshot on average
+// across the history, zero without snapshots
+func arbitrageRatePerSnapshot(history History) float64 {
+	if len(history.Snapshots) == 0 {
+		return 0
+	}
+	total := 0
+	for _, snapshot := range history.Snapshots {
+		total += snapshotArbitrageCount(snapshot)
+	}
+	return float64(total) / float64(len(history.Snapshots))
+}
+
+// Calculate how many arbitrage opportunities appear per hour across the
+// history, a measure of how often a data source offers them. Opportunities
+// are counted in every snapshot, so one lasting several snapshots counts
+// each time, and the rate is over the time from the first snapshot to the
+// last. Histories spanning no time have no rate and return zero.
+func arbitrageRate(history History) float64 {
+	if len(history.Snapshots) < 2 {
+		return 0
+	}
+	first, last := history.Snapshots[0].TakenAt, history.Snapshots[len(history.Snapshots)-1].TakenAt
+	hours := last.Sub(first).Hours()
+	if hours <= 0 {
+		return 0
+	}
+	return arbitrageRatePerSnapshot(history) * float64(len(history.Snapshots)) / hours
+}
+
+// Define the structure for the state of a back-test after one snapshot
+type EquityPoint struct {
+	At time.Time `json:"at"`
+	// Opportunities taken in this snapshot and the profit they lock in
+	Opportunities int     `json:"opportunities"`
+	Profit        float64 `json:"profit"`
+	// Profit locked in by every opportunity taken so far
+	Cumulative float64 `json:"cumulative"`
+}
+
+// Define the structure for the result of back-testing over a history
+type BacktestResult struct {
+	Opportunities int           `json:"opportunities"`
+	TotalProfit   float64       `json:"total_profit"`
+	EquityCurve   []EquityPoint `json:"equity_curve"`
+}
+
+// Replay a history in time order, simulating taking every arbitrage found
+// in each snapshot with totalBetPerOpp staked, and accumulate the guaranteed
+// profit into an equity curve with a point per snapshot. A fixture is taken
+// once, in the first snapshot it is an arbitrage: the position is already
+// hedged when it shows up again. Fills are assumed at the quoted odds
+// without limits or rounding.
+func backtest(history History, totalBetPerOpp float64) BacktestResult {
+	opts := scanOptions{TotalBet: totalBetPerOpp, ArbThreshold: 1}
+	taken := make(map[string]bool)
+	var result BacktestResult
+	for _, snapshot := range history.Snapshots {
+		point := EquityPoint{At: snapshot.TakenAt}
+		opportunities, _ := detectArbitrageOpportunities(context.Background(), snapshot.Bookmakers, opts)
+		for _, opportunity := range opportunities {
+			if taken[opportunity.GameID] {
+				continue
+			}
+			taken[opportunity.GameID] = true
+			point.Opportunities++
+			point.Profit += opportunity.GuaranteedProfit
+		}
+		result.Opportunities += point.Opportunities
+		result.TotalProfit += point.Profit
+		point.Cumulative = result.TotalProfit
+		result.EquityCurve = append(result.EquityCurve, point)
+	}
+	return result
+}
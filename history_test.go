@@ -0,0 +1,121 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Build a snapshot with one bookmaker per odds, all quoting game g
+func oddsSnapshot(at time.Time, odds ...Odds) Snapshot {
+	snapshot := Snapshot{TakenAt: at}
+	for i, o := range odds {
+		snapshot.Bookmakers = append(snapshot.Bookmakers, Bookmaker{
+			Name:  string(rune('a' + i)),
+			Games: []Game{{ID: "g", Odds: o, Available: true}},
+		})
+	}
+	return snapshot
+}
+
+func TestSnapshotArbitrageCount(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		snapshot Snapshot
+		want     int
+	}{
+		{name: "arbitrage across bookmakers", snapshot: oddsSnapshot(at, Odds{Win: 3, Draw: 3.5, Lose: 2}, Odds{Win: 2, Draw: 4, Lose: 4}), want: 1},
+		{name: "no arbitrage", snapshot: oddsSnapshot(at, Odds{Win: 2, Draw: 3, Lose: 3}), want: 0},
+		// A negative price makes the implied total look tiny but is not a bet
+		{name: "invalid odds", snapshot: oddsSnapshot(at, Odds{Win: 2, Draw: 3, Lose: -1}), want: 0},
+		{name: "empty", snapshot: Snapshot{TakenAt: at}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snapshotArbitrageCount(tt.snapshot); got != tt.want {
+				t.Errorf("snapshotArbitrageCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArbitrageRate(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	arb := Odds{Win: 3, Draw: 4, Lose: 4}
+	none := Odds{Win: 2, Draw: 3, Lose: 3}
+	tests := []struct {
+		name        string
+		history     History
+		wantPerSnap float64
+		wantPerHour float64
+	}{
+		{name: "empty"},
+		{
+			name:        "single snapshot spans no time",
+			history:     History{Snapshots: []Snapshot{oddsSnapshot(start, arb)}},
+			wantPerSnap: 1,
+		},
+		{
+			name: "two arbitrages in four snapshots over two hours",
+			history: History{Snapshots: []Snapshot{
+				oddsSnapshot(start, arb),
+				oddsSnapshot(start.Add(40*time.Minute), none),
+				oddsSnapshot(start.Add(80*time.Minute), arb),
+				oddsSnapshot(start.Add(2*time.Hour), none),
+			}},
+			wantPerSnap: 0.5,
+			wantPerHour: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := arbitrageRatePerSnapshot(tt.history); got != tt.wantPerSnap {
+				t.Errorf("arbitrageRatePerSnapshot() = %v, want %v", got, tt.wantPerSnap)
+			}
+			if got := arbitrageRate(tt.history); got != tt.wantPerHour {
+				t.Errorf("arbitrageRate() = %v, want %v", got, tt.wantPerHour)
+			}
+		})
+	}
+}
+
+func TestBacktest(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := func(at time.Time, g1, g2 Odds) Snapshot {
+		return Snapshot{TakenAt: at, Bookmakers: []Bookmaker{{Name: "a", Games: []Game{
+			{ID: "g1", Odds: g1, Available: true},
+			{ID: "g2", Odds: g2, Available: true},
+		}}}}
+	}
+	// g1 books 5/6 for 20 profit on 100 and g2 later books 6/7 for 16.67;
+	// g1 is still an arbitrage in the second snapshot but already taken
+	arb20, arb1667, none := Odds{Win: 3, Draw: 4, Lose: 4}, Odds{Win: 3.5, Draw: 3.5, Lose: 3.5}, Odds{Win: 2, Draw: 3, Lose: 3}
+	var history History
+	history.Add(snapshot(start.Add(2*time.Hour), none, arb1667))
+	history.Add(snapshot(start, arb20, none))
+	history.Add(snapshot(start.Add(time.Hour), arb20, none))
+
+	result := backtest(history, 100)
+	want := []EquityPoint{
+		{At: start, Opportunities: 1, Profit: 20, Cumulative: 20},
+		{At: start.Add(time.Hour), Cumulative: 20},
+		{At: start.Add(2 * time.Hour), Opportunities: 1, Profit: 100/(6/7.0) - 100, Cumulative: 20 + 100/(6/7.0) - 100},
+	}
+	if len(result.EquityCurve) != len(want) {
+		t.Fatalf("equity curve = %+v, want %+v", result.EquityCurve, want)
+	}
+	for i, point := range result.EquityCurve {
+		if !point.At.Equal(want[i].At) || point.Opportunities != want[i].Opportunities ||
+			math.Abs(point.Profit-want[i].Profit) > 1e-9 || math.Abs(point.Cumulative-want[i].Cumulative) > 1e-9 {
+			t.Errorf("point %d = %+v, want %+v", i, point, want[i])
+		}
+	}
+	if result.Opportunities != 2 || math.Abs(result.TotalProfit-want[2].Cumulative) > 1e-9 {
+		t.Errorf("totals = %d opportunities for %v, want 2 for %v", result.Opportunities, result.TotalProfit, want[2].Cumulative)
+	}
+
+	if empty := backtest(History{}, 100); empty.Opportunities != 0 || empty.TotalProfit != 0 || len(empty.EquityCurve) != 0 {
+		t.Errorf("backtest of no history = %+v", empty)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// Define the structure for a position offered to the portfolio sizer: an
+// arbitrage, which cannot lose, or a value bet at decimal odds with an edge,
+// its expected profit per unit staked
+type PortfolioCandidate struct {
+	ID       string  `json:"id"`
+	Riskless bool    `json:"riskless"`
+	Odds     float64 `json:"odds,omitempty"`
+	Edge     float64 `json:"edge"`
+	// Reliability of the bookmakers involved, scaling down positions whose
+	// bets might be voided or limited; zero is treated as fully reliable
+	Reliability float64 `json:"reliability,omitempty"`
+}
+
+// Define the structure for a sized position
+type PortfolioPosition struct {
+	PortfolioCandidate
+	// Share of the bankroll full Kelly would stake on the position alone
+	Kelly float64 `json:"kelly"`
+	Stake float64 `json:"stake"`
+}
+
+// Turn arbitrage opportunities into portfolio candidates
+func arbitrageCandidates(opportunities []ArbitrageOpportunity) []PortfolioCandidate {
+	candidates := make([]PortfolioCandidate, 0, len(opportunities))
+	for _, opportunity := range opportunities {
+		candidates = append(candidates, PortfolioCandidate{
+			ID:          opportunity.ID,
+			Riskless:    true,
+			Edge:        opportunity.ReturnOnCapital,
+			Reliability: opportunity.Reliability,
+		})
+	}
+	return candidates
+}
+
+// Turn value bets into portfolio candidates
+func valueBetCandidates(bets []ValueBet) []PortfolioCandidate {
+	candidates := make([]PortfolioCandidate, 0, len(bets))
+	for _, bet := range bets {
+		candidates = append(candidates, PortfolioCandidate{
+			ID:   fmt.Sprintf("%s/%s/%s", bet.GameID, bet.Outcome, bet.Bookmaker),
+			Odds: bet.Odds,
+			Edge: bet.Edge,
+		})
+	}
+	return candidates
+}
+
+// Return the share of a bankroll the Kelly criterion stakes on a candidate.
+// A value bet at decimal odds o with edge e stakes e / (o - 1); an arbitrage
+// cannot lose, so Kelly would stake everything. Either is scaled by the
+// bookmakers' reliability.
+func kellyShare(candidate PortfolioCandidate) float64 {
+	if !(candidate.Edge > 0) {
+		return 0
+	}
+	share := 1.0
+	if !candidate.Riskless {
+		if !(candidate.Odds > 1) {
+			return 0
+		}
+		share = min(candidate.Edge/(candidate.Odds-1), 1)
+	}
+	if candidate.Reliability > 0 && candidate.Reliability < 1 {
+		share *= candidate.Reliability
+	}
+	return share
+}
+
+// Size simultaneous positions under a shared bankroll with fractional Kelly:
+// each is staked fraction times its Kelly share of the bankroll, and when
+// those stakes add up to more than the bankroll they are all scaled down in
+// proportion so the total fits. Candidates without an edge are left out.
+func sizePortfolio(candidates []PortfolioCandidate, bankroll, fraction float64) []PortfolioPosition {
+	var positions []PortfolioPosition
+	total := 0.0
+	for _, candidate := range candidates {
+		share := kellyShare(candidate)
+		if share == 0 {
+			continue
+		}
+		stake := fraction * share * bankroll
+		positions = append(positions, PortfolioPosition{PortfolioCandidate: candidate, Kelly: share, Stake: stake})
+		total += stake
+	}
+	if total > bankroll {
+		for i := range positions {
+			positions[i].Stake *= bankroll / total
+		}
+	}
+	return positions
+}
+
+// Print the sized positions of a portfolio
+func printPortfolio(w io.Writer, positions []PortfolioPosition, bankroll, fraction float64, out outputOptions) {
+	fmt.Fprintf(w, "Portfolio at %.2f Kelly of a %.*f bankroll:\n", fraction, out.Precision, bankroll)
+	total := 0.0
+	for _, position := range positions {
+		kind := "value bet"
+		if position.Riskless {
+			kind = "arbitrage"
+		}
+		fmt.Fprintf(w, "%s (%s): edge %.2f%%, stake %.*f\n", position.ID, kind, position.Edge*100, out.Precision, position.Stake)
+		total += position.Stake
+	}
+	fmt.Fprintf(w, "Staked %.*f of %.*f across %d positions\n", out.Precision, total, out.Precision, bankroll, len(positions))
+}
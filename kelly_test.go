@@ -0,0 +1,70 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKellyShare(t *testing.T) {
+	tests := []struct {
+		name      string
+		candidate PortfolioCandidate
+		want      float64
+	}{
+		{name: "value bet", candidate: PortfolioCandidate{Odds: 3, Edge: 0.2}, want: 0.1},
+		{name: "arbitrage", candidate: PortfolioCandidate{Riskless: true, Edge: 0.02}, want: 1},
+		{name: "unreliable arbitrage", candidate: PortfolioCandidate{Riskless: true, Edge: 0.02, Reliability: 0.5}, want: 0.5},
+		{name: "unreliable value bet", candidate: PortfolioCandidate{Odds: 3, Edge: 0.2, Reliability: 0.5}, want: 0.05},
+		{name: "capped at the bankroll", candidate: PortfolioCandidate{Odds: 1.1, Edge: 0.5}, want: 1},
+		{name: "no edge", candidate: PortfolioCandidate{Odds: 3, Edge: -0.1}},
+		{name: "odds without payout", candidate: PortfolioCandidate{Odds: 1, Edge: 0.2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kellyShare(tt.candidate); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("kellyShare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSizePortfolio(t *testing.T) {
+	// Kelly shares of 0.1, 0.05 and 0.5, with the losing bet left out
+	candidates := []PortfolioCandidate{
+		{ID: "v1", Odds: 3, Edge: 0.2},
+		{ID: "v2", Odds: 2, Edge: 0.05},
+		{ID: "losing", Odds: 2, Edge: -0.05},
+		{ID: "arb", Riskless: true, Edge: 0.03, Reliability: 0.5},
+	}
+	const bankroll = 1000
+	tests := []struct {
+		fraction  float64
+		wantTotal float64
+	}{
+		{fraction: 0.25, wantTotal: 162.5},
+		{fraction: 0.5, wantTotal: 325},
+		{fraction: 1, wantTotal: 650},
+		// Full stakes of 1300 are scaled down to fit the bankroll
+		{fraction: 2, wantTotal: bankroll},
+	}
+	for _, tt := range tests {
+		positions := sizePortfolio(candidates, bankroll, tt.fraction)
+		if len(positions) != 3 {
+			t.Fatalf("fraction %v sized %d positions, want 3", tt.fraction, len(positions))
+		}
+		total := 0.0
+		for _, position := range positions {
+			if position.ID == "losing" {
+				t.Errorf("fraction %v staked the losing bet", tt.fraction)
+			}
+			total += position.Stake
+		}
+		if math.Abs(total-tt.wantTotal) > 1e-9 {
+			t.Errorf("fraction %v staked %v in total, want %v", tt.fraction, total, tt.wantTotal)
+		}
+		// Stakes keep the proportions of the Kelly shares
+		if got := positions[2].Stake / positions[0].Stake; math.Abs(got-5) > 1e-9 {
+			t.Errorf("fraction %v staked the arbitrage %v times the first value bet, want 5", tt.fraction, got)
+		}
+	}
+}
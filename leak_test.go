@@ -0,0 +1,18 @@
+package main
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+// Fail t if goroutines it started are still running once it and its other
+// cleanups have finished. Call it first in a test so its check runs after
+// cleanups registered later, such as stopping a server. Goroutines already
+// running when it is called are ignored, so it works alongside parallel
+// tests and package-level workers.
+func verifyNoGoroutineLeaks(t *testing.T) {
+	t.Helper()
+	ignoreExisting := goleak.IgnoreCurrent()
+	t.Cleanup(func() { goleak.VerifyNone(t, ignoreExisting) })
+}
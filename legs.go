@@ -0,0 +1,58 @@
+package main
+
+import "sort"
+
+// Sport given to fixtures evaluated as two-way markets because they have no
+// sport and no bookmaker quotes their draw
+const sportTwoWay = "two-way"
+
+// Define the structure for a fixture skipped because no bookmaker quotes
+// some of the outcomes it needs
+type MissingLegs struct {
+	GameID   string   `json:"game_id"`
+	Outcomes []string `json:"outcomes"`
+}
+
+// Return the sport a fixture is evaluated as. Fixtures without a sport are
+// three-way by default; when twoWayWithoutDraw is set and no bookmaker
+// quotes their draw they are evaluated as two-way markets instead.
+func effectiveSport(sport string, best Odds, twoWayWithoutDraw bool) string {
+	if twoWayWithoutDraw && sport == "" && best.Draw == 0 {
+		return sportTwoWay
+	}
+	return sport
+}
+
+// Return the outcomes a fixture's sport needs that have no quote in its best
+// odds across bookmakers
+func missingLegs(best Odds, sport string) []string {
+	var missing []string
+	if best.Win == 0 {
+		missing = append(missing, OutcomeWin)
+	}
+	if outcomesForSport(sport).HasDraw && best.Draw == 0 {
+		missing = append(missing, OutcomeDraw)
+	}
+	if best.Lose == 0 {
+		missing = append(missing, OutcomeLose)
+	}
+	return missing
+}
+
+// Find the fixtures that cannot be an arbitrage because no bookmaker quotes
+// one of their required outcomes, in game ID order. Such fixtures are skipped
+// by detection; this reports them.
+func fixturesMissingLegs(bookmakers []Bookmaker, twoWayWithoutDraw bool) []MissingLegs {
+	sports := fixtureSports(bookmakers)
+	var fixtures []MissingLegs
+	for gameID, best := range findBestOdds(bookmakers) {
+		sport := effectiveSport(sports[gameID], best, twoWayWithoutDraw)
+		if missing := missingLegs(best, sport); len(missing) > 0 {
+			fixtures = append(fixtures, MissingLegs{GameID: gameID, Outcomes: missing})
+		}
+	}
+	sort.Slice(fixtures, func(i, j int) bool {
+		return fixtures[i].GameID < fixtures[j].GameID
+	})
+	return fixtures
+}
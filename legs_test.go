@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Bookmakers quoting fixtures with and without every leg
+func missingLegBookmakers() []Bookmaker {
+	games := []Game{
+		{ID: "complete", TeamA: "A", TeamB: "B", Odds: Odds{Win: 3, Draw: 4, Lose: 4}, Available: true},
+		{ID: "no-lose", TeamA: "A", TeamB: "B", Odds: Odds{Win: 3, Draw: 4}, Available: true},
+		{ID: "no-draw", TeamA: "A", TeamB: "B", Odds: Odds{Win: 2.1, Lose: 2.1}, Available: true},
+		{ID: "soccer-no-draw", TeamA: "A", TeamB: "B", Sport: "soccer", Odds: Odds{Win: 2.1, Lose: 2.1}, Available: true},
+		{ID: "tennis", TeamA: "A", TeamB: "B", Sport: "tennis", Odds: Odds{Win: 2.1, Lose: 2.1}, Available: true},
+	}
+	return []Bookmaker{{Name: "a", Games: games}, {Name: "b", Games: games}}
+}
+
+func TestFixturesMissingLegs(t *testing.T) {
+	tests := []struct {
+		name              string
+		twoWayWithoutDraw bool
+		want              []MissingLegs
+	}{
+		{name: "all legs required", want: []MissingLegs{
+			{GameID: "no-draw", Outcomes: []string{OutcomeDraw}},
+			{GameID: "no-lose", Outcomes: []string{OutcomeLose}},
+			{GameID: "soccer-no-draw", Outcomes: []string{OutcomeDraw}},
+		}},
+		// Only fixtures without a sport may drop the draw
+		{name: "two-way without a draw", twoWayWithoutDraw: true, want: []MissingLegs{
+			{GameID: "no-lose", Outcomes: []string{OutcomeLose}},
+			{GameID: "soccer-no-draw", Outcomes: []string{OutcomeDraw}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fixturesMissingLegs(missingLegBookmakers(), tt.twoWayWithoutDraw); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fixturesMissingLegs() = %+v, want %+v", got, tt.want)
+			}
+			detected, _ := detectArbitrageOpportunities(context.Background(), missingLegBookmakers(),
+				scanOptions{TotalBet: 100, ArbThreshold: 1, TwoWayWithoutDraw: tt.twoWayWithoutDraw})
+			for _, opportunity := range detected {
+				for _, fixture := range tt.want {
+					if opportunity.GameID == fixture.GameID {
+						t.Errorf("detected %s despite its missing %v", fixture.GameID, fixture.Outcomes)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRunReportsMissingLegs(t *testing.T) {
+	file := writeTestData(t, missingLegBookmakers())
+	_, stdout, stderr := runCLI(t, "-file", file)
+	if !strings.Contains(stderr, "Warning: game no-lose: skipped, no bookmaker quotes "+OutcomeLose) {
+		t.Errorf("stderr = %q, want no-lose reported", stderr)
+	}
+	if strings.Contains(stdout, "game no-draw\n") {
+		t.Errorf("stdout reports no-draw while all legs are required:\n%s", stdout)
+	}
+	_, stdout, _ = runCLI(t, "-require-all-legs=false", "-file", file)
+	if !strings.Contains(stdout, "found for game no-draw\n") {
+		t.Errorf("stdout does not report no-draw as a two-way market:\n%s", stdout)
+	}
+}
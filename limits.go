@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// Approximate per-game memory beyond the Game struct itself: the generated
+// string contents plus the indented JSON produced when the data is written.
+const (
+	approxGameStringBytes = 64
+	approxGameJSONBytes   = 220
+)
+
+// Approximate size of one generated game in an indented JSON file, measured
+// on generated data. Realistic names add the sport and league.
+const (
+	approxGameFileBytes          = 281
+	approxRealisticGameFileBytes = 351
+)
+
+// Estimate the size of the JSON file a dataset is written to, in bytes
+func estimateGenerationFileSize(numBookmakers, numGamesPerBookmaker int, names NameMode) uint64 {
+	perGame := uint64(approxGameFileBytes)
+	if names == NamesRealistic {
+		perGame = approxRealisticGameFileBytes
+	}
+	return uint64(numBookmakers) * uint64(numGamesPerBookmaker) * perGame
+}
+
+// Print what generating a dataset would produce, without generating it: the
+// bookmaker and game counts, the estimated file size and memory, and whether
+// the size check would refuse it
+func printGenerationDryRun(w io.Writer, filename string, numBookmakers, numGamesPerBookmaker int, names NameMode, limitMB uint64) {
+	fmt.Fprintf(w, "Dry run: would generate %d bookmakers with %d games each (%d games) to %s\n",
+		numBookmakers, numGamesPerBookmaker, numBookmakers*numGamesPerBookmaker, filename)
+	if err := checkGenerationSize(numBookmakers, numGamesPerBookmaker, limitMB); err != nil {
+		fmt.Fprintln(w, "Generation would be refused:", err)
+		return
+	}
+	fmt.Fprintf(w, "Estimated file size: %.1f MB\n", float64(estimateGenerationFileSize(numBookmakers, numGamesPerBookmaker, names))/(1<<20))
+	fmt.Fprintf(w, "Estimated memory: %.1f MB\n", float64(estimateGenerationMemory(numBookmakers, numGamesPerBookmaker))/(1<<20))
+}
+
+// Estimate the memory needed to generate and write a dataset, in bytes
+func estimateGenerationMemory(numBookmakers, numGamesPerBookmaker int) uint64 {
+	perGame := uint64(unsafe.Sizeof(Game{})) + approxGameStringBytes + approxGameJSONBytes
+	return uint64(numBookmakers) * uint64(numGamesPerBookmaker) * perGame
+}
+
+// Check that a generation request is sane and fits within limitMB megabytes.
+// A limit of zero disables the memory check. Oversized requests are pointed
+// at streaming odds from a feed, which never holds a generated dataset.
+func checkGenerationSize(numBookmakers, numGamesPerBookmaker int, limitMB uint64) error {
+	if numBookmakers <= 0 || numGamesPerBookmaker <= 0 {
+		return fmt.Errorf("bookmakers and games must be positive, got %d and %d", numBookmakers, numGamesPerBookmaker)
+	}
+	if limitMB == 0 {
+		return nil
+	}
+	estimatedMB := estimateGenerationMemory(numBookmakers, numGamesPerBookmaker) / (1 << 20)
+	if estimatedMB > limitMB {
+		return fmt.Errorf("generating %d bookmakers with %d games each needs about %d MB, above the %d MB limit; "+
+			"generate fewer games, raise -max-memory, or stream odds with -source grpc instead of generating them", numBookmakers, numGamesPerBookmaker, estimatedMB, limitMB)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckGenerationSize(t *testing.T) {
+	tests := []struct {
+		name              string
+		bookmakers, games int
+		limitMB           uint64
+		wantErr           string
+	}{
+		{name: "within limit", bookmakers: 2, games: 10, limitMB: 1},
+		{name: "no limit", bookmakers: 100, games: 1000000, limitMB: 0},
+		{name: "oversized with a low limit", bookmakers: 100, games: 10000, limitMB: 1, wantErr: "above the 1 MB limit"},
+		{name: "no bookmakers", bookmakers: 0, games: 10, limitMB: 1, wantErr: "must be positive"},
+		{name: "negative games", bookmakers: 2, games: -1, wantErr: "must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkGenerationSize(tt.bookmakers, tt.games, tt.limitMB)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkGenerationSize() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkGenerationSize() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Refusals point at the streaming source rather than only at the limit
+	err := checkGenerationSize(100, 10000, 1)
+	if err == nil || !strings.Contains(err.Error(), "stream odds with -source grpc") {
+		t.Errorf("checkGenerationSize() error = %v, want the streaming suggestion", err)
+	}
+}
+
+func TestEstimateGenerationSizesScale(t *testing.T) {
+	small := estimateGenerationMemory(1, 100)
+	if got := estimateGenerationMemory(10, 100); got != 10*small {
+		t.Errorf("memory for 10 bookmakers = %d, want %d", got, 10*small)
+	}
+	plain := estimateGenerationFileSize(2, 100, NamesRandom)
+	realistic := estimateGenerationFileSize(2, 100, NamesRealistic)
+	if plain != 2*100*approxGameFileBytes || realistic <= plain {
+		t.Errorf("file sizes = %d and %d, want realistic names to be larger", plain, realistic)
+	}
+}
+
+func TestPrintGenerationDryRun(t *testing.T) {
+	var buf bytes.Buffer
+	printGenerationDryRun(&buf, "odds.json", 2, 10, NamesRandom, 1)
+	if !strings.Contains(buf.String(), "(20 games) to odds.json") || !strings.Contains(buf.String(), "Estimated memory:") {
+		t.Errorf("dry run output = %q", buf.String())
+	}
+
+	buf.Reset()
+	printGenerationDryRun(&buf, "odds.json", 100, 10000, NamesRandom, 1)
+	if !strings.Contains(buf.String(), "Generation would be refused:") || strings.Contains(buf.String(), "Estimated") {
+		t.Errorf("oversized dry run output = %q", buf.String())
+	}
+}
+
+func TestRunDryRunWritesNothing(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "odds.json")
+	code, stdout, _ := runCLI(t, "-dry-run", "-bookmakers", "3", "-games", "50", "-file", filename)
+	if code != exitNoOpportunities {
+		t.Errorf("exit code = %d, want %d", code, exitNoOpportunities)
+	}
+	if !strings.Contains(stdout, "(150 games) to "+filename) {
+		t.Errorf("stdout = %q, want the planned counts", stdout)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Errorf("dry run left %d files, %v, want none", len(entries), err)
+	}
+
+	existing := writeTestData(t, plantedBookmakers())
+	before, err := os.ReadFile(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, stdout, _ = runCLI(t, "-dry-run", "-file", existing)
+	if !strings.Contains(stdout, "exists and would be read") || strings.Contains(stdout, "Arbitrage opportunity found") {
+		t.Errorf("stdout = %q, want the file left unread", stdout)
+	}
+	if after, _ := os.ReadFile(existing); !bytes.Equal(after, before) {
+		t.Error("dry run changed the existing file")
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Resolve a bookmaker link template for a game. The {book} and {id}
+// placeholders are replaced with the path-escaped bookmaker name and game ID.
+// An empty template resolves to an empty link.
+func resolveLink(template, bookmaker, gameID string) string {
+	if template == "" {
+		return ""
+	}
+	replacer := strings.NewReplacer("{book}", url.PathEscape(bookmaker), "{id}", url.PathEscape(gameID))
+	return replacer.Replace(template)
+}
+
+// Map bookmaker names to their link templates
+func bookmakerLinkTemplates(bookmakers []Bookmaker) map[string]string {
+	templates := make(map[string]string, len(bookmakers))
+	for _, bookmaker := range bookmakers {
+		if bookmaker.LinkTemplate != "" {
+			templates[bookmaker.Name] = bookmaker.LinkTemplate
+		}
+	}
+	return templates
+}
+
+// Resolve a link for each leg of an opportunity keyed by outcome. Legs at
+// bookmakers without a template are left out.
+func opportunityLinks(opportunity ArbitrageOpportunity, templates map[string]string) map[string]string {
+	var links map[string]string
+	for _, leg := range opportunityLegs(opportunity) {
+		link := resolveLink(templates[leg.Bookmaker], leg.Bookmaker, opportunity.GameID)
+		if link == "" {
+			continue
+		}
+		if links == nil {
+			links = make(map[string]string)
+		}
+		links[leg.Outcome] = link
+	}
+	return links
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveLink(t *testing.T) {
+	tests := []struct {
+		template, bookmaker, gameID string
+		want                        string
+	}{
+		{template: "https://{book}/game/{id}", bookmaker: "bet.example", gameID: "g1", want: "https://bet.example/game/g1"},
+		{template: "https://odds.example/{id}?book={book}&again={id}", bookmaker: "b", gameID: "g2", want: "https://odds.example/g2?book=b&again=g2"},
+		{template: "https://odds.example/{book}/{id}", bookmaker: "Big Book", gameID: "a/b", want: "https://odds.example/Big%20Book/a%2Fb"},
+		{template: "https://odds.example/static", bookmaker: "b", gameID: "g1", want: "https://odds.example/static"},
+		{template: "", bookmaker: "b", gameID: "g1", want: ""},
+	}
+	for _, tt := range tests {
+		if got := resolveLink(tt.template, tt.bookmaker, tt.gameID); got != tt.want {
+			t.Errorf("resolveLink(%q, %q, %q) = %q, want %q", tt.template, tt.bookmaker, tt.gameID, got, tt.want)
+		}
+	}
+}
+
+func TestOpportunityLinks(t *testing.T) {
+	// Bookmaker b has no template, so its legs fall back to the bookmaker name
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2.6, Draw: 3.3, Lose: 3.3}, Available: true}}},
+		{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2.2, Draw: 3.6, Lose: 4.2}, Available: true}}},
+	}
+	applyConfig(bookmakers, Config{Bookmakers: map[string]BookmakerConfig{"a": {LinkTemplate: "https://{book}.example/game/{id}"}}})
+	detected, _ := detectArbitrageOpportunities(context.Background(), bookmakers, scanOptions{TotalBet: 100, ArbThreshold: 1})
+	if len(detected) != 1 {
+		t.Fatalf("got %d opportunities, want 1", len(detected))
+	}
+	want := map[string]string{OutcomeWin: "https://a.example/game/g1"}
+	if !reflect.DeepEqual(detected[0].Links, want) {
+		t.Errorf("links = %v, want %v", detected[0].Links, want)
+	}
+
+	var buf strings.Builder
+	printArbitrageOpportunity(&buf, detected[0], defaultOutputOptions)
+	if !strings.Contains(buf.String(), "Links: Win: https://a.example/game/g1, Draw: b, Lose: b\n") {
+		t.Errorf("text output lacks the links:\n%s", buf.String())
+	}
+	data, err := json.Marshal(detected[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"links":{"win":"https://a.example/game/g1"}`) {
+		t.Errorf("JSON output lacks the links: %s", data)
+	}
+
+	if links := opportunityLinks(detected[0], nil); links != nil {
+		t.Errorf("links without templates = %v, want none", links)
+	}
+}
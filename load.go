@@ -0,0 +1,56 @@
+package main
+
+// Define the options used when loading bookmakers data
+type loadOptions struct {
+	// Accept the first valid JSON array in a file and warn about any trailing
+	// data after it instead of failing
+	Lenient bool
+	// Read only this sheet of an Excel workbook instead of every sheet
+	Sheet string
+	// Memory-map JSON files where the platform supports it
+	Mmap bool
+	// Basis the file quotes odds on; net odds are converted to decimal
+	OddsBasis OddsBasis
+	// Retry reading after transient filesystem errors
+	Retry retryPolicy
+	// Leave event times as written instead of normalizing them to RFC3339
+	RawEventTimes bool
+}
+
+// Read bookmakers data from a file, choosing the format from its extension,
+// and normalize its odds to decimal and, unless opts.RawEventTimes is set,
+// its event times to RFC3339
+func loadBookmakers(filename string, opts loadOptions) ([]Bookmaker, error) {
+	var bookmakers []Bookmaker
+	err := opts.Retry.do(func() error {
+		var err error
+		switch {
+		case isCSVFile(filename):
+			bookmakers, err = readBookmakersFromCSV(filename)
+		case isXLSXFile(filename):
+			bookmakers, err = readBookmakersFromXLSX(filename, opts.Sheet)
+		default:
+			bookmakers, err = readBookmakersFromFile(filename, opts)
+		}
+		return err
+	})
+	normalizeOddsBasis(bookmakers, opts.OddsBasis)
+	if !opts.RawEventTimes {
+		normalizeEventTimes(bookmakers)
+	}
+	return bookmakers, err
+}
+
+// Write bookmakers data to a file, choosing the format from its extension
+// and retrying after transient filesystem errors
+func saveBookmakers(bookmakers []Bookmaker, filename string, retry retryPolicy) error {
+	return retry.do(func() error {
+		if isCSVFile(filename) {
+			return writeBookmakersToCSV(bookmakers, filename)
+		}
+		if isXLSXFile(filename) {
+			return writeBookmakersToXLSX(bookmakers, filename)
+		}
+		return writeBookmakersToFile(bookmakers, filename)
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Collect the opportunities a scan reports, with the bankroll selection and
+// result cap applied and intra-bookmaker opportunities following when intra is set, in
+// the same order as the text output
+func collectOpportunities(ctx context.Context, bookmakers []Bookmaker, opts scanOptions, intra bool) []ArbitrageOpportunity {
+	opportunities, partial := collectOpportunitiesPartial(ctx, bookmakers, opts, intra)
+	if partial {
+		warn(partialResultsWarning)
+	}
+	return opportunities
+}
+
+// Collect the opportunities a scan reports like collectOpportunities,
+// reporting whether ctx cut detection short instead of warning about it
+func collectOpportunitiesPartial(ctx context.Context, bookmakers []Bookmaker, opts scanOptions, intra bool) ([]ArbitrageOpportunity, bool) {
+	detected, partial := detectArbitrageOpportunities(ctx, bookmakers, opts)
+	opportunities := applyScanOptions(detected, opts)
+	if intra {
+		for _, bookmaker := range bookmakers {
+			opportunities = append(opportunities, applyScanOptions(findIntraBookmakerArbitrage(bookmaker, opts.TotalBet, opts.ArbThreshold), opts)...)
+		}
+	}
+	if opts.Bankroll > 0 {
+		opportunities = selectOpportunities(opportunities, opts.Bankroll)
+	}
+	opportunities, omitted := limitOpportunities(opportunities, opts.MaxResults)
+	if omitted > 0 {
+		warn("%d opportunities omitted by -max-results", omitted)
+	}
+	sortOpportunities(opportunities, opts.Sort)
+	return opportunities, partial
+}
+
+// Escape text for use inside a Markdown table cell
+func markdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
+
+// Write opportunities as a Markdown table with one row per opportunity and a
+// summary line, formatting odds and amounts like the text output
+func writeOpportunitiesMarkdown(w io.Writer, opportunities []ArbitrageOpportunity, out outputOptions) error {
+	if _, err := fmt.Fprintln(w, "| Game | Sport | Odds | Bookmakers | Stakes | Guaranteed profit |"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "| --- | --- | --- | --- | --- | ---: |"); err != nil {
+		return err
+	}
+	totalProfit := 0.0
+	for _, opportunity := range opportunities {
+		labels := outcomesForSport(opportunity.Sport)
+		var odds, bookmakers, stakes []string
+		for _, leg := range opportunityLegs(opportunity) {
+			label := outcomeLabel(labels, leg.Outcome)
+			odds = append(odds, fmt.Sprintf("%s %.*f", label, out.OddsPrecision, leg.Odds))
+			bookmakers = append(bookmakers, fmt.Sprintf("%s %s", label, leg.Bookmaker))
+			stakes = append(stakes, fmt.Sprintf("%s %.*f", label, out.Precision, leg.Stake))
+		}
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %.*f |\n",
+			markdownCell(opportunity.GameID),
+			markdownCell(opportunity.Sport),
+			markdownCell(strings.Join(odds, " / ")),
+			markdownCell(strings.Join(bookmakers, " / ")),
+			markdownCell(strings.Join(stakes, " / ")),
+			out.Precision, opportunity.GuaranteedProfit); err != nil {
+			return err
+		}
+		totalProfit += opportunity.GuaranteedProfit
+	}
+	_, err := fmt.Fprintf(w, "\n%d opportunities with %.*f total guaranteed profit\n", len(opportunities), out.Precision, totalProfit)
+	return err
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWriteOpportunitiesMarkdown(t *testing.T) {
+	opportunities := collectOpportunities(context.Background(), streamedBookmakers(), scanOptions{TotalBet: 100, ArbThreshold: 1}, false)
+	if len(opportunities) != 2 {
+		t.Fatalf("got %d opportunities, want 2", len(opportunities))
+	}
+	var buf bytes.Buffer
+	if err := writeOpportunitiesMarkdown(&buf, opportunities, defaultOutputOptions); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if want := "| Game | Sport | Odds | Bookmakers | Stakes | Guaranteed profit |"; lines[0] != want {
+		t.Errorf("header = %q, want %q", lines[0], want)
+	}
+	var rows int
+	for _, line := range lines[2:] {
+		if strings.HasPrefix(line, "| ") {
+			rows++
+		}
+	}
+	if rows != 2 {
+		t.Errorf("got %d rows, want 2:\n%s", rows, buf.String())
+	}
+	if !strings.HasPrefix(lines[2], "| g1 |  | Win 3.30 / Draw 3.60 / Lose 4.20 | Win b / Draw b / Lose b | ") {
+		t.Errorf("first row = %q", lines[2])
+	}
+	total := opportunities[0].GuaranteedProfit + opportunities[1].GuaranteedProfit
+	if want := "2 opportunities with " + fmt.Sprintf("%.2f", total) + " total guaranteed profit"; lines[len(lines)-1] != want {
+		t.Errorf("summary = %q, want %q", lines[len(lines)-1], want)
+	}
+}
+
+func TestMarkdownCellEscapesPipes(t *testing.T) {
+	if got := markdownCell("A|B"); got != `A\|B` {
+		t.Errorf("markdownCell() = %q", got)
+	}
+	var buf bytes.Buffer
+	opportunity := newArbitrageOpportunity("cup|final", Odds{Win: 2.2, Lose: 2.2}, 100)
+	if err := writeOpportunitiesMarkdown(&buf, []ArbitrageOpportunity{opportunity}, defaultOutputOptions); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `| cup\|final |`) {
+		t.Errorf("game ID not escaped:\n%s", buf.String())
+	}
+}
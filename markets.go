@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Narrow bookmakers down to the ones quoting a fixture, each with only that
+// game. Bookmaker settings such as reliability and limits are kept so the
+// fixture can be scanned like the full data.
+func fixtureBookmakers(bookmakers []Bookmaker, gameID string) []Bookmaker {
+	fixture := make([]Bookmaker, 0, len(bookmakers))
+	for _, bookmaker := range bookmakers {
+		for _, game := range bookmaker.Games {
+			if game.ID == gameID {
+				bookmaker.Games = []Game{game}
+				fixture = append(fixture, bookmaker)
+				break
+			}
+		}
+	}
+	return fixture
+}
+
+// Find the most profitable arbitrage on one fixture across every market its
+// bookmakers quote, running each detector that applies: the match result
+// across bookmakers and within each bookmaker, and draw no bet combined with
+// the draw elsewhere. Detectors follow the same scan options as a full scan.
+// Returns ErrGameNotFound when no bookmaker quotes the fixture and
+// ErrNoArbitrage when no market combination is an arbitrage.
+func bestFixtureArbitrage(ctx context.Context, bookmakers []Bookmaker, gameID string, opts scanOptions) (ArbitrageOpportunity, error) {
+	fixture := fixtureBookmakers(bookmakers, gameID)
+	if len(fixture) == 0 {
+		return ArbitrageOpportunity{}, &GameError{GameID: gameID, Err: ErrGameNotFound}
+	}
+	detected, _ := detectArbitrageOpportunities(ctx, fixture, opts)
+	candidates := applyScanOptions(detected, opts)
+	for _, bookmaker := range fixture {
+		intra := findIntraBookmakerArbitrage(bookmaker, opts.TotalBet, opts.ArbThreshold)
+		candidates = append(candidates, applyScanOptions(intra, opts)...)
+	}
+	candidates = append(candidates, findDrawNoBetArbitrage(fixture, opts)...)
+	if len(candidates) == 0 {
+		return ArbitrageOpportunity{}, &GameError{GameID: gameID, Err: ErrNoArbitrage}
+	}
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.GuaranteedProfit > best.GuaranteedProfit {
+			best = candidate
+		}
+	}
+	return best, nil
+}
+
+// Print the best arbitrage found on a fixture along with the markets it
+// combines
+func printBestFixtureArbitrage(w io.Writer, opportunity ArbitrageOpportunity, out outputOptions) {
+	fmt.Fprintln(w, "Best market combination:", strings.Join(opportunityMarkets(opportunity), " + "))
+	printArbitrageOpportunity(w, opportunity, out)
+}
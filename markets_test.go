@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestBestFixtureArbitrage(t *testing.T) {
+	dnb := &Odds{Win: 2.5, Lose: 2.5}
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1}
+	tests := []struct {
+		name       string
+		bookmakers []Bookmaker
+		wantMarket string
+		wantProfit float64
+		wantErr    error
+	}{
+		{
+			// The match result across a and b books 95%, while a's draw no
+			// bet hedged with b's draw books 84%
+			name: "draw no bet combined with the draw",
+			bookmakers: []Bookmaker{
+				{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, DrawNoBet: dnb, Available: true}}},
+				{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 5, Lose: 4}, Available: true}}},
+			},
+			wantMarket: marketDrawNoBet,
+			wantProfit: 100/0.84 - 100,
+		},
+		{
+			name: "match result across bookmakers",
+			bookmakers: []Bookmaker{
+				{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, Available: true}}},
+				{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 5, Lose: 4}, Available: true}}},
+			},
+			wantProfit: 100/0.95 - 100,
+		},
+		{
+			name: "no arbitrage",
+			bookmakers: []Bookmaker{
+				{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, DrawNoBet: &Odds{Win: 1.8, Lose: 1.9}, Available: true}}},
+			},
+			wantErr: ErrNoArbitrage,
+		},
+		{
+			name:       "unknown fixture",
+			bookmakers: []Bookmaker{{Name: "a", Games: []Game{{ID: "other", Odds: Odds{Win: 3, Draw: 4, Lose: 4}, Available: true}}}},
+			wantErr:    ErrGameNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bestFixtureArbitrage(context.Background(), tt.bookmakers, "g1", opts)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Market != tt.wantMarket || math.Abs(got.GuaranteedProfit-tt.wantProfit) > 1e-9 {
+				t.Errorf("best is %q for %v profit, want %q for %v", got.Market, got.GuaranteedProfit, tt.wantMarket, tt.wantProfit)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Merge bookmakers from several sources, combining entries that share a name,
+// including duplicates within a single source. Bookmakers keep the order in
+// which they were first seen and games keep the order of the sources they
+// came from, so the result does not depend on map iteration.
+func mergeBookmakers(sources ...[]Bookmaker) []Bookmaker {
+	var merged []Bookmaker
+	index := make(map[string]int)
+	for _, source := range sources {
+		for _, bookmaker := range source {
+			i, exists := index[bookmaker.Name]
+			if !exists {
+				index[bookmaker.Name] = len(merged)
+				// Settings such as limits and currency come from the
+				// first occurrence; only the games are combined.
+				first := bookmaker
+				first.Games = nil
+				merged = append(merged, first)
+				i = len(merged) - 1
+			}
+			merged[i].Games = append(merged[i].Games, bookmaker.Games...)
+		}
+	}
+	return merged
+}
+
+// Read several bookmaker files concurrently using at most workers goroutines.
+// Results are returned in the same order as filenames; errors from every file
+// that failed are joined together so they can be reported at once.
+func readBookmakersFromFiles(ctx context.Context, filenames []string, workers int, opts loadOptions) ([][]Bookmaker, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([][]Bookmaker, len(filenames))
+	errs := make([]error, len(filenames))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					errs[i] = &FileError{Path: filenames[i], Err: err}
+					continue
+				}
+				bookmakers, err := loadBookmakers(filenames[i], opts)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				results[i] = bookmakers
+			}
+		}()
+	}
+
+	for i := range filenames {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
+
+// Read and merge several bookmaker files
+func loadMergedBookmakers(ctx context.Context, filenames []string, workers int, opts loadOptions) ([]Bookmaker, error) {
+	sources, err := readBookmakersFromFiles(ctx, filenames, workers, opts)
+	if err != nil {
+		return nil, err
+	}
+	return mergeBookmakers(sources...), nil
+}
+
+// List the JSON files in a directory, descending into subdirectories when
+// recursive is set, in lexical order. Other files are skipped.
+func bookmakerFilesInDir(dir string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, newFileError(dir, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Read and merge every JSON bookmakers file in a directory
+func loadBookmakersFromDir(dir string, recursive bool, opts loadOptions) ([]Bookmaker, error) {
+	files, err := bookmakerFilesInDir(dir, recursive)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, &FileError{Path: dir, Err: ErrNoData}
+	}
+	return loadMergedBookmakers(context.Background(), files, runtime.NumCPU(), opts)
+}
+
+// Replace the directories among paths with the JSON files they contain, so
+// files and directories can be merged together
+func expandBookmakerPaths(paths []string, recursive bool) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		dirFiles, err := bookmakerFilesInDir(path, recursive)
+		if err != nil {
+			return nil, err
+		}
+		if len(dirFiles) == 0 {
+			return nil, &FileError{Path: path, Err: ErrNoData}
+		}
+		files = append(files, dirFiles...)
+	}
+	return files, nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMergeBookmakersKeepsSettings(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.json")
+	second := filepath.Join(dir, "second.json")
+
+	sharp := Bookmaker{
+		Name:         "sharp",
+		Games:        []Game{{ID: "g1", TeamA: "A", TeamB: "B", Odds: Odds{Win: 2.1, Draw: 3.4, Lose: 3.9}, Available: true}},
+		Reliability:  0.9,
+		LinkTemplate: "https://sharp.example/{id}",
+		Currency:     "EUR",
+		MaxPayout:    5000,
+		MaxStakes:    map[string]float64{defaultSportLimit: 250},
+		MinStake:     2,
+	}
+	if err := writeBookmakersToFile([]Bookmaker{sharp}, first); err != nil {
+		t.Fatal(err)
+	}
+	// A later occurrence with different settings contributes only games
+	later := Bookmaker{
+		Name:     "sharp",
+		Games:    []Game{{ID: "g2", TeamA: "C", TeamB: "D", Odds: Odds{Win: 1.8, Draw: 3.6, Lose: 4.5}, Available: true}},
+		Currency: "USD",
+		MinStake: 10,
+	}
+	if err := writeBookmakersToFile([]Bookmaker{later}, second); err != nil {
+		t.Fatal(err)
+	}
+
+	var sources [][]Bookmaker
+	for _, filename := range []string{first, second} {
+		bookmakers, err := loadBookmakers(filename, loadOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sources = append(sources, bookmakers)
+	}
+	merged := mergeBookmakers(sources...)
+
+	if len(merged) != 1 {
+		t.Fatalf("got %d bookmakers, want 1", len(merged))
+	}
+	got := merged[0]
+	if len(got.Games) != 2 || got.Games[0].ID != "g1" || got.Games[1].ID != "g2" {
+		t.Errorf("games = %+v, want g1 then g2", got.Games)
+	}
+	if got.Reliability != 0.9 {
+		t.Errorf("Reliability = %v, want 0.9", got.Reliability)
+	}
+	if got.LinkTemplate != sharp.LinkTemplate {
+		t.Errorf("LinkTemplate = %q, want %q", got.LinkTemplate, sharp.LinkTemplate)
+	}
+	if got.Currency != "EUR" {
+		t.Errorf("Currency = %q, want EUR", got.Currency)
+	}
+	if got.MaxPayout != 5000 {
+		t.Errorf("MaxPayout = %v, want 5000", got.MaxPayout)
+	}
+	if got.MaxStakes[defaultSportLimit] != 250 {
+		t.Errorf("MaxStakes = %v, want default 250", got.MaxStakes)
+	}
+	if got.MinStake != 2 {
+		t.Errorf("MinStake = %v, want 2", got.MinStake)
+	}
+}
+
+func TestMergeBookmakersDoesNotAliasSources(t *testing.T) {
+	source := []Bookmaker{{Name: "a", Games: []Game{{ID: "g1"}}}}
+	merged := mergeBookmakers(source, []Bookmaker{{Name: "a", Games: []Game{{ID: "g2"}}}})
+	if len(source[0].Games) != 1 {
+		t.Errorf("source games changed to %+v", source[0].Games)
+	}
+	if len(merged[0].Games) != 2 {
+		t.Errorf("merged games = %+v, want 2", merged[0].Games)
+	}
+}
+
+func TestLoadBookmakersFromDir(t *testing.T) {
+	dir := t.TempDir()
+	game := func(id string) Game {
+		return Game{ID: id, TeamA: "A", TeamB: "B", Odds: Odds{Win: 2, Draw: 3.5, Lose: 4}, Available: true}
+	}
+	files := map[string][]Bookmaker{
+		"first.json":        {{Name: "a", Games: []Game{game("g1")}}},
+		"second.JSON":       {{Name: "a", Games: []Game{game("g2")}}, {Name: "b", Games: []Game{game("g1")}}},
+		"nested/third.json": {{Name: "c", Games: []Game{game("g1")}}},
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, bookmakers := range files {
+		if err := writeBookmakersToFile(bookmakers, filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A stray file that is not JSON must be skipped rather than parsed
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not bookmakers"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		recursive bool
+		want      map[string][]string
+	}{
+		{recursive: false, want: map[string][]string{"a": {"g1", "g2"}, "b": {"g1"}}},
+		{recursive: true, want: map[string][]string{"a": {"g1", "g2"}, "b": {"g1"}, "c": {"g1"}}},
+	}
+	for _, tt := range tests {
+		bookmakers, err := loadBookmakersFromDir(dir, tt.recursive, loadOptions{})
+		if err != nil {
+			t.Fatalf("recursive %v: %v", tt.recursive, err)
+		}
+		got := make(map[string][]string)
+		for _, bookmaker := range bookmakers {
+			got[bookmaker.Name] = fixtureIDs(bookmaker)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("recursive %v loaded %v, want %v", tt.recursive, got, tt.want)
+		}
+	}
+
+	if _, err := loadBookmakersFromDir(t.TempDir(), false, loadOptions{}); !errors.Is(err, ErrNoData) {
+		t.Errorf("empty directory error = %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestReadBookmakersFromFilesConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	var filenames []string
+	for i := 0; i < 8; i++ {
+		filename := filepath.Join(dir, fmt.Sprintf("feed%d.json", i))
+		bookmakers := []Bookmaker{{Name: fmt.Sprintf("b%d", i), Games: []Game{
+			{ID: "g1", TeamA: "A", TeamB: "B", Odds: Odds{Win: 2, Draw: 3.5, Lose: 4}, Available: true},
+		}}}
+		if err := writeBookmakersToFile(bookmakers, filename); err != nil {
+			t.Fatal(err)
+		}
+		filenames = append(filenames, filename)
+	}
+	// One listed file is missing and another is not valid JSON
+	filenames[2] = filepath.Join(dir, "missing.json")
+	if err := os.WriteFile(filenames[5], []byte(`[{"name":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{1, 3, 16} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			results, err := readBookmakersFromFiles(context.Background(), filenames, workers, loadOptions{})
+			if !errors.Is(err, ErrFileNotFound) || !errors.Is(err, ErrParse) {
+				t.Errorf("error = %v, want both the missing and the malformed file", err)
+			}
+			if len(results) != len(filenames) {
+				t.Fatalf("got %d results, want %d", len(results), len(filenames))
+			}
+			for i, bookmakers := range results {
+				if i == 2 || i == 5 {
+					if bookmakers != nil {
+						t.Errorf("failed file %d loaded %+v", i, bookmakers)
+					}
+					continue
+				}
+				if len(bookmakers) != 1 || bookmakers[0].Name != fmt.Sprintf("b%d", i) {
+					t.Errorf("result %d = %+v, want bookmaker b%d", i, bookmakers, i)
+				}
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := readBookmakersFromFiles(ctx, filenames, 4, loadOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled error = %v, want %v", err, context.Canceled)
+	}
+	for i, bookmakers := range results {
+		if bookmakers != nil {
+			t.Errorf("cancelled pool loaded file %d", i)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"time"
+)
+
+// Define the structure for the summary metrics of a scan
+type ScanMetrics struct {
+	Opportunities int
+	// Lowest arbitrage percentage and highest return on capital among the
+	// opportunities, zero when there are none
+	BestArbitragePercentage float64
+	BestReturnOnCapital     float64
+	GuaranteedProfit        float64
+	Partial                 bool
+	FinishedAt              time.Time
+}
+
+// Summarize the opportunities reported by a scan
+func scanMetrics(opportunities []ArbitrageOpportunity, partial bool, finishedAt time.Time) ScanMetrics {
+	metrics := ScanMetrics{Partial: partial, FinishedAt: finishedAt}
+	for _, opportunity := range opportunities {
+		metrics.add(opportunity)
+	}
+	return metrics
+}
+
+// Record an opportunity as it is reported, so streamed reports can be
+// summarized without holding every opportunity. Does nothing on nil metrics.
+func (m *ScanMetrics) add(opportunity ArbitrageOpportunity) {
+	if m == nil {
+		return
+	}
+	if m.Opportunities == 0 || opportunity.ArbitragePercentage < m.BestArbitragePercentage {
+		m.BestArbitragePercentage = opportunity.ArbitragePercentage
+	}
+	if m.Opportunities == 0 || opportunity.ReturnOnCapital > m.BestReturnOnCapital {
+		m.BestReturnOnCapital = opportunity.ReturnOnCapital
+	}
+	m.GuaranteedProfit += opportunity.GuaranteedProfit
+	m.Opportunities++
+}
+
+// Write scan metrics in the OpenMetrics text format, as gauges describing
+// the last run, ending with the required EOF marker
+func writeOpenMetrics(w io.Writer, metrics ScanMetrics) error {
+	partial := 0
+	if metrics.Partial {
+		partial = 1
+	}
+	gauges := []struct {
+		name  string
+		unit  string
+		help  string
+		value float64
+	}{
+		{"sba_opportunities", "", "Arbitrage opportunities reported by the last scan.", float64(metrics.Opportunities)},
+		{"sba_best_arbitrage_percentage", "", "Lowest arbitrage percentage among the opportunities, 0 without any.", metrics.BestArbitragePercentage},
+		{"sba_best_return_on_capital", "", "Highest return on capital among the opportunities, as a fraction.", metrics.BestReturnOnCapital},
+		{"sba_guaranteed_profit", "", "Guaranteed profit summed over the opportunities.", metrics.GuaranteedProfit},
+		{"sba_partial", "", "1 when detection stopped at the deadline and results are partial.", float64(partial)},
+		{"sba_last_run_timestamp_seconds", "seconds", "Unix time the last scan finished.", float64(metrics.FinishedAt.UnixNano()) / 1e9},
+	}
+	for _, gauge := range gauges {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", gauge.name, gauge.help, gauge.name); err != nil {
+			return err
+		}
+		if gauge.unit != "" {
+			if _, err := fmt.Fprintf(w, "# UNIT %s %s\n", gauge.name, gauge.unit); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\n", gauge.name, strconv.FormatFloat(gauge.value, 'f', -1, 64)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "# EOF")
+	return err
+}
+
+// Write scan metrics to an OpenMetrics file, replacing it atomically so a
+// textfile collector never reads a partly written file
+func writeOpenMetricsFile(filename string, metrics ScanMetrics) error {
+	var buf bytes.Buffer
+	if err := writeOpenMetrics(&buf, metrics); err != nil {
+		return err
+	}
+	return replaceFileAtomically(filename, func(tempName string) error {
+		if err := ioutil.WriteFile(tempName, buf.Bytes(), 0644); err != nil {
+			return newFileError(tempName, err)
+		}
+		return nil
+	})
+}
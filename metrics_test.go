@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanMetrics(t *testing.T) {
+	finished := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		opportunities []ArbitrageOpportunity
+		partial       bool
+		want          ScanMetrics
+	}{
+		{name: "none", want: ScanMetrics{FinishedAt: finished}},
+		{
+			name: "several",
+			opportunities: []ArbitrageOpportunity{
+				{ArbitragePercentage: 0.98, ReturnOnCapital: 0.02, GuaranteedProfit: 2},
+				{ArbitragePercentage: 0.95, ReturnOnCapital: 0.05, GuaranteedProfit: 5},
+				{ArbitragePercentage: 0.99, ReturnOnCapital: 0.01, GuaranteedProfit: 1},
+			},
+			partial: true,
+			want: ScanMetrics{Opportunities: 3, BestArbitragePercentage: 0.95, BestReturnOnCapital: 0.05,
+				GuaranteedProfit: 8, Partial: true, FinishedAt: finished},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scanMetrics(tt.opportunities, tt.partial, finished); got != tt.want {
+				t.Errorf("scanMetrics() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanMetricsCountReportedOpportunities(t *testing.T) {
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{
+			{ID: "g1", Odds: Odds{Win: 3, Draw: 4, Lose: 4}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 2.8, Draw: 3.8, Lose: 3.9}, Available: true},
+		}},
+	}
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1, MaxResults: 1}
+	metrics := &ScanMetrics{}
+	found := findArbitrageOpportunities(context.Background(), io.Discard, bookmakers, opts, defaultOutputOptions, metrics)
+	if found != 1 || metrics.Opportunities != found {
+		t.Errorf("found %d, metrics count %d, want both 1", found, metrics.Opportunities)
+	}
+
+	var buf bytes.Buffer
+	jsonMetrics := &ScanMetrics{}
+	if _, err := writeOpportunitiesJSON(context.Background(), &buf, bookmakers, opts, false, jsonMetrics); err != nil {
+		t.Fatal(err)
+	}
+	if *jsonMetrics != *metrics {
+		t.Errorf("JSON report metrics %+v, want %+v as for text", *jsonMetrics, *metrics)
+	}
+}
+
+var (
+	openMetricsName   = `[a-zA-Z_:][a-zA-Z0-9_:]*`
+	openMetricsHelp   = regexp.MustCompile(`^# HELP (` + openMetricsName + `) \S.*$`)
+	openMetricsType   = regexp.MustCompile(`^# TYPE (` + openMetricsName + `) (counter|gauge|histogram|gaugehistogram|stateset|info|summary|unknown)$`)
+	openMetricsUnit   = regexp.MustCompile(`^# UNIT (` + openMetricsName + `) ([a-zA-Z0-9_:]+)$`)
+	openMetricsSample = regexp.MustCompile(`^(` + openMetricsName + `) (\S+)$`)
+)
+
+// Parse an OpenMetrics text exposition of label-free metrics, checking the
+// rules the format sets on metadata, samples and the EOF marker, and return
+// the sample values by name
+func parseOpenMetrics(t *testing.T, text string) map[string]float64 {
+	t.Helper()
+	if !strings.HasSuffix(text, "# EOF\n") {
+		t.Fatalf("exposition does not end with # EOF and a newline:\n%s", text)
+	}
+	samples := make(map[string]float64)
+	types := make(map[string]string)
+	units := make(map[string]string)
+	seen := make(map[string]bool)
+	current := ""
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	eof := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if eof {
+			t.Fatalf("line after # EOF: %q", line)
+		}
+		if line == "# EOF" {
+			eof = true
+			continue
+		}
+		if m := openMetricsHelp.FindStringSubmatch(line); m != nil {
+			if seen[m[1]] {
+				t.Fatalf("metric family %s appears twice", m[1])
+			}
+			seen[m[1]], current = true, m[1]
+			continue
+		}
+		if m := openMetricsType.FindStringSubmatch(line); m != nil {
+			if m[1] != current {
+				t.Fatalf("TYPE for %s outside its family %s", m[1], current)
+			}
+			types[m[1]] = m[2]
+			continue
+		}
+		if m := openMetricsUnit.FindStringSubmatch(line); m != nil {
+			if m[1] != current || !strings.HasSuffix(m[1], "_"+m[2]) {
+				t.Fatalf("UNIT %s does not belong to or suffix family %s", m[2], m[1])
+			}
+			units[m[1]] = m[2]
+			continue
+		}
+		m := openMetricsSample.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("line is not valid OpenMetrics: %q", line)
+		}
+		if m[1] != current || types[m[1]] == "" {
+			t.Fatalf("sample %s outside its family %s or without a TYPE", m[1], current)
+		}
+		value, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			t.Fatalf("sample %s has value %q: %v", m[1], m[2], err)
+		}
+		samples[m[1]] = value
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return samples
+}
+
+func TestWriteOpenMetricsParses(t *testing.T) {
+	metrics := ScanMetrics{Opportunities: 3, BestArbitragePercentage: 0.95, BestReturnOnCapital: 0.05,
+		GuaranteedProfit: 8.5, Partial: true, FinishedAt: time.Unix(1714564800, 500000000)}
+	var buf bytes.Buffer
+	if err := writeOpenMetrics(&buf, metrics); err != nil {
+		t.Fatal(err)
+	}
+	samples := parseOpenMetrics(t, buf.String())
+	want := map[string]float64{
+		"sba_opportunities":              3,
+		"sba_best_arbitrage_percentage":  0.95,
+		"sba_best_return_on_capital":     0.05,
+		"sba_guaranteed_profit":          8.5,
+		"sba_partial":                    1,
+		"sba_last_run_timestamp_seconds": 1714564800.5,
+	}
+	if len(samples) != len(want) {
+		t.Errorf("got samples %v, want %v", samples, want)
+	}
+	for name, value := range want {
+		if samples[name] != value {
+			t.Errorf("%s = %v, want %v", name, samples[name], value)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+)
+
+// Reported by mmapFile on platforms without memory-mapped files
+var errMmapUnsupported = errors.New("memory-mapped files are not supported on this platform")
+
+// Read a file's contents, memory-mapping it when useMmap is set so huge files
+// are decoded in place instead of being copied into memory first. Platforms
+// without mmap fall back to reading the file. Release must be called once
+// the data is no longer used; decoded values do not refer to it.
+func readFileData(filename string, useMmap bool) (data []byte, release func(), err error) {
+	if useMmap {
+		data, release, err = mmapFile(filename)
+		if !errors.Is(err, errMmapUnsupported) {
+			return data, release, err
+		}
+	}
+	data, err = ioutil.ReadFile(filename)
+	return data, func() {}, err
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+// Memory-mapped files are not supported on this platform
+func mmapFile(filename string) ([]byte, func(), error) {
+	return nil, nil, errMmapUnsupported
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Write a generated dump of the given size to a temporary file
+func writeLargeDump(tb testing.TB, numBookmakers, numGames int) string {
+	tb.Helper()
+	filename := filepath.Join(tb.TempDir(), "dump.json")
+	bookmakers := generateBookmakersWithSeed(numBookmakers, numGames, 1, NamesRandom)
+	if err := writeBookmakersToFile(bookmakers, filename); err != nil {
+		tb.Fatal(err)
+	}
+	return filename
+}
+
+func TestReadFileDataMatchesFile(t *testing.T) {
+	filename := writeLargeDump(t, 2, 50)
+	want, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, useMmap := range []bool{false, true} {
+		data, release, err := readFileData(filename, useMmap)
+		if err != nil {
+			t.Fatalf("readFileData(mmap=%v): %v", useMmap, err)
+		}
+		if !bytes.Equal(data, want) {
+			t.Errorf("readFileData(mmap=%v) returned %d bytes, want the file's %d", useMmap, len(data), len(want))
+		}
+		release()
+	}
+}
+
+func TestReadFileDataEmptyFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "empty.json")
+	if err := os.WriteFile(filename, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, release, err := readFileData(filename, true)
+	if err != nil {
+		t.Fatalf("readFileData: %v", err)
+	}
+	defer release()
+	if len(data) != 0 {
+		t.Errorf("readFileData returned %d bytes, want none", len(data))
+	}
+}
+
+func TestReadBookmakersFromFileMmap(t *testing.T) {
+	filename := writeLargeDump(t, 3, 20)
+	read, err := readBookmakersFromFile(filename, loadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapped, err := readBookmakersFromFile(filename, loadOptions{Mmap: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mapped) != len(read) || len(mapped[0].Games) != len(read[0].Games) {
+		t.Fatalf("mapped read decoded %d bookmakers, want %d", len(mapped), len(read))
+	}
+	if !reflect.DeepEqual(mapped[2].Games[19], read[2].Games[19]) {
+		t.Errorf("mapped game = %+v, want %+v", mapped[2].Games[19], read[2].Games[19])
+	}
+}
+
+// Compare decoding a large dump from a mapped region against streaming it
+// through a decoder reading from the file
+func BenchmarkDecodeLargeFile(b *testing.B) {
+	filename := writeLargeDump(b, 20, 5000)
+	info, err := os.Stat(filename)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("mmap", func(b *testing.B) {
+		b.SetBytes(info.Size())
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := readBookmakersFromFile(filename, loadOptions{Mmap: true}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("streaming", func(b *testing.B) {
+		b.SetBytes(info.Size())
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			file, err := os.Open(filename)
+			if err != nil {
+				b.Fatal(err)
+			}
+			var bookmakers []Bookmaker
+			err = json.NewDecoder(file).Decode(&bookmakers)
+			file.Close()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
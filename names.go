@@ -0,0 +1,76 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// Define how generated fixtures are named
+type NameMode int
+
+const (
+	// NamesRandom names teams with random words and leaves the sport empty
+	NamesRandom NameMode = iota
+	// NamesRealistic draws both teams from the same league of a bundled list
+	NamesRealistic
+)
+
+// Return the name of a naming mode
+func (m NameMode) String() string {
+	switch m {
+	case NamesRandom:
+		return "random"
+	case NamesRealistic:
+		return "realistic"
+	}
+	return fmt.Sprintf("NameMode(%d)", int(m))
+}
+
+// Parse a naming mode name
+func parseNameMode(name string) (NameMode, error) {
+	for _, mode := range []NameMode{NamesRandom, NamesRealistic} {
+		if mode.String() == name {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown names %q, expected random or realistic", name)
+}
+
+// Define the structure for a league and its teams in the bundled list
+type leagueNames struct {
+	Sport  string   `json:"sport"`
+	League string   `json:"league"`
+	Teams  []string `json:"teams"`
+}
+
+//go:embed names/leagues.json
+var leaguesData []byte
+
+// Leagues parsed from the bundled list on first use
+var bundledLeagues = sync.OnceValue(func() []leagueNames {
+	var leagues []leagueNames
+	if err := json.Unmarshal(leaguesData, &leagues); err != nil {
+		panic(fmt.Sprintf("parsing bundled leagues: %v", err))
+	}
+	return leagues
+})
+
+// Name a game after two different teams of a random bundled league, setting
+// its sport and league and dropping the draw odds of sports without a draw
+func nameRealistically(rng *rand.Rand, game *Game) {
+	leagues := bundledLeagues()
+	league := leagues[rng.Intn(len(leagues))]
+	home := rng.Intn(len(league.Teams))
+	away := rng.Intn(len(league.Teams) - 1)
+	if away >= home {
+		away++
+	}
+	game.Sport = league.Sport
+	game.League = league.League
+	game.TeamA = league.Teams[home]
+	game.TeamB = league.Teams[away]
+	game.Odds = oddsForSport(game.Odds, game.Sport)
+}
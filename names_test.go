@@ -0,0 +1,83 @@
+package main
+
+import "testing"
+
+func TestBundledLeagues(t *testing.T) {
+	leagues := bundledLeagues()
+	if len(leagues) == 0 {
+		t.Fatal("no bundled leagues")
+	}
+	for _, league := range leagues {
+		if league.Sport == "" || league.League == "" || len(league.Teams) < 2 {
+			t.Errorf("league %+v needs a sport, a name and at least two teams", league)
+		}
+	}
+}
+
+func TestGenerateRealisticNames(t *testing.T) {
+	teams := make(map[string]map[string]bool)
+	sports := make(map[string]string)
+	for _, league := range bundledLeagues() {
+		teams[league.League] = make(map[string]bool)
+		for _, team := range league.Teams {
+			teams[league.League][team] = true
+		}
+		sports[league.League] = league.Sport
+	}
+
+	for _, bookmaker := range generateBookmakersWithSeed(2, 200, 5, NamesRealistic) {
+		for _, game := range bookmaker.Games {
+			leagueTeams, exists := teams[game.League]
+			if !exists {
+				t.Fatalf("game %s has league %q, not in the bundled list", game.ID, game.League)
+			}
+			if !leagueTeams[game.TeamA] || !leagueTeams[game.TeamB] {
+				t.Errorf("game %s teams %q and %q are not both in %s", game.ID, game.TeamA, game.TeamB, game.League)
+			}
+			if game.Sport != sports[game.League] {
+				t.Errorf("game %s sport = %q, want %q", game.ID, game.Sport, sports[game.League])
+			}
+			if !outcomesForSport(game.Sport).HasDraw && game.Odds.Draw != 0 {
+				t.Errorf("game %s in %s has draw odds %v", game.ID, game.Sport, game.Odds.Draw)
+			}
+		}
+	}
+}
+
+func TestGenerateRealisticNamesRepicksSelfMatches(t *testing.T) {
+	// Two spellings of the same club make self-matches likely
+	leagues := []leagueNames{{Sport: "soccer", League: "Test League", Teams: []string{"Arsenal", "arsenal", "ARSENAL", "Chelsea"}}}
+	saved := bundledLeagues
+	bundledLeagues = func() []leagueNames { return leagues }
+	t.Cleanup(func() { bundledLeagues = saved })
+
+	for _, game := range generateBookmakersWithSeed(1, 100, 5, NamesRealistic)[0].Games {
+		bundled := false
+		for _, team := range leagues[0].Teams {
+			bundled = bundled || team == game.TeamB
+		}
+		if !bundled || isSelfMatch(game) {
+			t.Errorf("game %s pairs %q with %q, want two different bundled teams", game.ID, game.TeamA, game.TeamB)
+		}
+		if game.Odds.Draw == 0 {
+			t.Errorf("soccer game %s lost its draw odds", game.ID)
+		}
+	}
+}
+
+func TestGenerateRandomNamesByDefault(t *testing.T) {
+	for _, game := range generateBookmakersWithSeed(1, 20, 5, NamesRandom)[0].Games {
+		if game.Sport != "" || game.League != "" || game.TeamA == "" || game.TeamB == "" {
+			t.Errorf("random game %+v should have team names and no sport or league", game)
+		}
+	}
+	if mode, err := parseNameMode("random"); err != nil || mode != NamesRandom {
+		t.Errorf("parseNameMode(\"random\") = %v, %v", mode, err)
+	}
+	if mode, err := parseNameMode("realistic"); err != nil || mode != NamesRealistic {
+		t.Errorf("parseNameMode(\"realistic\") = %v, %v", mode, err)
+	}
+	if _, err := parseNameMode("famous"); err == nil {
+		t.Error("parseNameMode(\"famous\") succeeded, want an error")
+	}
+}
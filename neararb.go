@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Define the structure for a fixture whose best odds fall just short of an
+// arbitrage
+type NearArbitrage struct {
+	GameID string `json:"game_id"`
+	Sport  string `json:"sport,omitempty"`
+	Odds   Odds   `json:"odds"`
+	// Bookmaker offering each leg's best odds keyed by outcome
+	Bookmakers          map[string]string `json:"bookmakers"`
+	ArbitragePercentage float64           `json:"arbitrage_percentage"`
+	// How far the fixture is from profitability, arbitrage percentage - 1
+	Gap float64 `json:"gap"`
+	// Odds each leg would need, with the others unchanged, for the fixture
+	// to break even, keyed by outcome
+	BreakEvenOdds map[string]float64 `json:"break_even_odds"`
+}
+
+// Find near misses: fixtures whose best odds across bookmakers have an
+// arbitrage percentage of at least 1 but below ceiling, such as 1.03. These
+// become arbitrages if one leg's odds improve slightly, so they are worth
+// watching as prices move. Results are ordered closest to profitable first.
+func findNearArbitrage(bookmakers []Bookmaker, ceiling float64) []NearArbitrage {
+	sports := fixtureSports(bookmakers)
+	var near []NearArbitrage
+	for gameID, best := range findBestOddsWithSource(bookmakers) {
+		sport := sports[gameID]
+		odds := oddsForSport(best.Odds, sport)
+		if checkOdds(odds, sport) != nil {
+			continue
+		}
+		percentage := calculateArbitragePercentage(odds)
+		if percentage < 1 || percentage >= ceiling {
+			continue
+		}
+		opportunity := ArbitrageOpportunity{GameID: gameID, Sport: sport, Odds: odds,
+			WinBookmaker: best.WinSource, DrawBookmaker: best.DrawSource, LoseBookmaker: best.LoseSource}
+		sources := make(map[string]string)
+		for _, leg := range opportunityLegs(opportunity) {
+			sources[leg.Outcome] = leg.Bookmaker
+		}
+		near = append(near, NearArbitrage{
+			GameID:              gameID,
+			Sport:               sport,
+			Odds:                odds,
+			Bookmakers:          sources,
+			ArbitragePercentage: percentage,
+			Gap:                 percentage - 1,
+			BreakEvenOdds:       sensitivity(opportunity),
+		})
+	}
+	sort.Slice(near, func(i, j int) bool {
+		if near[i].ArbitragePercentage != near[j].ArbitragePercentage {
+			return near[i].ArbitragePercentage < near[j].ArbitragePercentage
+		}
+		return near[i].GameID < near[j].GameID
+	})
+	return near
+}
+
+// Print near misses with the odds each leg would need to break even
+func printNearArbitrage(w io.Writer, near []NearArbitrage, ceiling float64, out outputOptions) {
+	fmt.Fprintf(w, "Near arbitrages below an arbitrage percentage of %v:\n", ceiling)
+	for _, fixture := range near {
+		labels := outcomesForSport(fixture.Sport)
+		opportunity := ArbitrageOpportunity{Odds: fixture.Odds}
+		var legs []string
+		for _, leg := range opportunityLegs(opportunity) {
+			legs = append(legs, fmt.Sprintf("%s %.*f at %s, needs %.*f", outcomeLabel(labels, leg.Outcome),
+				out.OddsPrecision, leg.Odds, fixture.Bookmakers[leg.Outcome], out.OddsPrecision, fixture.BreakEvenOdds[leg.Outcome]))
+		}
+		fmt.Fprintf(w, "%s: %.2f%% from profitable; %s\n", fixture.GameID, fixture.Gap*100, strings.Join(legs, "; "))
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// A tennis match at 2/1.96 = 1.0204, a fixture on exactly 100%, a genuine
+// arbitrage and a fixture at 1.05, split across two bookmakers
+func nearArbitrageBookmakers() []Bookmaker {
+	return []Bookmaker{
+		{Name: "a", Games: []Game{
+			{ID: "tennis", Sport: "tennis", Odds: Odds{Win: 1.96, Lose: 1.5}, Available: true},
+			{ID: "even", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, Available: true},
+			{ID: "arb", Odds: Odds{Win: 3, Draw: 4, Lose: 4}, Available: true},
+			{ID: "wide", Odds: Odds{Win: 2, Draw: 1 / 0.3, Lose: 4}, Available: true},
+		}},
+		{Name: "b", Games: []Game{
+			{ID: "tennis", Sport: "tennis", Odds: Odds{Win: 1.5, Lose: 1.96}, Available: true},
+			{ID: "even", Odds: Odds{Win: 1.5, Draw: 4, Lose: 3}, Available: true},
+		}},
+	}
+}
+
+func TestFindNearArbitrage(t *testing.T) {
+	tests := []struct {
+		ceiling float64
+		want    []string
+	}{
+		{ceiling: 1.01, want: []string{"even"}},
+		{ceiling: 1.03, want: []string{"even", "tennis"}},
+		{ceiling: 1.06, want: []string{"even", "tennis", "wide"}},
+	}
+	for _, tt := range tests {
+		near := findNearArbitrage(nearArbitrageBookmakers(), tt.ceiling)
+		var got []string
+		for _, fixture := range near {
+			got = append(got, fixture.GameID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("at ceiling %v found %v, want %v", tt.ceiling, got, tt.want)
+		}
+	}
+
+	near := findNearArbitrage(nearArbitrageBookmakers(), 1.03)
+	tennis := near[1]
+	if want := 2/1.96 - 1; math.Abs(tennis.Gap-want) > 1e-9 || math.Abs(tennis.ArbitragePercentage-1-tennis.Gap) > 1e-12 {
+		t.Errorf("tennis gap = %v, want %v", tennis.Gap, want)
+	}
+	if want := map[string]string{OutcomeWin: "a", OutcomeLose: "b"}; !reflect.DeepEqual(tennis.Bookmakers, want) {
+		t.Errorf("tennis bookmakers = %v, want %v", tennis.Bookmakers, want)
+	}
+	// Either leg breaks even once it pays 1 / (1 - 1/1.96)
+	breakEven := 1 / (1 - 1/1.96)
+	if len(tennis.BreakEvenOdds) != 2 || math.Abs(tennis.BreakEvenOdds[OutcomeWin]-breakEven) > 1e-9 || math.Abs(tennis.BreakEvenOdds[OutcomeLose]-breakEven) > 1e-9 {
+		t.Errorf("tennis break-even odds = %v, want %v on both legs", tennis.BreakEvenOdds, breakEven)
+	}
+}
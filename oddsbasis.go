@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Define how a feed quotes its odds. Decimal odds include the stake: odds of
+// 2.10 return 2.10 per unit staked, the stake plus 1.10 of winnings. Net odds
+// exclude the stake and quote only the winnings, so the same price is 1.10.
+// All arbitrage math assumes decimal odds, and treating net odds as decimal
+// makes every leg look shorter than it is and hides real opportunities, so a
+// net feed must be normalized before it is scanned.
+type OddsBasis int
+
+const (
+	// OddsDecimal quotes odds inclusive of stake, the basis used internally
+	OddsDecimal OddsBasis = iota
+	// OddsNet quotes odds exclusive of stake; decimal odds are net + 1
+	OddsNet
+	// OddsProbability gives the probability of each outcome instead of odds;
+	// decimal odds are 1/p
+	OddsProbability
+)
+
+// Return the name of an odds basis
+func (b OddsBasis) String() string {
+	switch b {
+	case OddsDecimal:
+		return "decimal"
+	case OddsNet:
+		return "net"
+	case OddsProbability:
+		return "probability"
+	}
+	return fmt.Sprintf("OddsBasis(%d)", int(b))
+}
+
+// Parse an odds basis name
+func parseOddsBasis(name string) (OddsBasis, error) {
+	for _, basis := range []OddsBasis{OddsDecimal, OddsNet, OddsProbability} {
+		if basis.String() == name {
+			return basis, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown odds basis %q, expected decimal, net or probability", name)
+}
+
+// Convert odds quoted on a basis to decimal odds. Zero legs, such as the
+// draw of a two-way market, stay zero.
+func toDecimalOdds(odds Odds, basis OddsBasis) Odds {
+	if basis == OddsProbability {
+		return oddsFromProbabilities(odds.Win, odds.Draw, odds.Lose)
+	}
+	if basis != OddsNet {
+		return odds
+	}
+	convert := func(value float64) float64 {
+		if value == 0 {
+			return 0
+		}
+		return value + 1
+	}
+	return Odds{Win: convert(odds.Win), Draw: convert(odds.Draw), Lose: convert(odds.Lose)}
+}
+
+// Convert outcome probabilities to decimal odds of 1/p. A zero draw means a
+// two-way market and stays zero; any other probability that is not above 0
+// and at most 1 gives zero odds, which fail odds validation.
+func oddsFromProbabilities(win, draw, lose float64) Odds {
+	convert := func(p float64) float64 {
+		if !(p > 0) || p > 1 {
+			return 0
+		}
+		return 1 / p
+	}
+	return Odds{Win: convert(win), Draw: convert(draw), Lose: convert(lose)}
+}
+
+// Tolerance for probabilities of a game summing to 1 before they are
+// reported; sources that embed their margin sum to a little more
+const probabilitySumTolerance = 0.01
+
+// Report whether a game's probabilities are positive and sum to 1 within
+// probabilitySumTolerance
+func probabilitiesSumToOne(odds Odds) bool {
+	if !(odds.Win > 0) || !(odds.Lose > 0) || odds.Draw < 0 {
+		return false
+	}
+	return math.Abs(odds.Win+odds.Draw+odds.Lose-1) <= probabilitySumTolerance
+}
+
+// Normalize every game's odds, including draw no bet markets, from a basis
+// to decimal odds. Probabilities that are not positive or do not sum to 1
+// are counted and reported in a single warning.
+func normalizeOddsBasis(bookmakers []Bookmaker, basis OddsBasis) {
+	if basis == OddsDecimal {
+		return
+	}
+	unbalanced := 0
+	for i := range bookmakers {
+		for j := range bookmakers[i].Games {
+			game := &bookmakers[i].Games[j]
+			if basis == OddsProbability && !probabilitiesSumToOne(game.Odds) {
+				unbalanced++
+			}
+			game.Odds = toDecimalOdds(game.Odds, basis)
+			if game.DrawNoBet != nil {
+				dnb := toDecimalOdds(*game.DrawNoBet, basis)
+				game.DrawNoBet = &dnb
+			}
+		}
+	}
+	if unbalanced > 0 {
+		warn("%d games have probabilities that are not positive or do not sum to 1", unbalanced)
+	}
+}
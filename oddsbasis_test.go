@@ -0,0 +1,111 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// Report whether two sets of odds agree to within rounding error
+func oddsClose(a, b Odds) bool {
+	return math.Abs(a.Win-b.Win) < 1e-9 && math.Abs(a.Draw-b.Draw) < 1e-9 && math.Abs(a.Lose-b.Lose) < 1e-9
+}
+
+func TestToDecimalOdds(t *testing.T) {
+	tests := []struct {
+		name  string
+		odds  Odds
+		basis OddsBasis
+		want  Odds
+	}{
+		{name: "net adds the stake", odds: Odds{Win: 1.1, Draw: 2.4, Lose: 3}, basis: OddsNet, want: Odds{Win: 2.1, Draw: 3.4, Lose: 4}},
+		{name: "net two-way keeps zero draw", odds: Odds{Win: 0.9, Lose: 1.05}, basis: OddsNet, want: Odds{Win: 1.9, Lose: 2.05}},
+		{name: "decimal unchanged", odds: Odds{Win: 2.1, Draw: 3.4, Lose: 4}, basis: OddsDecimal, want: Odds{Win: 2.1, Draw: 3.4, Lose: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toDecimalOdds(tt.odds, tt.basis); !oddsClose(got, tt.want) {
+				t.Errorf("toDecimalOdds(%+v, %v) = %+v, want %+v", tt.odds, tt.basis, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOddsBasis(t *testing.T) {
+	for _, basis := range []OddsBasis{OddsDecimal, OddsNet, OddsProbability} {
+		got, err := parseOddsBasis(basis.String())
+		if err != nil || got != basis {
+			t.Errorf("parseOddsBasis(%q) = %v, %v", basis.String(), got, err)
+		}
+	}
+	if _, err := parseOddsBasis("american"); err == nil {
+		t.Error("parseOddsBasis(\"american\") succeeded, want an error")
+	}
+}
+
+func TestLoadNetOdds(t *testing.T) {
+	file := writeTestData(t, []Bookmaker{{Name: "a", Games: []Game{
+		{ID: "g1", Odds: Odds{Win: 1.1, Draw: 2, Lose: 3}, DrawNoBet: &Odds{Win: 0.8, Lose: 1.2}, Available: true},
+	}}})
+	bookmakers, err := loadBookmakers(file, loadOptions{OddsBasis: OddsNet})
+	if err != nil {
+		t.Fatal(err)
+	}
+	game := bookmakers[0].Games[0]
+	if want := (Odds{Win: 2.1, Draw: 3, Lose: 4}); !oddsClose(game.Odds, want) {
+		t.Errorf("odds = %+v, want %+v", game.Odds, want)
+	}
+	if want := (Odds{Win: 1.8, Lose: 2.2}); game.DrawNoBet == nil || !oddsClose(*game.DrawNoBet, want) {
+		t.Errorf("draw no bet = %+v, want %+v", game.DrawNoBet, want)
+	}
+}
+
+func TestOddsFromProbabilities(t *testing.T) {
+	tests := []struct {
+		name            string
+		win, draw, lose float64
+		want            Odds
+	}{
+		{name: "three-way", win: 0.4, draw: 0.3, lose: 0.3, want: Odds{Win: 2.5, Draw: 1 / 0.3, Lose: 1 / 0.3}},
+		{name: "two-way keeps zero draw", win: 0.5, lose: 0.5, want: Odds{Win: 2, Lose: 2}},
+		{name: "certain outcome", win: 1, draw: 0.5, lose: 0.5, want: Odds{Win: 1, Draw: 2, Lose: 2}},
+		{name: "negative and above 1 give zero odds", win: -0.2, draw: 1.5, lose: 0.3, want: Odds{Lose: 1 / 0.3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := oddsFromProbabilities(tt.win, tt.draw, tt.lose); !oddsClose(got, tt.want) {
+				t.Errorf("oddsFromProbabilities(%v, %v, %v) = %+v, want %+v", tt.win, tt.draw, tt.lose, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProbabilitiesSumToOne(t *testing.T) {
+	tests := []struct {
+		odds Odds
+		want bool
+	}{
+		{odds: Odds{Win: 0.4, Draw: 0.3, Lose: 0.3}, want: true},
+		{odds: Odds{Win: 0.5, Lose: 0.505}, want: true},
+		{odds: Odds{Win: 0.4, Draw: 0.4, Lose: 0.3}},
+		{odds: Odds{Win: 0, Draw: 0.5, Lose: 0.5}},
+	}
+	for _, tt := range tests {
+		if got := probabilitiesSumToOne(tt.odds); got != tt.want {
+			t.Errorf("probabilitiesSumToOne(%+v) = %v, want %v", tt.odds, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeProbabilitiesWarnsAboutUnbalancedGames(t *testing.T) {
+	bookmakers := []Bookmaker{{Name: "a", Games: []Game{
+		{ID: "g1", Odds: Odds{Win: 0.4, Draw: 0.3, Lose: 0.3}},
+		{ID: "g2", Odds: Odds{Win: 0.5, Draw: 0.3, Lose: 0.3}},
+	}}}
+	warning := captureStderr(t, func() { normalizeOddsBasis(bookmakers, OddsProbability) })
+	if want := "Warning: 1 games have probabilities that are not positive or do not sum to 1\n"; warning != want {
+		t.Errorf("warning = %q, want %q", warning, want)
+	}
+	if want := (Odds{Win: 2.5, Draw: 1 / 0.3, Lose: 1 / 0.3}); !oddsClose(bookmakers[0].Games[0].Odds, want) {
+		t.Errorf("odds = %+v, want %+v", bookmakers[0].Games[0].Odds, want)
+	}
+}
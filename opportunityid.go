@@ -0,0 +1,21 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Derive a stable ID for an opportunity from its fixture, the bookmakers of
+// its legs and their odds rounded to two decimals, so the same opportunity
+// seen in separate scans gets the same ID and a change in any selected price
+// gets a new one. Stakes are not part of the ID as they depend on options.
+func opportunityID(opportunity ArbitrageOpportunity) string {
+	parts := []string{opportunity.GameID, opportunity.Market}
+	for _, leg := range opportunityLegs(opportunity) {
+		parts = append(parts, fmt.Sprintf("%s=%s@%.2f", leg.Outcome, leg.Bookmaker, leg.Odds))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:8])
+}
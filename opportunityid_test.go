@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOpportunityID(t *testing.T) {
+	base := ArbitrageOpportunity{GameID: "g1", Odds: Odds{Win: 2.5, Draw: 3.6, Lose: 4.2},
+		WinBookmaker: "a", DrawBookmaker: "b", LoseBookmaker: "c", TotalBet: 100, WinStake: 40}
+	id := opportunityID(base)
+	if len(id) != 16 {
+		t.Errorf("opportunityID() = %q, want 16 hex digits", id)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*ArbitrageOpportunity)
+		same   bool
+	}{
+		{name: "identical", change: func(*ArbitrageOpportunity) {}, same: true},
+		{name: "different stakes", change: func(o *ArbitrageOpportunity) { o.TotalBet, o.WinStake = 500, 200 }, same: true},
+		{name: "odds within rounding", change: func(o *ArbitrageOpportunity) { o.Odds.Win = 2.501 }, same: true},
+		{name: "changed odds", change: func(o *ArbitrageOpportunity) { o.Odds.Win = 2.55 }},
+		{name: "different bookmaker", change: func(o *ArbitrageOpportunity) { o.DrawBookmaker = "d" }},
+		{name: "different fixture", change: func(o *ArbitrageOpportunity) { o.GameID = "g2" }},
+		{name: "different market", change: func(o *ArbitrageOpportunity) { o.Market = marketDrawNoBet }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := base
+			tt.change(&changed)
+			if got := opportunityID(changed); (got == id) != tt.same {
+				t.Errorf("opportunityID() = %q, base %q, want same %v", got, id, tt.same)
+			}
+		})
+	}
+}
+
+func TestDetectedOpportunityIDsStableAcrossScans(t *testing.T) {
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1}
+	first, _ := detectArbitrageOpportunities(context.Background(), streamedBookmakers(), opts)
+	opts.TotalBet = 250
+	second, _ := detectArbitrageOpportunities(context.Background(), streamedBookmakers(), opts)
+	if len(first) == 0 || len(first) != len(second) {
+		t.Fatalf("scans found %d and %d opportunities", len(first), len(second))
+	}
+	for i := range first {
+		if first[i].ID == "" || first[i].ID != second[i].ID {
+			t.Errorf("opportunity %s IDs = %q and %q, want the same", first[i].GameID, first[i].ID, second[i].ID)
+		}
+	}
+	if first[0].ID == first[1].ID {
+		t.Errorf("games %s and %s share ID %q", first[0].GameID, first[1].GameID, first[0].ID)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+// Detect arbitrage opportunities and send them on a channel as they are
+// found, applying scan options to each. Opportunities within each bookmaker's
+// own odds follow when intra is set. The channel is closed when detection
+// finishes or ctx is cancelled.
+func streamArbitrageOpportunities(ctx context.Context, bookmakers []Bookmaker, opts scanOptions, intra bool) <-chan ArbitrageOpportunity {
+	opportunities := make(chan ArbitrageOpportunity)
+	go func() {
+		defer close(opportunities)
+		send := func(opportunity ArbitrageOpportunity) bool {
+			opportunity, ok := applyScanOptionsTo(opportunity, opts)
+			if !ok {
+				return true
+			}
+			select {
+			case opportunities <- opportunity:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		stopped := false
+		partial := eachArbitrageOpportunity(ctx, bookmakers, opts, func(opportunity ArbitrageOpportunity) bool {
+			stopped = !send(opportunity)
+			return !stopped
+		})
+		if partial && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			warn(partialResultsWarning)
+		}
+		if stopped || partial || !intra {
+			return
+		}
+		for _, bookmaker := range bookmakers {
+			for _, opportunity := range findIntraBookmakerArbitrage(bookmaker, opts.TotalBet, opts.ArbThreshold) {
+				if !send(opportunity) {
+					return
+				}
+			}
+		}
+	}()
+	return opportunities
+}
+
+// Send already collected opportunities on a channel, closing it when done or
+// when ctx is cancelled
+func sendOpportunities(ctx context.Context, opps []ArbitrageOpportunity) <-chan ArbitrageOpportunity {
+	opportunities := make(chan ArbitrageOpportunity)
+	go func() {
+		defer close(opportunities)
+		for _, opportunity := range opps {
+			select {
+			case opportunities <- opportunity:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return opportunities
+}
+
+// Write opportunities as a JSON array incrementally, one element at a time,
+// without holding the whole result set in memory
+func streamOpportunities(w io.Writer, opps <-chan ArbitrageOpportunity) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	first := true
+	for opportunity := range opps {
+		data, err := json.Marshal(opportunity)
+		if err != nil {
+			return err
+		}
+		separator := ",\n"
+		if first {
+			separator = "\n"
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		first = false
+	}
+	if !first {
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
+// Define the structure of an opportunity written as a line of NDJSON
+type ndjsonOpportunity struct {
+	ScannedAt time.Time `json:"scanned_at"`
+	ArbitrageOpportunity
+}
+
+// Write opportunities as newline-delimited JSON, one compact object per
+// line, each stamped with the time of the scan
+func streamOpportunitiesNDJSON(w io.Writer, opps <-chan ArbitrageOpportunity, scannedAt time.Time) error {
+	encoder := json.NewEncoder(w)
+	for opportunity := range opps {
+		if err := encoder.Encode(ndjsonOpportunity{ScannedAt: scannedAt, ArbitrageOpportunity: opportunity}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pass opportunities through to a writer, counting each one it takes and
+// recording it in metrics when set. The returned function stops the pass
+// and reports the count once no more can be added.
+func countOpportunities(opps <-chan ArbitrageOpportunity, metrics *ScanMetrics) (<-chan ArbitrageOpportunity, func() int) {
+	counted := make(chan ArbitrageOpportunity)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	count := 0
+	go func() {
+		defer close(done)
+		defer close(counted)
+		for opportunity := range opps {
+			select {
+			case counted <- opportunity:
+				metrics.add(opportunity)
+				count++
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return counted, func() int {
+		close(stop)
+		<-done
+		return count
+	}
+}
+
+// Return a channel of the opportunities to report, streaming them unless a
+// bankroll selection, result cap or sort order needs the full set first
+func reportedOpportunities(ctx context.Context, bookmakers []Bookmaker, opts scanOptions, intra bool) <-chan ArbitrageOpportunity {
+	if opts.Bankroll <= 0 && opts.MaxResults <= 0 && opts.Sort == SortByGame {
+		return streamArbitrageOpportunities(ctx, bookmakers, opts, intra)
+	}
+	var all []ArbitrageOpportunity
+	for opportunity := range streamArbitrageOpportunities(ctx, bookmakers, opts, intra) {
+		all = append(all, opportunity)
+	}
+	if opts.Bankroll > 0 {
+		all = selectOpportunities(all, opts.Bankroll)
+	}
+	all, omitted := limitOpportunities(all, opts.MaxResults)
+	if omitted > 0 {
+		warn("%d opportunities omitted by -max-results", omitted)
+	}
+	if opts.Sort != SortByGame {
+		sortOpportunities(all, opts.Sort)
+	}
+	return sendOpportunities(ctx, all)
+}
+
+// Write the opportunities in bookmakers data as a JSON array, recording
+// them in metrics when set
+func writeOpportunitiesJSON(ctx context.Context, w io.Writer, bookmakers []Bookmaker, opts scanOptions, intra bool, metrics *ScanMetrics) (int, error) {
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	opps, written := countOpportunities(reportedOpportunities(scanCtx, bookmakers, opts, intra), metrics)
+	err := streamOpportunities(w, opps)
+	count := written()
+	recordPartial(ctx, metrics)
+	return count, err
+}
+
+// Write the opportunities in bookmakers data as newline-delimited JSON,
+// recording them in metrics when set
+func writeOpportunitiesNDJSON(ctx context.Context, w io.Writer, bookmakers []Bookmaker, opts scanOptions, intra bool, metrics *ScanMetrics) (int, error) {
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	opps, written := countOpportunities(reportedOpportunities(scanCtx, bookmakers, opts, intra), metrics)
+	err := streamOpportunitiesNDJSON(w, opps, time.Now().UTC())
+	count := written()
+	recordPartial(ctx, metrics)
+	return count, err
+}
+
+// Mark streamed results as partial when the scan's time budget ran out
+// while they were written
+func recordPartial(ctx context.Context, metrics *ScanMetrics) {
+	if metrics != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		metrics.Partial = true
+	}
+}
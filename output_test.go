@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Two fixtures with an arbitrage across bookmakers a and b
+func streamedBookmakers() []Bookmaker {
+	return []Bookmaker{
+		{Name: "a", Games: []Game{
+			{ID: "g1", Odds: Odds{Win: 2.6, Draw: 3.3, Lose: 3.3}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 3.3, Draw: 3.3, Lose: 2.6}, Available: true},
+		}},
+		{Name: "b", Games: []Game{
+			{ID: "g1", Odds: Odds{Win: 3.3, Draw: 3.6, Lose: 4.2}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 4.2, Draw: 3.6, Lose: 3.3}, Available: true},
+		}},
+	}
+}
+
+// Encode and decode opportunities the way a consumer of the JSON output sees
+// them
+func decodedOpportunities(t *testing.T, opportunities []ArbitrageOpportunity) []ArbitrageOpportunity {
+	t.Helper()
+	data, err := json.Marshal(opportunities)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []ArbitrageOpportunity
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestStreamOpportunitiesRoundTrip(t *testing.T) {
+	want, _ := detectArbitrageOpportunities(context.Background(), streamedBookmakers(), scanOptions{TotalBet: 100, ArbThreshold: 1})
+	if len(want) != 2 {
+		t.Fatalf("got %d opportunities, want 2", len(want))
+	}
+
+	var buf bytes.Buffer
+	if err := streamOpportunities(&buf, sendOpportunities(context.Background(), want)); err != nil {
+		t.Fatalf("streamOpportunities() = %v", err)
+	}
+	var got []ArbitrageOpportunity
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("streamed output is not a JSON array: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(got, decodedOpportunities(t, want)) {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
+
+func TestStreamOpportunitiesEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	err := streamOpportunities(&buf, sendOpportunities(context.Background(), nil))
+	if err != nil || buf.String() != "[]\n" {
+		t.Errorf("streamOpportunities() = %v, %q, want an empty array", err, buf.String())
+	}
+}
+
+// Fail every write after the first n
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n <= 0 {
+		return 0, errors.New("disk full")
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestStreamOpportunitiesWriteError(t *testing.T) {
+	opportunities, _ := detectArbitrageOpportunities(context.Background(), streamedBookmakers(), scanOptions{TotalBet: 100, ArbThreshold: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := streamOpportunities(&failingWriter{n: 3}, sendOpportunities(ctx, opportunities)); err == nil {
+		t.Error("streamOpportunities() = nil, want the write error")
+	}
+}
+
+func TestCountOpportunities(t *testing.T) {
+	opportunities, _ := detectArbitrageOpportunities(context.Background(), streamedBookmakers(), scanOptions{TotalBet: 100, ArbThreshold: 1})
+	tests := []struct {
+		name string
+		take int
+		want int
+	}{
+		{"drained", len(opportunities), len(opportunities)},
+		{"stopped early", 1, 1},
+		{"stopped before any", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			metrics := &ScanMetrics{}
+			counted, count := countOpportunities(sendOpportunities(ctx, opportunities), metrics)
+			for i := 0; i < tt.take; i++ {
+				<-counted
+			}
+			if got := count(); got != tt.want || metrics.Opportunities != tt.want {
+				t.Errorf("count() = %d with %d recorded, want %d", got, metrics.Opportunities, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamArbitrageOpportunitiesMatchesDetection(t *testing.T) {
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1}
+	detected, _ := detectArbitrageOpportunities(context.Background(), streamedBookmakers(), opts)
+	want := applyScanOptions(detected, opts)
+
+	var got []ArbitrageOpportunity
+	for opportunity := range streamArbitrageOpportunities(context.Background(), streamedBookmakers(), opts, false) {
+		got = append(got, opportunity)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("streamed %+v, want %+v", got, want)
+	}
+}
+
+func TestStreamArbitrageOpportunitiesStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	opportunities := streamArbitrageOpportunities(ctx, streamedBookmakers(), scanOptions{TotalBet: 100, ArbThreshold: 1}, true)
+	<-opportunities
+	cancel()
+	// The channel must be closed rather than block the stopped consumer
+	for range opportunities {
+	}
+}
+
+func TestStreamOpportunitiesNDJSON(t *testing.T) {
+	opportunities, _ := detectArbitrageOpportunities(context.Background(), streamedBookmakers(), scanOptions{TotalBet: 100, ArbThreshold: 1})
+	scannedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := streamOpportunitiesNDJSON(&buf, sendOpportunities(context.Background(), opportunities), scannedAt); err != nil {
+		t.Fatalf("streamOpportunitiesNDJSON() = %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	want := decodedOpportunities(t, opportunities)
+	for i, line := range lines {
+		var got ndjsonOpportunity
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d does not decode on its own: %v\n%s", i+1, err, line)
+		}
+		if !got.ScannedAt.Equal(scannedAt) {
+			t.Errorf("line %d scanned at %v, want %v", i+1, got.ScannedAt, scannedAt)
+		}
+		if !reflect.DeepEqual(got.ArbitrageOpportunity, want[i]) {
+			t.Errorf("line %d = %+v, want %+v", i+1, got.ArbitrageOpportunity, want[i])
+		}
+		if !strings.Contains(line, `"scanned_at":"2024-05-01T12:00:00Z"`) {
+			t.Errorf("line %d lacks the scan timestamp: %s", i+1, line)
+		}
+	}
+}
+
+func TestWriteOpportunitiesNDJSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	written, err := writeOpportunitiesNDJSON(context.Background(), &buf, nil, scanOptions{TotalBet: 100, ArbThreshold: 1}, false, nil)
+	if err != nil || written != 0 || buf.Len() != 0 {
+		t.Errorf("writeOpportunitiesNDJSON() = %d, %v, %q, want no lines", written, err, buf.String())
+	}
+}
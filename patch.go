@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Define the structure for a change to one bookmaker's odds for a game
+type OddsUpdate struct {
+	Bookmaker string `json:"bookmaker"`
+	GameID    string `json:"game_id"`
+	Odds      Odds   `json:"odds"`
+}
+
+// Read an odds patch from a JSON file holding an array of updates
+func loadOddsPatch(filename string) ([]OddsUpdate, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, newFileError(filename, err)
+	}
+	var patch []OddsUpdate
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, &FileError{Path: filename, Err: ErrParse, Cause: err}
+	}
+	return patch, nil
+}
+
+// Apply odds updates to bookmakers, returning the updated set and leaving
+// the input unchanged. Later updates to the same game win. Updates are all
+// or nothing: when any targets a bookmaker or game that does not exist,
+// nothing is applied and the error lists every unknown target.
+func applyOddsPatch(bookmakers []Bookmaker, patch []OddsUpdate) ([]Bookmaker, error) {
+	type target struct{ bookmaker, gameID string }
+	positions := make(map[target][2]int)
+	for i, bookmaker := range bookmakers {
+		for j, game := range bookmaker.Games {
+			positions[target{bookmaker.Name, game.ID}] = [2]int{i, j}
+		}
+	}
+
+	var errs []error
+	for _, update := range patch {
+		if _, exists := positions[target{update.Bookmaker, update.GameID}]; !exists {
+			errs = append(errs, &GameError{Bookmaker: update.Bookmaker, GameID: update.GameID, Err: ErrGameNotFound})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	patched := append([]Bookmaker(nil), bookmakers...)
+	copied := make(map[int]bool)
+	for _, update := range patch {
+		position := positions[target{update.Bookmaker, update.GameID}]
+		i, j := position[0], position[1]
+		if !copied[i] {
+			patched[i].Games = append([]Game(nil), patched[i].Games...)
+			copied[i] = true
+		}
+		patched[i].Games[j].Odds = update.Odds
+	}
+	return patched, nil
+}
+
+// Save bookmakers by writing a temporary file next to filename and renaming
+// it over the original, so readers never see a partly written file
+func saveBookmakersAtomically(bookmakers []Bookmaker, filename string, retry retryPolicy) error {
+	return replaceFileAtomically(filename, func(tempName string) error {
+		return saveBookmakers(bookmakers, tempName, retry)
+	})
+}
+
+// Replace a file by having write create its new contents in a temporary file
+// in the same directory, with the same extension so the format is kept, and
+// renaming that over filename. The original's permissions are kept.
+func replaceFileAtomically(filename string, write func(tempName string) error) error {
+	ext := filepath.Ext(filename)
+	temp, err := os.CreateTemp(filepath.Dir(filename), "."+strings.TrimSuffix(filepath.Base(filename), ext)+".*"+ext)
+	if err != nil {
+		return newFileError(filename, err)
+	}
+	tempName := temp.Name()
+	temp.Close()
+	// Keep the original's permissions rather than the temporary file's
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.Chmod(tempName, mode); err != nil {
+		os.Remove(tempName)
+		return newFileError(tempName, err)
+	}
+	if err := write(tempName); err != nil {
+		os.Remove(tempName)
+		return err
+	}
+	if err := os.Rename(tempName, filename); err != nil {
+		os.Remove(tempName)
+		return newFileError(filename, err)
+	}
+	return nil
+}
+
+// Apply an odds patch to a bookmakers file and atomically rewrite it. The
+// file is read as written, so patch odds must be on the file's odds basis
+// and event times are left untouched. Returns how many updates were applied.
+func patchBookmakersFile(filename string, patch []OddsUpdate, opts loadOptions, retry retryPolicy) (int, error) {
+	opts.OddsBasis = OddsDecimal
+	opts.RawEventTimes = true
+	bookmakers, err := loadBookmakers(filename, opts)
+	if err != nil {
+		return 0, err
+	}
+	if bookmakers, err = applyOddsPatch(bookmakers, patch); err != nil {
+		return 0, err
+	}
+	return len(patch), saveBookmakersAtomically(bookmakers, filename, retry)
+}
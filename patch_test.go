@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestApplyOddsPatch(t *testing.T) {
+	boosted := Odds{Win: 2.5, Draw: 4, Lose: 4}
+	tests := []struct {
+		name    string
+		patch   []OddsUpdate
+		want    Odds
+		wantErr error
+	}{
+		{name: "one game", patch: []OddsUpdate{{Bookmaker: "b", GameID: "planted", Odds: boosted}}, want: boosted},
+		{name: "later update wins", patch: []OddsUpdate{
+			{Bookmaker: "b", GameID: "planted", Odds: Odds{Win: 9, Draw: 9, Lose: 9}},
+			{Bookmaker: "b", GameID: "planted", Odds: boosted},
+		}, want: boosted},
+		{name: "unknown bookmaker", patch: []OddsUpdate{
+			{Bookmaker: "b", GameID: "planted", Odds: boosted},
+			{Bookmaker: "missing", GameID: "planted", Odds: boosted},
+		}, wantErr: ErrGameNotFound},
+		{name: "unknown game", patch: []OddsUpdate{{Bookmaker: "b", GameID: "missing", Odds: boosted}}, wantErr: ErrGameNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookmakers := plantedBookmakers()
+			got, err := applyOddsPatch(bookmakers, tt.patch)
+			if !reflect.DeepEqual(bookmakers, plantedBookmakers()) {
+				t.Errorf("the input was changed to %+v", bookmakers)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || got != nil {
+					t.Errorf("applyOddsPatch() = %+v, %v, want %v", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got[1].Games[0].Odds != tt.want {
+				t.Errorf("patched odds = %+v, want %+v", got[1].Games[0].Odds, tt.want)
+			}
+			if !reflect.DeepEqual(got[0], plantedBookmakers()[0]) {
+				t.Errorf("an unpatched bookmaker changed to %+v", got[0])
+			}
+		})
+	}
+}
+
+func TestPatchBookmakersFile(t *testing.T) {
+	filename := writeTestData(t, plantedBookmakers())
+	if err := os.Chmod(filename, 0640); err != nil {
+		t.Fatal(err)
+	}
+	boosted := Odds{Win: 2.5, Draw: 4, Lose: 4}
+	applied, err := patchBookmakersFile(filename, []OddsUpdate{{Bookmaker: "b", GameID: "planted", Odds: boosted}}, loadOptions{}, retryPolicy{})
+	if err != nil || applied != 1 {
+		t.Fatalf("patchBookmakersFile() = %d, %v, want 1 applied", applied, err)
+	}
+
+	got, err := loadBookmakers(filename, loadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := plantedBookmakers()
+	want[1].Games[0].Odds = boosted
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("patched file holds %+v, want %+v", got, want)
+	}
+	if info, err := os.Stat(filename); err != nil || info.Mode().Perm() != 0640 {
+		t.Errorf("patched file mode = %v, %v, want 0640", info.Mode().Perm(), err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(filename)); len(entries) != 1 {
+		t.Errorf("patching left %d files, want only the original", len(entries))
+	}
+
+	// A patch with an unknown target leaves the file alone
+	before, _ := os.ReadFile(filename)
+	if _, err := patchBookmakersFile(filename, []OddsUpdate{{Bookmaker: "missing", GameID: "planted"}}, loadOptions{}, retryPolicy{}); !errors.Is(err, ErrGameNotFound) {
+		t.Errorf("error = %v, want %v", err, ErrGameNotFound)
+	}
+	if after, _ := os.ReadFile(filename); string(after) != string(before) {
+		t.Error("a failed patch rewrote the file")
+	}
+}
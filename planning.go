@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// Calculate the decimal odds needed on the missing leg for a set of odds to
+// break even, given the two known legs. Any better price makes the set an
+// arbitrage. The value of the missing leg in known is ignored. If the known
+// legs alone already imply a probability of 1 or more no price is enough and
+// +Inf is returned; an unknown leg name returns NaN. A draw of zero marks a
+// two-way market, whose draw leg is skipped.
+func breakEvenOdds(known Odds, missingLeg string) float64 {
+	var draw float64
+	if known.Draw != 0 {
+		draw = 1 / known.Draw
+	}
+	var implied float64
+	switch missingLeg {
+	case OutcomeWin:
+		implied = draw + 1/known.Lose
+	case OutcomeDraw:
+		implied = 1/known.Win + 1/known.Lose
+	case OutcomeLose:
+		implied = 1/known.Win + draw
+	default:
+		return math.NaN()
+	}
+	if implied >= 1 {
+		return math.Inf(1)
+	}
+	return 1 / (1 - implied)
+}
+
+// Calculate the stakes on the remaining outcomes that hedge an open position,
+// so that every outcome pays the same as the existing leg would. The returned
+// profit is locked in whichever outcome happens and is negative when the
+// remaining odds are too short to hedge at a profit. Unlike a fresh arbitrage
+// the existing stake is fixed and only the hedges are sized.
+func hedgeStakes(existingLeg Leg, otherOdds []float64) ([]float64, float64) {
+	payout := existingLeg.Stake * existingLeg.Odds
+	stakes := make([]float64, len(otherOdds))
+	total := existingLeg.Stake
+	for i, odds := range otherOdds {
+		stakes[i] = payout / odds
+		total += stakes[i]
+	}
+	return stakes, payout - total
+}
+
+// Plan the stakes for the remaining legs of a position when some legs are
+// already placed, possibly at odds that have since moved. Placed legs'
+// payouts are fixed, so every remaining leg is sized to pay the smallest of
+// them, which maximizes the worst-case profit. The returned legs are the
+// remaining legs with their stakes filled in, along with the profit
+// guaranteed whichever outcome happens; ok is false when that profit is not
+// positive and the arbitrage can no longer be completed. With a single
+// placed leg this equals hedgeStakes. Fresh positions with nothing placed
+// are sized by calculateStakes instead.
+func planRemainingStakes(placed, remaining []Leg) (stakes []Leg, profit float64, ok bool) {
+	if len(placed) == 0 {
+		return nil, 0, false
+	}
+	payout := math.Inf(1)
+	total := 0.0
+	for _, leg := range placed {
+		payout = math.Min(payout, leg.Stake*leg.Odds)
+		total += leg.Stake
+	}
+	stakes = make([]Leg, len(remaining))
+	for i, leg := range remaining {
+		if !(leg.Odds > 1) {
+			return nil, 0, false
+		}
+		leg.Stake = payout / leg.Odds
+		total += leg.Stake
+		stakes[i] = leg
+	}
+	profit = payout - total
+	return stakes, profit, profit > 0
+}
+
+// Calculate the total stake, and its allocation, needed for a set of odds to
+// guarantee targetProfit. This inverts calculateStakes: a position of total
+// T on odds with arbitrage percentage p pays T/p on every outcome, so
+// T = targetProfit * p / (1 - p). Zero draw odds mean a two-way market.
+// Odds that are not an arbitrage can never guarantee a profit and return
+// ErrNoArbitrage.
+func stakesForTargetProfit(odds Odds, targetProfit float64) (total float64, stakes StakeAllocation, err error) {
+	if !(odds.Win > 1) || !(odds.Lose > 1) || (odds.Draw != 0 && !(odds.Draw > 1)) {
+		return 0, StakeAllocation{}, fmt.Errorf("%w: every leg must be above 1", ErrInvalidOdds)
+	}
+	percentage := calculateArbitragePercentage(odds)
+	if !(percentage < 1) {
+		return 0, StakeAllocation{}, fmt.Errorf("%w: arbitrage percentage %.4f", ErrNoArbitrage, percentage)
+	}
+	total = targetProfit * percentage / (1 - percentage)
+	if err := checkTotalBet(total); err != nil {
+		return 0, StakeAllocation{}, err
+	}
+	return total, calculateStakes(odds, total), nil
+}
+
+// Build the stake plan for one fixture from the best odds across bookmakers
+// for it, without scanning the rest of the data. Returns ErrGameNotFound when
+// no bookmaker has the game available and ErrNoArbitrage when its best odds
+// are not an arbitrage.
+func stakePlan(bookmakers []Bookmaker, gameID string, totalBet float64) (ArbitrageOpportunity, error) {
+	fixture := fixtureBookmakers(bookmakers, gameID)
+	best, exists := findBestOddsWithSource(fixture)[gameID]
+	if !exists {
+		return ArbitrageOpportunity{}, &GameError{GameID: gameID, Err: ErrGameNotFound}
+	}
+
+	sport := fixtureSports(fixture)[gameID]
+	odds := oddsForSport(best.Odds, sport)
+	if err := checkOdds(odds, sport); err != nil {
+		return ArbitrageOpportunity{}, &GameError{GameID: gameID, Err: err}
+	}
+	if percentage := calculateArbitragePercentage(odds); percentage >= 1 {
+		return ArbitrageOpportunity{}, &GameError{GameID: gameID, Err: fmt.Errorf("%w: arbitrage percentage %.4f", ErrNoArbitrage, percentage)}
+	}
+	opportunity := newArbitrageOpportunity(gameID, odds, totalBet)
+	opportunity.Sport = sport
+	opportunity.WinBookmaker = best.WinSource
+	opportunity.LoseBookmaker = best.LoseSource
+	if odds.Draw != 0 {
+		opportunity.DrawBookmaker = best.DrawSource
+	}
+	opportunity.ID = opportunityID(opportunity)
+	return opportunity, nil
+}
+
+// Print a stake plan: the best odds with their bookmakers, the stake on each
+// leg and the guaranteed profit
+func printStakePlan(w io.Writer, opportunity ArbitrageOpportunity, out outputOptions) {
+	labels := outcomesForSport(opportunity.Sport)
+	for _, leg := range opportunityLegs(opportunity) {
+		fmt.Fprintf(w, "%s: %.*f at %s, stake %.*f\n", outcomeLabel(labels, leg.Outcome),
+			out.OddsPrecision, leg.Odds, leg.Bookmaker, out.Precision, leg.Stake)
+	}
+	fmt.Fprintf(w, "Guaranteed profit: %.*f\n", out.Precision, opportunity.GuaranteedProfit)
+}
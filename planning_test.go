@@ -0,0 +1,289 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBreakEvenOdds(t *testing.T) {
+	tests := []struct {
+		name    string
+		known   Odds
+		missing string
+		want    float64
+	}{
+		{name: "missing win", known: Odds{Draw: 4, Lose: 4}, missing: OutcomeWin, want: 2},
+		{name: "missing draw", known: Odds{Win: 2.5, Lose: 5}, missing: OutcomeDraw, want: 1 / (1 - 0.4 - 0.2)},
+		{name: "missing lose ignores its own value", known: Odds{Win: 3, Draw: 3, Lose: 1.1}, missing: OutcomeLose, want: 3},
+		{name: "known legs already at 100%", known: Odds{Win: 2, Draw: 2}, missing: OutcomeLose, want: math.Inf(1)},
+		{name: "two-way missing win", known: Odds{Lose: 2.5}, missing: OutcomeWin, want: 1 / (1 - 0.4)},
+		{name: "two-way missing lose", known: Odds{Win: 1.8, Lose: 1.1}, missing: OutcomeLose, want: 1 / (1 - 1/1.8)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := breakEvenOdds(tt.known, tt.missing)
+			if !(got == tt.want || math.Abs(got-tt.want) < 1e-9) {
+				t.Fatalf("breakEvenOdds() = %v, want %v", got, tt.want)
+			}
+			if math.IsInf(got, 1) {
+				return
+			}
+			// Plugging the odds back in must land exactly on break-even
+			odds := tt.known
+			switch tt.missing {
+			case OutcomeWin:
+				odds.Win = got
+			case OutcomeDraw:
+				odds.Draw = got
+			case OutcomeLose:
+				odds.Lose = got
+			}
+			if pct := calculateArbitragePercentage(odds); math.Abs(pct-1) > 1e-9 {
+				t.Errorf("arbitrage percentage with the break-even odds = %v, want 1", pct)
+			}
+		})
+	}
+	if got := breakEvenOdds(Odds{Win: 2, Draw: 4, Lose: 4}, "home"); !math.IsNaN(got) {
+		t.Errorf("breakEvenOdds() of an unknown leg = %v, want NaN", got)
+	}
+}
+
+func TestHedgeStakes(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   Leg
+		otherOdds  []float64
+		wantStakes []float64
+		wantProfit float64
+	}{
+		{
+			name:       "win bet hedged at a profit",
+			existing:   Leg{Outcome: OutcomeWin, Odds: 3, Stake: 100},
+			otherOdds:  []float64{4, 5},
+			wantStakes: []float64{75, 60},
+			wantProfit: 65,
+		},
+		{
+			name:       "hedges too short to profit",
+			existing:   Leg{Outcome: OutcomeWin, Odds: 2, Stake: 100},
+			otherOdds:  []float64{3, 3},
+			wantStakes: []float64{200.0 / 3, 200.0 / 3},
+			wantProfit: 200 - 100 - 400.0/3,
+		},
+		{
+			name:       "two-way market",
+			existing:   Leg{Outcome: OutcomeWin, Odds: 2.5, Stake: 40},
+			otherOdds:  []float64{2},
+			wantStakes: []float64{50},
+			wantProfit: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stakes, profit := hedgeStakes(tt.existing, tt.otherOdds)
+			if len(stakes) != len(tt.wantStakes) {
+				t.Fatalf("stakes = %v, want %v", stakes, tt.wantStakes)
+			}
+			total := tt.existing.Stake
+			for i, stake := range stakes {
+				if math.Abs(stake-tt.wantStakes[i]) > 1e-9 {
+					t.Errorf("stake %d = %v, want %v", i, stake, tt.wantStakes[i])
+				}
+				total += stake
+			}
+			if math.Abs(profit-tt.wantProfit) > 1e-9 {
+				t.Errorf("profit = %v, want %v", profit, tt.wantProfit)
+			}
+			// Every outcome nets the locked profit
+			if net := tt.existing.Stake*tt.existing.Odds - total; math.Abs(net-profit) > 1e-9 {
+				t.Errorf("existing leg nets %v, want %v", net, profit)
+			}
+			for i, odds := range tt.otherOdds {
+				if net := stakes[i]*odds - total; math.Abs(net-profit) > 1e-9 {
+					t.Errorf("hedge %d nets %v, want %v", i, net, profit)
+				}
+			}
+		})
+	}
+}
+
+func TestPlanRemainingStakes(t *testing.T) {
+	tests := []struct {
+		name       string
+		placed     []Leg
+		remaining  []Leg
+		wantStakes []float64
+		wantProfit float64
+		wantOK     bool
+	}{
+		{
+			name:       "one leg pre-funded",
+			placed:     []Leg{{Outcome: OutcomeWin, Odds: 2, Stake: 50}},
+			remaining:  []Leg{{Outcome: OutcomeDraw, Odds: 4}, {Outcome: OutcomeLose, Odds: 5}},
+			wantStakes: []float64{25, 20},
+			wantProfit: 5,
+			wantOK:     true,
+		},
+		{
+			name:       "placed legs pay differently",
+			placed:     []Leg{{Outcome: OutcomeWin, Odds: 2, Stake: 60}, {Outcome: OutcomeDraw, Odds: 4, Stake: 25}},
+			remaining:  []Leg{{Outcome: OutcomeLose, Odds: 5}},
+			wantStakes: []float64{20},
+			wantProfit: 100 - 105,
+		},
+		{
+			name:       "odds moved against us",
+			placed:     []Leg{{Outcome: OutcomeWin, Odds: 2, Stake: 50}},
+			remaining:  []Leg{{Outcome: OutcomeDraw, Odds: 3}, {Outcome: OutcomeLose, Odds: 4}},
+			wantStakes: []float64{100.0 / 3, 25},
+			wantProfit: 100 - 50 - 100.0/3 - 25,
+		},
+		{
+			name:      "nothing placed",
+			remaining: []Leg{{Outcome: OutcomeWin, Odds: 2}},
+		},
+		{
+			name:      "remaining odds not above 1",
+			placed:    []Leg{{Outcome: OutcomeWin, Odds: 2, Stake: 50}},
+			remaining: []Leg{{Outcome: OutcomeDraw, Odds: 1}, {Outcome: OutcomeLose, Odds: 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stakes, profit, ok := planRemainingStakes(tt.placed, tt.remaining)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if len(stakes) != len(tt.wantStakes) {
+				t.Fatalf("stakes = %+v, want %v", stakes, tt.wantStakes)
+			}
+			for i, leg := range stakes {
+				if leg.Outcome != tt.remaining[i].Outcome || math.Abs(leg.Stake-tt.wantStakes[i]) > 1e-9 {
+					t.Errorf("leg %d = %+v, want %s staking %v", i, leg, tt.remaining[i].Outcome, tt.wantStakes[i])
+				}
+			}
+			if math.Abs(profit-tt.wantProfit) > 1e-9 {
+				t.Errorf("profit = %v, want %v", profit, tt.wantProfit)
+			}
+		})
+	}
+}
+
+func TestPlanRemainingStakesGeneralizesCalculateStakes(t *testing.T) {
+	odds := Odds{Win: 2.5, Draw: 3.6, Lose: 4.2}
+	fresh := calculateStakes(odds, 100)
+	placed := []Leg{{Outcome: OutcomeWin, Odds: odds.Win, Stake: fresh.Win}}
+	stakes, _, ok := planRemainingStakes(placed, []Leg{{Outcome: OutcomeDraw, Odds: odds.Draw}, {Outcome: OutcomeLose, Odds: odds.Lose}})
+	if !ok || math.Abs(stakes[0].Stake-fresh.Draw) > 1e-9 || math.Abs(stakes[1].Stake-fresh.Lose) > 1e-9 {
+		t.Errorf("planned %+v, want the fresh stakes %+v", stakes, fresh)
+	}
+
+	hedges, hedgeProfit := hedgeStakes(placed[0], []float64{odds.Draw, odds.Lose})
+	_, profit, _ := planRemainingStakes(placed, []Leg{{Outcome: OutcomeDraw, Odds: odds.Draw}, {Outcome: OutcomeLose, Odds: odds.Lose}})
+	if math.Abs(hedges[0]-stakes[0].Stake) > 1e-9 || math.Abs(hedgeProfit-profit) > 1e-9 {
+		t.Errorf("single placed leg differs from hedgeStakes: %v, %v vs %+v, %v", hedges, hedgeProfit, stakes, profit)
+	}
+}
+
+func TestStakesForTargetProfit(t *testing.T) {
+	tests := []struct {
+		name    string
+		odds    Odds
+		target  float64
+		wantErr error
+	}{
+		{name: "three-way arbitrage", odds: Odds{Win: 2.5, Draw: 3.6, Lose: 4.2}, target: 20},
+		{name: "two-way arbitrage", odds: Odds{Win: 2.2, Lose: 2.2}, target: 5},
+		{name: "not an arbitrage", odds: Odds{Win: 2, Draw: 3, Lose: 4}, target: 20, wantErr: ErrNoArbitrage},
+		{name: "invalid leg", odds: Odds{Win: 1, Draw: 3, Lose: 4}, target: 20, wantErr: ErrInvalidOdds},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, stakes, err := stakesForTargetProfit(tt.odds, tt.target)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(stakes.Total()-total) > 1e-9 {
+				t.Errorf("stakes total %v, want %v", stakes.Total(), total)
+			}
+			// Every outcome must return the total plus exactly the target
+			payouts := []float64{stakes.Win * tt.odds.Win, stakes.Lose * tt.odds.Lose}
+			if tt.odds.Draw != 0 {
+				payouts = append(payouts, stakes.Draw*tt.odds.Draw)
+			}
+			for _, payout := range payouts {
+				if profit := payout - total; math.Abs(profit-tt.target) > 1e-9 {
+					t.Errorf("profit = %v, want %v", profit, tt.target)
+				}
+			}
+		})
+	}
+}
+
+func TestStakePlan(t *testing.T) {
+	bookmakers := append(plantedBookmakers(), Bookmaker{Name: "c", Games: []Game{
+		{ID: "fair", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, Available: true},
+		{ID: "suspended", Odds: Odds{Win: 3, Draw: 4, Lose: 4}},
+	}})
+	tests := []struct {
+		name    string
+		gameID  string
+		wantErr error
+	}{
+		{name: "found", gameID: "planted"},
+		{name: "not found", gameID: "missing", wantErr: ErrGameNotFound},
+		{name: "only suspended", gameID: "suspended", wantErr: ErrGameNotFound},
+		{name: "not an arbitrage", gameID: "fair", wantErr: ErrNoArbitrage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := stakePlan(bookmakers, tt.gameID, 200)
+			if tt.wantErr != nil {
+				var gameErr *GameError
+				if !errors.Is(err, tt.wantErr) || !errors.As(err, &gameErr) || gameErr.GameID != tt.gameID {
+					t.Errorf("error = %v, want %v for game %s", err, tt.wantErr, tt.gameID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := StakeAllocation{Win: 80, Draw: 60, Lose: 60}
+			got := StakeAllocation{Win: plan.WinStake, Draw: plan.DrawStake, Lose: plan.LoseStake}
+			if math.Abs(got.Win-want.Win) > 1e-9 || math.Abs(got.Draw-want.Draw) > 1e-9 || math.Abs(got.Lose-want.Lose) > 1e-9 {
+				t.Errorf("stakes = %+v, want %+v", got, want)
+			}
+			if plan.WinBookmaker != "a" || plan.DrawBookmaker != "b" || plan.LoseBookmaker != "b" {
+				t.Errorf("bookmakers = %s/%s/%s, want a/b/b", plan.WinBookmaker, plan.DrawBookmaker, plan.LoseBookmaker)
+			}
+			if math.Abs(plan.GuaranteedProfit-40) > 1e-9 {
+				t.Errorf("profit = %v, want 40", plan.GuaranteedProfit)
+			}
+		})
+	}
+}
+
+func TestRunStakePlan(t *testing.T) {
+	file := writeTestData(t, plantedBookmakers())
+	code, stdout, _ := runCLI(t, "-stake-plan", "planted", "-total-bet", "200", "-file", file)
+	if code != exitNoOpportunities {
+		t.Errorf("exit code = %d, want %d", code, exitNoOpportunities)
+	}
+	want := "Win: 3.00 at a, stake 80.00\nDraw: 4.00 at b, stake 60.00\nLose: 4.00 at b, stake 60.00\nGuaranteed profit: 40.00\n"
+	if stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+
+	code, stdout, _ = runCLI(t, "-stake-plan", "missing", "-file", file)
+	if code != exitError || !strings.HasPrefix(stdout, "Error planning stakes:") {
+		t.Errorf("missing game exited %d with %q, want an error", code, stdout)
+	}
+}
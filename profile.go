@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// Start a CPU profile written to cpuFile, when set, and return a function
+// that stops it and writes a heap profile to memFile, when set. Both are
+// pprof files for go tool pprof. The stop function must be called for the
+// CPU profile to be flushed.
+func startProfiling(cpuFile, memFile string) (stop func() error, err error) {
+	var cpu *os.File
+	if cpuFile != "" {
+		if cpu, err = os.Create(cpuFile); err != nil {
+			return nil, newFileError(cpuFile, err)
+		}
+		if err := pprof.StartCPUProfile(cpu); err != nil {
+			cpu.Close()
+			return nil, newFileError(cpuFile, err)
+		}
+	}
+	return func() error {
+		var errs []error
+		if cpu != nil {
+			pprof.StopCPUProfile()
+			if err := cpu.Close(); err != nil {
+				errs = append(errs, newFileError(cpuFile, err))
+			}
+		}
+		if memFile != "" {
+			errs = append(errs, writeHeapProfile(memFile))
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+// Write a heap profile of the live objects after a garbage collection
+func writeHeapProfile(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return newFileError(filename, err)
+	}
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		file.Close()
+		return newFileError(filename, err)
+	}
+	if err := file.Close(); err != nil {
+		return newFileError(filename, err)
+	}
+	return nil
+}
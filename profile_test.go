@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStartProfilingWritesProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		cpu, mem bool
+	}{
+		{name: "cpu", cpu: true},
+		{name: "memory", mem: true},
+		{name: "both", cpu: true, mem: true},
+		{name: "neither"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var cpuFile, memFile string
+			var want []string
+			if tt.cpu {
+				cpuFile = filepath.Join(dir, "cpu.pprof")
+				want = append(want, cpuFile)
+			}
+			if tt.mem {
+				memFile = filepath.Join(dir, "mem.pprof")
+				want = append(want, memFile)
+			}
+			stop, err := startProfiling(cpuFile, memFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			findBestOdds(sharedFixtureBookmakers(20, 200, 1))
+			if err := stop(); err != nil {
+				t.Fatal(err)
+			}
+			for _, filename := range want {
+				if info, err := os.Stat(filename); err != nil || info.Size() == 0 {
+					t.Errorf("profile %s: %v, want a non-empty file", filepath.Base(filename), err)
+				}
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != len(want) {
+				t.Errorf("wrote %d files, want %d", len(entries), len(want))
+			}
+		})
+	}
+
+	if _, err := startProfiling(filepath.Join(t.TempDir(), "missing", "cpu.pprof"), ""); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("unwritable CPU profile error = %v, want %v", err, ErrFileNotFound)
+	}
+}
+
+func TestRunWritesProfiles(t *testing.T) {
+	dir := t.TempDir()
+	cpuFile, memFile := filepath.Join(dir, "cpu.pprof"), filepath.Join(dir, "mem.pprof")
+	code, _, stderr := runCLI(t, "-cpuprofile", cpuFile, "-memprofile", memFile, "-file", writeTestData(t, plantedBookmakers()))
+	if code != exitOpportunities {
+		t.Errorf("exit code = %d, want %d: %s", code, exitOpportunities, stderr)
+	}
+	for _, filename := range []string{cpuFile, memFile} {
+		if info, err := os.Stat(filename); err != nil || info.Size() == 0 {
+			t.Errorf("profile %s: %v, want a non-empty file", filepath.Base(filename), err)
+		}
+	}
+}
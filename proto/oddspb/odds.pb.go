@@ -0,0 +1,538 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: proto/odds.proto
+
+package oddspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Decimal odds for each outcome of a fixture. Draw is 0 for markets without
+// a draw.
+type Odds struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Win  float64 `protobuf:"fixed64,1,opt,name=win,proto3" json:"win,omitempty"`
+	Draw float64 `protobuf:"fixed64,2,opt,name=draw,proto3" json:"draw,omitempty"`
+	Lose float64 `protobuf:"fixed64,3,opt,name=lose,proto3" json:"lose,omitempty"`
+}
+
+func (x *Odds) Reset() {
+	*x = Odds{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_odds_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Odds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Odds) ProtoMessage() {}
+
+func (x *Odds) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_odds_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Odds.ProtoReflect.Descriptor instead.
+func (*Odds) Descriptor() ([]byte, []int) {
+	return file_proto_odds_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Odds) GetWin() float64 {
+	if x != nil {
+		return x.Win
+	}
+	return 0
+}
+
+func (x *Odds) GetDraw() float64 {
+	if x != nil {
+		return x.Draw
+	}
+	return 0
+}
+
+func (x *Odds) GetLose() float64 {
+	if x != nil {
+		return x.Lose
+	}
+	return 0
+}
+
+type Game struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TeamA   string `protobuf:"bytes,2,opt,name=team_a,json=teamA,proto3" json:"team_a,omitempty"`
+	TeamB   string `protobuf:"bytes,3,opt,name=team_b,json=teamB,proto3" json:"team_b,omitempty"`
+	Odds    *Odds  `protobuf:"bytes,4,opt,name=odds,proto3" json:"odds,omitempty"`
+	EventAt string `protobuf:"bytes,5,opt,name=event_at,json=eventAt,proto3" json:"event_at,omitempty"`
+	Sport   string `protobuf:"bytes,6,opt,name=sport,proto3" json:"sport,omitempty"`
+	// Set when the bookmaker has suspended the market
+	Suspended bool   `protobuf:"varint,7,opt,name=suspended,proto3" json:"suspended,omitempty"`
+	League    string `protobuf:"bytes,8,opt,name=league,proto3" json:"league,omitempty"`
+	// Draw no bet market odds, with win and lose holding the home and away
+	// sides, when the bookmaker offers one
+	DrawNoBet *Odds `protobuf:"bytes,9,opt,name=draw_no_bet,json=drawNoBet,proto3" json:"draw_no_bet,omitempty"`
+	// Free-form metadata about the fixture from external sources, such as team
+	// news
+	Metadata map[string]string `protobuf:"bytes,10,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *Game) Reset() {
+	*x = Game{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_odds_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Game) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Game) ProtoMessage() {}
+
+func (x *Game) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_odds_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Game.ProtoReflect.Descriptor instead.
+func (*Game) Descriptor() ([]byte, []int) {
+	return file_proto_odds_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Game) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Game) GetTeamA() string {
+	if x != nil {
+		return x.TeamA
+	}
+	return ""
+}
+
+func (x *Game) GetTeamB() string {
+	if x != nil {
+		return x.TeamB
+	}
+	return ""
+}
+
+func (x *Game) GetOdds() *Odds {
+	if x != nil {
+		return x.Odds
+	}
+	return nil
+}
+
+func (x *Game) GetEventAt() string {
+	if x != nil {
+		return x.EventAt
+	}
+	return ""
+}
+
+func (x *Game) GetSport() string {
+	if x != nil {
+		return x.Sport
+	}
+	return ""
+}
+
+func (x *Game) GetSuspended() bool {
+	if x != nil {
+		return x.Suspended
+	}
+	return false
+}
+
+func (x *Game) GetLeague() string {
+	if x != nil {
+		return x.League
+	}
+	return ""
+}
+
+func (x *Game) GetDrawNoBet() *Odds {
+	if x != nil {
+		return x.DrawNoBet
+	}
+	return nil
+}
+
+func (x *Game) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type Bookmaker struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string  `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Games       []*Game `protobuf:"bytes,2,rep,name=games,proto3" json:"games,omitempty"`
+	Reliability float64 `protobuf:"fixed64,3,opt,name=reliability,proto3" json:"reliability,omitempty"`
+	// Builds a link to a game at the bookmaker from the {book} and {id}
+	// placeholders
+	LinkTemplate string `protobuf:"bytes,4,opt,name=link_template,json=linkTemplate,proto3" json:"link_template,omitempty"`
+	// Currency the bookmaker quotes stakes in, empty for the base currency
+	Currency string `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Largest total payout accepted on a single bet, 0 for no limit
+	MaxPayout float64 `protobuf:"fixed64,6,opt,name=max_payout,json=maxPayout,proto3" json:"max_payout,omitempty"`
+	// Largest stake accepted on a single bet keyed by sport, with "default"
+	// covering sports not listed
+	MaxStakes map[string]float64 `protobuf:"bytes,7,rep,name=max_stakes,json=maxStakes,proto3" json:"max_stakes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"fixed64,2,opt,name=value,proto3"`
+	// Smallest stake accepted on a single bet, 0 for none
+	MinStake float64 `protobuf:"fixed64,8,opt,name=min_stake,json=minStake,proto3" json:"min_stake,omitempty"`
+}
+
+func (x *Bookmaker) Reset() {
+	*x = Bookmaker{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_odds_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Bookmaker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bookmaker) ProtoMessage() {}
+
+func (x *Bookmaker) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_odds_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bookmaker.ProtoReflect.Descriptor instead.
+func (*Bookmaker) Descriptor() ([]byte, []int) {
+	return file_proto_odds_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Bookmaker) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Bookmaker) GetGames() []*Game {
+	if x != nil {
+		return x.Games
+	}
+	return nil
+}
+
+func (x *Bookmaker) GetReliability() float64 {
+	if x != nil {
+		return x.Reliability
+	}
+	return 0
+}
+
+func (x *Bookmaker) GetLinkTemplate() string {
+	if x != nil {
+		return x.LinkTemplate
+	}
+	return ""
+}
+
+func (x *Bookmaker) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Bookmaker) GetMaxPayout() float64 {
+	if x != nil {
+		return x.MaxPayout
+	}
+	return 0
+}
+
+func (x *Bookmaker) GetMaxStakes() map[string]float64 {
+	if x != nil {
+		return x.MaxStakes
+	}
+	return nil
+}
+
+func (x *Bookmaker) GetMinStake() float64 {
+	if x != nil {
+		return x.MinStake
+	}
+	return 0
+}
+
+type StreamBookmakersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StreamBookmakersRequest) Reset() {
+	*x = StreamBookmakersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_odds_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamBookmakersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamBookmakersRequest) ProtoMessage() {}
+
+func (x *StreamBookmakersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_odds_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamBookmakersRequest.ProtoReflect.Descriptor instead.
+func (*StreamBookmakersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_odds_proto_rawDescGZIP(), []int{3}
+}
+
+var File_proto_odds_proto protoreflect.FileDescriptor
+
+var file_proto_odds_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6f, 0x64, 0x64, 0x73, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x07, 0x6f, 0x64, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x22, 0x40, 0x0a, 0x04, 0x4f,
+	0x64, 0x64, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x77, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x03, 0x77, 0x69, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x72, 0x61, 0x77, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x04, 0x64, 0x72, 0x61, 0x77, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x73,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6c, 0x6f, 0x73, 0x65, 0x22, 0xf3, 0x02,
+	0x0a, 0x04, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x15, 0x0a, 0x06, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x61,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x65, 0x61, 0x6d, 0x41, 0x12, 0x15, 0x0a,
+	0x06, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74,
+	0x65, 0x61, 0x6d, 0x42, 0x12, 0x21, 0x0a, 0x04, 0x6f, 0x64, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6f, 0x64, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x64, 0x64,
+	0x73, 0x52, 0x04, 0x6f, 0x64, 0x64, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x41, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x75, 0x73, 0x70,
+	0x65, 0x6e, 0x64, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73, 0x75, 0x73,
+	0x70, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x65, 0x61, 0x67, 0x75, 0x65, 0x12, 0x2d,
+	0x0a, 0x0b, 0x64, 0x72, 0x61, 0x77, 0x5f, 0x6e, 0x6f, 0x5f, 0x62, 0x65, 0x74, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6f, 0x64, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x64,
+	0x64, 0x73, 0x52, 0x09, 0x64, 0x72, 0x61, 0x77, 0x4e, 0x6f, 0x42, 0x65, 0x74, 0x12, 0x37, 0x0a,
+	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x6f, 0x64, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x2e, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x6d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x3b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0xe3, 0x02, 0x0a, 0x09, 0x42, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65,
+	0x72, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x05, 0x67, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6f, 0x64, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x47,
+	0x61, 0x6d, 0x65, 0x52, 0x05, 0x67, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65,
+	0x6c, 0x69, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0b, 0x72, 0x65, 0x6c, 0x69, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x23, 0x0a, 0x0d,
+	0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x6c, 0x69, 0x6e, 0x6b, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1d, 0x0a,
+	0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x40, 0x0a, 0x0a,
+	0x6d, 0x61, 0x78, 0x5f, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x21, 0x2e, 0x6f, 0x64, 0x64, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x6d,
+	0x61, 0x6b, 0x65, 0x72, 0x2e, 0x4d, 0x61, 0x78, 0x53, 0x74, 0x61, 0x6b, 0x65, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x53, 0x74, 0x61, 0x6b, 0x65, 0x73, 0x12, 0x1b,
+	0x0a, 0x09, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x6b, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x53, 0x74, 0x61, 0x6b, 0x65, 0x1a, 0x3c, 0x0a, 0x0e, 0x4d,
+	0x61, 0x78, 0x53, 0x74, 0x61, 0x6b, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x19, 0x0a, 0x17, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x42, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x32, 0x59, 0x0a, 0x0b, 0x4f, 0x64, 0x64, 0x73, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x12, 0x4a, 0x0a, 0x10, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x42, 0x6f, 0x6f,
+	0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x20, 0x2e, 0x6f, 0x64, 0x64, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x42, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65,
+	0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x6f, 0x64, 0x64, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x42, 0x6f, 0x6f, 0x6b, 0x6d, 0x61, 0x6b, 0x65, 0x72, 0x30, 0x01, 0x42,
+	0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x76,
+	0x65, 0x6c, 0x65, 0x2f, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x2d, 0x62, 0x65, 0x74, 0x74, 0x69,
+	0x6e, 0x67, 0x2d, 0x61, 0x72, 0x62, 0x69, 0x74, 0x72, 0x61, 0x67, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2f, 0x6f, 0x64, 0x64, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_proto_odds_proto_rawDescOnce sync.Once
+	file_proto_odds_proto_rawDescData = file_proto_odds_proto_rawDesc
+)
+
+func file_proto_odds_proto_rawDescGZIP() []byte {
+	file_proto_odds_proto_rawDescOnce.Do(func() {
+		file_proto_odds_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_odds_proto_rawDescData)
+	})
+	return file_proto_odds_proto_rawDescData
+}
+
+var file_proto_odds_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_odds_proto_goTypes = []interface{}{
+	(*Odds)(nil),                    // 0: odds.v1.Odds
+	(*Game)(nil),                    // 1: odds.v1.Game
+	(*Bookmaker)(nil),               // 2: odds.v1.Bookmaker
+	(*StreamBookmakersRequest)(nil), // 3: odds.v1.StreamBookmakersRequest
+	nil,                             // 4: odds.v1.Game.MetadataEntry
+	nil,                             // 5: odds.v1.Bookmaker.MaxStakesEntry
+}
+var file_proto_odds_proto_depIdxs = []int32{
+	0, // 0: odds.v1.Game.odds:type_name -> odds.v1.Odds
+	0, // 1: odds.v1.Game.draw_no_bet:type_name -> odds.v1.Odds
+	4, // 2: odds.v1.Game.metadata:type_name -> odds.v1.Game.MetadataEntry
+	1, // 3: odds.v1.Bookmaker.games:type_name -> odds.v1.Game
+	5, // 4: odds.v1.Bookmaker.max_stakes:type_name -> odds.v1.Bookmaker.MaxStakesEntry
+	3, // 5: odds.v1.OddsService.StreamBookmakers:input_type -> odds.v1.StreamBookmakersRequest
+	2, // 6: odds.v1.OddsService.StreamBookmakers:output_type -> odds.v1.Bookmaker
+	6, // [6:7] is the sub-list for method output_type
+	5, // [5:6] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_proto_odds_proto_init() }
+func file_proto_odds_proto_init() {
+	if File_proto_odds_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_odds_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Odds); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_odds_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Game); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_odds_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Bookmaker); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_odds_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamBookmakersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_odds_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_odds_proto_goTypes,
+		DependencyIndexes: file_proto_odds_proto_depIdxs,
+		MessageInfos:      file_proto_odds_proto_msgTypes,
+	}.Build()
+	File_proto_odds_proto = out.File
+	file_proto_odds_proto_rawDesc = nil
+	file_proto_odds_proto_goTypes = nil
+	file_proto_odds_proto_depIdxs = nil
+}
@@ -0,0 +1,139 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: proto/odds.proto
+
+package oddspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	OddsService_StreamBookmakers_FullMethodName = "/odds.v1.OddsService/StreamBookmakers"
+)
+
+// OddsServiceClient is the client API for OddsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OddsServiceClient interface {
+	// Stream every bookmaker's current odds, one bookmaker per message
+	StreamBookmakers(ctx context.Context, in *StreamBookmakersRequest, opts ...grpc.CallOption) (OddsService_StreamBookmakersClient, error)
+}
+
+type oddsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOddsServiceClient(cc grpc.ClientConnInterface) OddsServiceClient {
+	return &oddsServiceClient{cc}
+}
+
+func (c *oddsServiceClient) StreamBookmakers(ctx context.Context, in *StreamBookmakersRequest, opts ...grpc.CallOption) (OddsService_StreamBookmakersClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OddsService_ServiceDesc.Streams[0], OddsService_StreamBookmakers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &oddsServiceStreamBookmakersClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OddsService_StreamBookmakersClient interface {
+	Recv() (*Bookmaker, error)
+	grpc.ClientStream
+}
+
+type oddsServiceStreamBookmakersClient struct {
+	grpc.ClientStream
+}
+
+func (x *oddsServiceStreamBookmakersClient) Recv() (*Bookmaker, error) {
+	m := new(Bookmaker)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// OddsServiceServer is the server API for OddsService service.
+// All implementations must embed UnimplementedOddsServiceServer
+// for forward compatibility
+type OddsServiceServer interface {
+	// Stream every bookmaker's current odds, one bookmaker per message
+	StreamBookmakers(*StreamBookmakersRequest, OddsService_StreamBookmakersServer) error
+	mustEmbedUnimplementedOddsServiceServer()
+}
+
+// UnimplementedOddsServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedOddsServiceServer struct {
+}
+
+func (UnimplementedOddsServiceServer) StreamBookmakers(*StreamBookmakersRequest, OddsService_StreamBookmakersServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBookmakers not implemented")
+}
+func (UnimplementedOddsServiceServer) mustEmbedUnimplementedOddsServiceServer() {}
+
+// UnsafeOddsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OddsServiceServer will
+// result in compilation errors.
+type UnsafeOddsServiceServer interface {
+	mustEmbedUnimplementedOddsServiceServer()
+}
+
+func RegisterOddsServiceServer(s grpc.ServiceRegistrar, srv OddsServiceServer) {
+	s.RegisterService(&OddsService_ServiceDesc, srv)
+}
+
+func _OddsService_StreamBookmakers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamBookmakersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OddsServiceServer).StreamBookmakers(m, &oddsServiceStreamBookmakersServer{ServerStream: stream})
+}
+
+type OddsService_StreamBookmakersServer interface {
+	Send(*Bookmaker) error
+	grpc.ServerStream
+}
+
+type oddsServiceStreamBookmakersServer struct {
+	grpc.ServerStream
+}
+
+func (x *oddsServiceStreamBookmakersServer) Send(m *Bookmaker) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// OddsService_ServiceDesc is the grpc.ServiceDesc for OddsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OddsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "odds.v1.OddsService",
+	HandlerType: (*OddsServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBookmakers",
+			Handler:       _OddsService_StreamBookmakers_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/odds.proto",
+}
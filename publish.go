@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Define a destination opportunities are published to instead of stdout,
+// such as a message queue feeding a trading pipeline
+type Publisher interface {
+	Publish(opportunity ArbitrageOpportunity) error
+	Close() error
+}
+
+// Publish opportunities as JSON messages on a NATS subject
+type natsPublisher struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// Connect to a NATS server, e.g. nats://localhost:4222, to publish on subject
+func newNATSPublisher(url, subject string) (*natsPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("sports-betting-arbitrage"), nats.Timeout(5*time.Second))
+	if err != nil {
+		return nil, err
+	}
+	return &natsPublisher{conn: conn, subject: subject}, nil
+}
+
+// Publish an opportunity as a JSON message
+func (p *natsPublisher) Publish(opportunity ArbitrageOpportunity) error {
+	data, err := json.Marshal(opportunity)
+	if err != nil {
+		return err
+	}
+	return p.conn.Publish(p.subject, data)
+}
+
+// Flush buffered messages to the server and close the connection
+func (p *natsPublisher) Close() error {
+	err := p.conn.FlushTimeout(5 * time.Second)
+	p.conn.Close()
+	return err
+}
+
+// Publish each opportunity, warning about those that fail instead of
+// stopping, and return how many were published
+func publishOpportunities(publisher Publisher, opportunities []ArbitrageOpportunity) int {
+	published := 0
+	for _, opportunity := range opportunities {
+		if err := publisher.Publish(opportunity); err != nil {
+			warn("game %s: publishing opportunity: %v", opportunity.GameID, err)
+			continue
+		}
+		published++
+	}
+	return published
+}
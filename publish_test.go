@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Define a publisher recording what it is given, failing for listed games
+type recordingPublisher struct {
+	failGames map[string]bool
+	published []string
+}
+
+// Record an opportunity unless its game is set to fail
+func (p *recordingPublisher) Publish(opportunity ArbitrageOpportunity) error {
+	if p.failGames[opportunity.GameID] {
+		return errors.New("connection lost")
+	}
+	p.published = append(p.published, opportunity.GameID)
+	return nil
+}
+
+// Close the publisher, which holds nothing open
+func (p *recordingPublisher) Close() error { return nil }
+
+func TestPublishOpportunitiesSkipsFailures(t *testing.T) {
+	publisher := &recordingPublisher{failGames: map[string]bool{"g2": true}}
+	opportunities := []ArbitrageOpportunity{{GameID: "g1"}, {GameID: "g2"}, {GameID: "g3"}}
+	var published int
+	warning := captureStderr(t, func() { published = publishOpportunities(publisher, opportunities) })
+	if published != 2 || strings.Join(publisher.published, ",") != "g1,g3" {
+		t.Errorf("published %d: %v, want g1 and g3", published, publisher.published)
+	}
+	if want := "Warning: game g2: publishing opportunity: connection lost\n"; warning != want {
+		t.Errorf("warning = %q, want %q", warning, want)
+	}
+}
+
+// Message published to the fake NATS server
+type natsMessage struct {
+	subject string
+	data    []byte
+}
+
+// Serve just enough of the NATS protocol on a local port for a client to
+// connect, publish and flush, returning its URL and the messages received
+func serveFakeNATS(t *testing.T) (string, <-chan natsMessage) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := make(chan natsMessage, 16)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		fmt.Fprint(conn, "INFO {\"server_id\":\"fake\",\"version\":\"2.10.0\",\"max_payload\":1048576}\r\n")
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			switch {
+			case len(fields) == 0:
+			case fields[0] == "PING":
+				fmt.Fprint(conn, "PONG\r\n")
+			case fields[0] == "PUB" && len(fields) == 3:
+				var size int
+				fmt.Sscan(fields[2], &size)
+				data := make([]byte, size+2)
+				if _, err := io.ReadFull(reader, data); err != nil {
+					return
+				}
+				messages <- natsMessage{subject: fields[1], data: data[:size]}
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		listener.Close()
+		wg.Wait()
+	})
+	return "nats://" + listener.Addr().String(), messages
+}
+
+func TestNATSPublisher(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	url, messages := serveFakeNATS(t)
+	publisher, err := newNATSPublisher(url, "arbitrage.opportunities")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opportunity := newArbitrageOpportunity("planted", Odds{Win: 3, Draw: 4, Lose: 4}, 100)
+	if err := publisher.Publish(opportunity); err != nil {
+		t.Fatal(err)
+	}
+	if err := publisher.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	message := <-messages
+	if message.subject != "arbitrage.opportunities" {
+		t.Errorf("subject = %q, want arbitrage.opportunities", message.subject)
+	}
+	var got ArbitrageOpportunity
+	if err := json.Unmarshal(message.data, &got); err != nil {
+		t.Fatalf("decoding %s: %v", message.data, err)
+	}
+	if got.GameID != "planted" || got.GuaranteedProfit != opportunity.GuaranteedProfit {
+		t.Errorf("published %+v, want %+v", got, opportunity)
+	}
+}
+
+func TestRunPublishFallsBackToStdout(t *testing.T) {
+	// Nothing listens on the closed listener's port
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "nats://" + listener.Addr().String()
+	listener.Close()
+
+	code, stdout, stderr := runCLI(t, "-publish", url, "-file", writeTestData(t, plantedBookmakers()))
+	if code != exitOpportunities {
+		t.Errorf("exit code = %d, want %d", code, exitOpportunities)
+	}
+	if !strings.HasPrefix(stdout, "Arbitrage opportunity found for game planted\n") {
+		t.Errorf("stdout = %q, want the report", stdout)
+	}
+	if !strings.Contains(stderr, "writing opportunities to stdout instead") {
+		t.Errorf("stderr = %q, want the connection warning", stderr)
+	}
+}
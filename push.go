@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// Define the push-adjusted position on one fixture. A push settles a leg by
+// returning its stake instead of paying out, as integer handicap and totals
+// lines do when the result lands exactly on the line.
+type PushPosition struct {
+	Opportunity ArbitrageOpportunity `json:"opportunity"`
+	Push        Odds                 `json:"push_probability"`
+	Effective   Odds                 `json:"effective_odds"`
+	// Expected profit over pushes, equal whichever outcome happens
+	ExpectedProfit float64 `json:"expected_profit"`
+	// Result of the worst settlement, a pushed leg when any can push
+	WorstCase        float64 `json:"worst_case"`
+	WorstCaseOutcome string  `json:"worst_case_outcome"`
+}
+
+// Calculate the odds a leg is worth on average when it pushes with
+// probability push given its outcome happens, returning 1 per unit staked,
+// and pays its odds otherwise
+func pushAdjustedOdds(odds, push float64) float64 {
+	return push + (1-push)*odds
+}
+
+// Size an opportunity's legs for pushes. Push probabilities are per outcome
+// and conditional on that outcome happening. The settlement assumption is
+// that a push only refunds the leg that pushed: the legs cover mutually
+// exclusive outcomes, so every other leg loses, as when home -1 pushes on a
+// one goal home win while away +0.5 loses. Each leg is staked to pay the
+// same on average at its push-adjusted odds, so the expected profit is the
+// same whichever outcome happens, but a push still loses everything staked
+// on the other legs. With no push probabilities this equals calculateStakes.
+func pushStakes(opportunity ArbitrageOpportunity, push Odds, totalBet float64) (PushPosition, error) {
+	for _, probability := range []float64{push.Win, push.Draw, push.Lose} {
+		if !(probability >= 0 && probability < 1) {
+			return PushPosition{}, fmt.Errorf("%w: push probability %v outside [0, 1)", ErrInvalidOdds, probability)
+		}
+	}
+	odds := opportunity.Odds
+	effective := Odds{
+		Win:  pushAdjustedOdds(odds.Win, push.Win),
+		Lose: pushAdjustedOdds(odds.Lose, push.Lose),
+	}
+	if odds.Draw != 0 {
+		effective.Draw = pushAdjustedOdds(odds.Draw, push.Draw)
+	} else {
+		push.Draw = 0
+	}
+	stakes := calculateStakes(effective, totalBet)
+	opportunity.WinStake, opportunity.DrawStake, opportunity.LoseStake = stakes.Win, stakes.Draw, stakes.Lose
+	opportunity.TotalBet = totalBet
+	opportunity.ArbitragePercentage = calculateArbitragePercentage(effective)
+	opportunity.GuaranteedProfit = 0
+
+	position := PushPosition{
+		Opportunity:    opportunity,
+		Push:           push,
+		Effective:      effective,
+		ExpectedProfit: totalBet/opportunity.ArbitragePercentage - totalBet,
+		WorstCase:      math.Inf(1),
+	}
+	pushes := map[string]float64{OutcomeWin: push.Win, OutcomeDraw: push.Draw, OutcomeLose: push.Lose}
+	for _, leg := range opportunityLegs(opportunity) {
+		result, outcome := leg.Stake*leg.Odds-totalBet, leg.Outcome
+		if pushes[leg.Outcome] > 0 {
+			result, outcome = leg.Stake-totalBet, leg.Outcome+" push"
+		}
+		if result < position.WorstCase {
+			position.WorstCase, position.WorstCaseOutcome = result, outcome
+		}
+	}
+	return position, nil
+}
+
+// Print a push-adjusted position: each leg's odds, push probability,
+// adjusted odds and stake, then the expected and worst case results
+func printPushPosition(w io.Writer, position PushPosition, out outputOptions) {
+	labels := outcomesForSport(position.Opportunity.Sport)
+	pushes := map[string]float64{OutcomeWin: position.Push.Win, OutcomeDraw: position.Push.Draw, OutcomeLose: position.Push.Lose}
+	fmt.Fprintln(w, "Push-adjusted stakes (a push refunds only that leg; the other legs lose):")
+	for _, leg := range opportunityLegs(position.Opportunity) {
+		fmt.Fprintf(w, "%s: %.*f at %s, push %.1f%%, adjusted odds %.*f, stake %.*f\n", outcomeLabel(labels, leg.Outcome),
+			out.OddsPrecision, leg.Odds, leg.Bookmaker, pushes[leg.Outcome]*100,
+			out.OddsPrecision, pushAdjustedOdds(leg.Odds, pushes[leg.Outcome]), out.Precision, leg.Stake)
+	}
+	fmt.Fprintf(w, "Expected profit: %.*f\n", out.Precision, position.ExpectedProfit)
+	fmt.Fprintf(w, "Worst case (%s): %.*f\n", position.WorstCaseOutcome, out.Precision, position.WorstCase)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPushStakes(t *testing.T) {
+	opportunity := newArbitrageOpportunity("g1", Odds{Win: 2.2, Lose: 2.2}, 100)
+	tests := []struct {
+		name          string
+		push          Odds
+		wantEffective Odds
+		wantWorst     string
+	}{
+		{name: "no pushes", wantEffective: Odds{Win: 2.2, Lose: 2.2}, wantWorst: OutcomeWin},
+		// A tenth of home wins land on the line and refund the home leg
+		{name: "pushable home leg", push: Odds{Win: 0.1}, wantEffective: Odds{Win: 2.08, Lose: 2.2}, wantWorst: OutcomeWin + " push"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position, err := pushStakes(opportunity, tt.push, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !oddsClose(position.Effective, tt.wantEffective) {
+				t.Errorf("effective odds = %+v, want %+v", position.Effective, tt.wantEffective)
+			}
+			got := position.Opportunity
+			if total := got.WinStake + got.DrawStake + got.LoseStake; math.Abs(total-100) > 1e-9 {
+				t.Errorf("stakes total %v, want 100", total)
+			}
+			// Each leg pays the same on average at its push-adjusted odds
+			winReturn, loseReturn := got.WinStake*tt.wantEffective.Win, got.LoseStake*tt.wantEffective.Lose
+			if math.Abs(winReturn-loseReturn) > 1e-9 || math.Abs(position.ExpectedProfit-(winReturn-100)) > 1e-9 {
+				t.Errorf("average returns %v and %v with expected profit %v", winReturn, loseReturn, position.ExpectedProfit)
+			}
+			if position.WorstCaseOutcome != tt.wantWorst {
+				t.Errorf("worst case outcome = %q, want %q", position.WorstCaseOutcome, tt.wantWorst)
+			}
+		})
+	}
+
+	// Without pushes the stakes are the plain arbitrage stakes
+	plain, _ := pushStakes(opportunity, Odds{}, 100)
+	if plain.Opportunity.WinStake != opportunity.WinStake || math.Abs(plain.ExpectedProfit-opportunity.GuaranteedProfit) > 1e-9 {
+		t.Errorf("without pushes got %+v, want %+v", plain.Opportunity, opportunity)
+	}
+	// A push refunds only the home stake and the away leg loses
+	pushed, _ := pushStakes(opportunity, Odds{Win: 0.1}, 100)
+	if want := pushed.Opportunity.WinStake - 100; math.Abs(pushed.WorstCase-want) > 1e-9 || pushed.WorstCase >= 0 {
+		t.Errorf("worst case = %v, want %v", pushed.WorstCase, want)
+	}
+
+	for _, push := range []Odds{{Win: -0.1}, {Lose: 1}} {
+		if _, err := pushStakes(opportunity, push, 100); !errors.Is(err, ErrInvalidOdds) {
+			t.Errorf("push %+v error = %v, want %v", push, err, ErrInvalidOdds)
+		}
+	}
+}
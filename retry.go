@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"time"
+)
+
+// Define how file operations are retried after transient errors, such as
+// those from networked filesystems. Each retry waits twice as long as the
+// previous one, starting from BaseDelay. The zero value tries once.
+type retryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+}
+
+// Run op until it succeeds, fails with an error that is not transient or
+// runs out of attempts, returning its last error
+func (p retryPolicy) do(op func() error) error {
+	delay := p.BaseDelay
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= p.MaxAttempts || !isTransientFileError(err) {
+			return err
+		}
+		warn("%v, retrying in %v", err, delay)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// Report whether a file error may go away if the operation is repeated.
+// Errors from the filesystem are retried unless the file is missing, already
+// exists or may not be accessed; errors in the data itself never are.
+func isTransientFileError(err error) bool {
+	var pathErr *fs.PathError
+	if !errors.As(err, &pathErr) {
+		return false
+	}
+	return !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, fs.ErrExist) && !errors.Is(err, fs.ErrPermission)
+}
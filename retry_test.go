@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+	"testing"
+)
+
+// Define a writer whose first failures writes fail with err
+type flakyWriter struct {
+	failures int
+	err      error
+	writes   int
+}
+
+// Write p, failing until the configured number of failures have happened
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.writes <= w.failures {
+		return 0, w.err
+	}
+	return len(p), nil
+}
+
+func TestRetryPolicy(t *testing.T) {
+	ioErr := newFileError("odds.json", &fs.PathError{Op: "write", Path: "odds.json", Err: syscall.EIO})
+	missing := newFileError("odds.json", &fs.PathError{Op: "open", Path: "odds.json", Err: fs.ErrNotExist})
+	tests := []struct {
+		name       string
+		policy     retryPolicy
+		writer     *flakyWriter
+		wantWrites int
+		wantErr    bool
+	}{
+		{name: "fails twice then succeeds", policy: retryPolicy{MaxAttempts: 3}, writer: &flakyWriter{failures: 2, err: ioErr}, wantWrites: 3},
+		{name: "runs out of attempts", policy: retryPolicy{MaxAttempts: 2}, writer: &flakyWriter{failures: 2, err: ioErr}, wantWrites: 2, wantErr: true},
+		{name: "zero value tries once", writer: &flakyWriter{failures: 2, err: ioErr}, wantWrites: 1, wantErr: true},
+		{name: "missing file is not retried", policy: retryPolicy{MaxAttempts: 3}, writer: &flakyWriter{failures: 2, err: missing}, wantWrites: 1, wantErr: true},
+		{name: "data error is not retried", policy: retryPolicy{MaxAttempts: 3}, writer: &flakyWriter{failures: 2, err: ErrParse}, wantWrites: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.do(func() error {
+				_, err := tt.writer.Write([]byte("[]"))
+				return err
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("do() = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.writer.writes != tt.wantWrites {
+				t.Errorf("writes = %d, want %d", tt.writer.writes, tt.wantWrites)
+			}
+		})
+	}
+}
+
+func TestIsTransientFileError(t *testing.T) {
+	pathErr := func(err error) error { return &fs.PathError{Op: "read", Path: "odds.json", Err: err} }
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "I/O error", err: pathErr(syscall.EIO), want: true},
+		{name: "wrapped I/O error", err: newFileError("odds.json", pathErr(syscall.EIO)), want: true},
+		{name: "not exist", err: pathErr(fs.ErrNotExist)},
+		{name: "exists", err: pathErr(fs.ErrExist)},
+		{name: "permission", err: pathErr(fs.ErrPermission)},
+		{name: "not a file error", err: errors.New("unexpected end of JSON input")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientFileError(tt.err); got != tt.want {
+				t.Errorf("isTransientFileError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
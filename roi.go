@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Calculate the guaranteed profit of an opportunity as a fraction of the
+// capital it ties up, zero when no capital is staked
+func returnOnCapital(opportunity ArbitrageOpportunity, totalBet float64) float64 {
+	if !(totalBet > 0) {
+		return 0
+	}
+	return opportunity.GuaranteedProfit / totalBet
+}
+
+// Define the order opportunities are reported in
+type SortKey int
+
+const (
+	// SortByGame orders opportunities by game ID, then bookmaker
+	SortByGame SortKey = iota
+	// SortByProfit puts the largest guaranteed profit first
+	SortByProfit
+	// SortByROI puts the largest return on capital first, which suits a
+	// limited bankroll
+	SortByROI
+)
+
+// Return the name of a sort key
+func (k SortKey) String() string {
+	switch k {
+	case SortByGame:
+		return "game"
+	case SortByProfit:
+		return "profit"
+	case SortByROI:
+		return "roi"
+	}
+	return fmt.Sprintf("SortKey(%d)", int(k))
+}
+
+// Parse a sort key name
+func parseSortKey(name string) (SortKey, error) {
+	for _, key := range []SortKey{SortByGame, SortByProfit, SortByROI} {
+		if key.String() == name {
+			return key, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown sort %q, expected game, profit or roi", name)
+}
+
+// Sort opportunities by a key. Ties keep game ID order so output is stable.
+func sortOpportunities(opportunities []ArbitrageOpportunity, key SortKey) {
+	sortOpportunitiesByGame(opportunities)
+	switch key {
+	case SortByProfit:
+		sort.SliceStable(opportunities, func(i, j int) bool {
+			return opportunities[i].GuaranteedProfit > opportunities[j].GuaranteedProfit
+		})
+	case SortByROI:
+		sort.SliceStable(opportunities, func(i, j int) bool {
+			return opportunities[i].ReturnOnCapital > opportunities[j].ReturnOnCapital
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReturnOnCapital(t *testing.T) {
+	tests := []struct {
+		profit, totalBet float64
+		want             float64
+	}{
+		{profit: 5, totalBet: 100, want: 0.05},
+		{profit: 5, totalBet: 50, want: 0.1},
+		{profit: 5, totalBet: 0},
+		{profit: 5, totalBet: -10},
+	}
+	for _, tt := range tests {
+		if got := returnOnCapital(ArbitrageOpportunity{GuaranteedProfit: tt.profit}, tt.totalBet); got != tt.want {
+			t.Errorf("returnOnCapital(%v on %v) = %v, want %v", tt.profit, tt.totalBet, got, tt.want)
+		}
+	}
+}
+
+func TestSortOpportunities(t *testing.T) {
+	// The largest profit ties up the most capital, so ROI ranks it last
+	opportunities := func() []ArbitrageOpportunity {
+		return []ArbitrageOpportunity{
+			{GameID: "c", GuaranteedProfit: 2, ReturnOnCapital: 0.04},
+			{GameID: "a", GuaranteedProfit: 10, ReturnOnCapital: 0.02},
+			{GameID: "d", GuaranteedProfit: 2, ReturnOnCapital: 0.04},
+			{GameID: "b", GuaranteedProfit: 3, ReturnOnCapital: 0.06},
+		}
+	}
+	tests := []struct {
+		key  SortKey
+		want []string
+	}{
+		{key: SortByGame, want: []string{"a", "b", "c", "d"}},
+		{key: SortByProfit, want: []string{"a", "b", "c", "d"}},
+		{key: SortByROI, want: []string{"b", "c", "d", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key.String(), func(t *testing.T) {
+			opps := opportunities()
+			sortOpportunities(opps, tt.key)
+			if got := opportunityGameIDs(opps); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sorted %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSortKey(t *testing.T) {
+	for _, key := range []SortKey{SortByGame, SortByProfit, SortByROI} {
+		got, err := parseSortKey(key.String())
+		if err != nil || got != key {
+			t.Errorf("parseSortKey(%q) = %v, %v", key.String(), got, err)
+		}
+	}
+	if _, err := parseSortKey("odds"); err == nil {
+		t.Error("parseSortKey(\"odds\") succeeded, want an error")
+	}
+}
+
+func TestPrintReturnOnCapital(t *testing.T) {
+	opportunity := newArbitrageOpportunity("g1", Odds{Win: 3, Draw: 4, Lose: 4}, 100)
+	var buf bytes.Buffer
+	printArbitrageOpportunity(&buf, opportunity, defaultOutputOptions)
+	if !strings.Contains(buf.String(), "Return on capital: 20.00%\n") {
+		t.Errorf("output does not report the return on capital:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Define how stakes are rounded to a betting unit
+type RoundingMode int
+
+// Rounding modes and their effect on the no-loss guarantee. Rounding moves
+// each leg's payout away from the equalized amount, so the guaranteed profit
+// is recomputed as the smallest payout minus the total actually staked.
+const (
+	// RoundDown never stakes more than the planned total bet, but each payout
+	// shrinks with its stake, so a coarse unit can eat the whole margin and
+	// turn the weakest outcome into a loss.
+	RoundDown RoundingMode = iota
+	// RoundNearest keeps the total close to the planned bet; payouts move in
+	// both directions and the guarantee holds only while the rounding error
+	// stays below the arbitrage margin.
+	RoundNearest
+	// RoundUp never lowers a payout below the equalized amount, so every
+	// outcome still returns at least the planned total bet divided by the
+	// arbitrage percentage, but the extra stake can exceed the margin and
+	// exposure goes above the planned total bet.
+	RoundUp
+)
+
+// Return the name of a rounding mode
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundDown:
+		return "down"
+	case RoundNearest:
+		return "nearest"
+	case RoundUp:
+		return "up"
+	}
+	return fmt.Sprintf("RoundingMode(%d)", int(m))
+}
+
+// Parse a rounding mode name
+func parseRoundingMode(name string) (RoundingMode, error) {
+	for _, mode := range []RoundingMode{RoundDown, RoundNearest, RoundUp} {
+		if mode.String() == name {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown rounding mode %q, expected down, nearest or up", name)
+}
+
+// Round a stake to a multiple of unit
+func roundStake(stake, unit float64, mode RoundingMode) float64 {
+	switch mode {
+	case RoundDown:
+		return math.Floor(stake/unit) * unit
+	case RoundUp:
+		return math.Ceil(stake/unit) * unit
+	}
+	return math.Round(stake/unit) * unit
+}
+
+// Calculate the profit guaranteed by a set of stakes: the smallest payout
+// across outcomes minus the total staked. Zero draw odds mean there is no
+// draw outcome to pay out on.
+func guaranteedProfit(odds Odds, winStake, drawStake, loseStake float64) float64 {
+	minPayout := math.Min(winStake*odds.Win, loseStake*odds.Lose)
+	if odds.Draw != 0 {
+		minPayout = math.Min(minPayout, drawStake*odds.Draw)
+	}
+	return minPayout - (winStake + drawStake + loseStake)
+}
+
+// Round an opportunity's stakes to multiples of unit and recompute the total
+// bet and guaranteed profit for the rounded position. A leg that mode would
+// take below its bookmaker's minimum stake is rounded up instead.
+func roundStakes(opportunity ArbitrageOpportunity, unit float64, mode RoundingMode) ArbitrageOpportunity {
+	if unit <= 0 {
+		return opportunity
+	}
+	round := func(stake float64, outcome string) float64 {
+		rounded := roundStake(stake, unit, mode)
+		if rounded < opportunity.MinStakes[outcome] {
+			return roundStake(stake, unit, RoundUp)
+		}
+		return rounded
+	}
+	opportunity.WinStake = round(opportunity.WinStake, OutcomeWin)
+	opportunity.DrawStake = round(opportunity.DrawStake, OutcomeDraw)
+	opportunity.LoseStake = round(opportunity.LoseStake, OutcomeLose)
+	opportunity.TotalBet = opportunity.Stakes().Total()
+	opportunity.GuaranteedProfit = guaranteedProfit(opportunity.Odds, opportunity.WinStake, opportunity.DrawStake, opportunity.LoseStake)
+	return opportunity
+}
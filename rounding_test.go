@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRoundStake(t *testing.T) {
+	tests := []struct {
+		stake, unit float64
+		mode        RoundingMode
+		want        float64
+	}{
+		{stake: 12.4, unit: 5, mode: RoundDown, want: 10},
+		{stake: 12.4, unit: 5, mode: RoundNearest, want: 10},
+		{stake: 12.6, unit: 5, mode: RoundNearest, want: 15},
+		{stake: 12.4, unit: 5, mode: RoundUp, want: 15},
+		{stake: 15, unit: 5, mode: RoundUp, want: 15},
+		{stake: 3.14159, unit: 0.01, mode: RoundDown, want: 3.14},
+	}
+	for _, tt := range tests {
+		if got := roundStake(tt.stake, tt.unit, tt.mode); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("roundStake(%v, %v, %v) = %v, want %v", tt.stake, tt.unit, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestRoundStakesModes(t *testing.T) {
+	// Unrounded stakes are 44.38, 29.20 and 26.42
+	opportunity := newArbitrageOpportunity("g", Odds{Win: 2.5, Draw: 3.8, Lose: 4.2}, 100)
+	tests := []struct {
+		mode       RoundingMode
+		wantStakes StakeAllocation
+		wantTotal  float64
+		wantProfit float64
+	}{
+		{mode: RoundDown, wantStakes: StakeAllocation{Win: 40, Draw: 25, Lose: 25}, wantTotal: 90, wantProfit: 5},
+		{mode: RoundNearest, wantStakes: StakeAllocation{Win: 45, Draw: 30, Lose: 25}, wantTotal: 100, wantProfit: 5},
+		{mode: RoundUp, wantStakes: StakeAllocation{Win: 45, Draw: 30, Lose: 30}, wantTotal: 105, wantProfit: 7.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			got := roundStakes(opportunity, 5, tt.mode)
+			if got.Stakes() != tt.wantStakes {
+				t.Errorf("stakes = %+v, want %+v", got.Stakes(), tt.wantStakes)
+			}
+			if math.Abs(got.TotalBet-tt.wantTotal) > 1e-9 {
+				t.Errorf("total bet = %v, want %v", got.TotalBet, tt.wantTotal)
+			}
+			if math.Abs(got.GuaranteedProfit-tt.wantProfit) > 1e-9 {
+				t.Errorf("guaranteed profit = %v, want %v", got.GuaranteedProfit, tt.wantProfit)
+			}
+		})
+	}
+	if got := roundStakes(opportunity, 0, RoundUp); !reflect.DeepEqual(got, opportunity) {
+		t.Errorf("a zero unit changed the opportunity to %+v", got)
+	}
+}
+
+func TestGuaranteedProfitTwoWay(t *testing.T) {
+	if got := guaranteedProfit(Odds{Win: 2.1, Lose: 2.1}, 50, 0, 50); math.Abs(got-5) > 1e-9 {
+		t.Errorf("guaranteedProfit() = %v, want 5", got)
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	for _, mode := range []RoundingMode{RoundDown, RoundNearest, RoundUp} {
+		if got, err := parseRoundingMode(mode.String()); err != nil || got != mode {
+			t.Errorf("parseRoundingMode(%q) = %v, %v", mode.String(), got, err)
+		}
+	}
+	if _, err := parseRoundingMode("banker"); err == nil {
+		t.Error("parseRoundingMode(\"banker\") succeeded, want an error")
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// Round a float to two decimal places, to the nearest value rather than
+// down as roundToTwoDecimal does
+func roundHalfToTwoDecimal(val float64) float64 {
+	return math.Round(val*100) / 100
+}
+
+// Define the structure for a generated fixture whose arbitrage status depends
+// on how its odds were rounded
+type RoundingAuditResult struct {
+	GameID             string `json:"game_id"`
+	Raw                Odds   `json:"raw"`
+	Truncated          Odds   `json:"truncated"`
+	Rounded            Odds   `json:"rounded"`
+	TruncatedArbitrage bool   `json:"truncated_arbitrage"`
+	RoundedArbitrage   bool   `json:"rounded_arbitrage"`
+}
+
+// Compare the arbitrage status of raw odds truncated to two decimals, as
+// generation does, with the same odds rounded to the nearest two decimals,
+// reporting whether the two disagree. Truncation lowers every leg, so it can
+// only hide an arbitrage that proper rounding would show.
+func auditOddsRounding(gameID string, raw Odds) (RoundingAuditResult, bool) {
+	apply := func(round func(float64) float64) Odds {
+		return Odds{Win: round(raw.Win), Draw: round(raw.Draw), Lose: round(raw.Lose)}
+	}
+	result := RoundingAuditResult{
+		GameID:    gameID,
+		Raw:       raw,
+		Truncated: apply(roundToTwoDecimal),
+		Rounded:   apply(roundHalfToTwoDecimal),
+	}
+	result.TruncatedArbitrage = calculateArbitragePercentage(result.Truncated) < 1
+	result.RoundedArbitrage = calculateArbitragePercentage(result.Rounded) < 1
+	return result, result.TruncatedArbitrage != result.RoundedArbitrage
+}
+
+// Generate odds for numFixtures fixtures the way generation does and return
+// those whose arbitrage status differs between truncated and rounded odds
+func roundingAudit(numFixtures int, seed int64) []RoundingAuditResult {
+	rng := rand.New(rand.NewSource(seed))
+	var sensitive []RoundingAuditResult
+	for i := 0; i < numFixtures; i++ {
+		if result, differs := auditOddsRounding(fmt.Sprintf("fixture-%d", i), generateRawOdds(rng)); differs {
+			sensitive = append(sensitive, result)
+		}
+	}
+	return sensitive
+}
+
+// Print the fixtures of a rounding audit
+func printRoundingAudit(w io.Writer, sensitive []RoundingAuditResult, numFixtures int, out outputOptions) {
+	format := func(odds Odds) string {
+		return fmt.Sprintf("%.*f/%.*f/%.*f", out.OddsPrecision, odds.Win, out.OddsPrecision, odds.Draw, out.OddsPrecision, odds.Lose)
+	}
+	status := func(arbitrage bool) string {
+		if arbitrage {
+			return "arbitrage"
+		}
+		return "no arbitrage"
+	}
+	for _, result := range sensitive {
+		fmt.Fprintf(w, "%s: truncated %s is %s, rounded %s is %s\n", result.GameID,
+			format(result.Truncated), status(result.TruncatedArbitrage), format(result.Rounded), status(result.RoundedArbitrage))
+	}
+	fmt.Fprintf(w, "%d of %d generated fixtures change arbitrage status between truncated and rounded odds\n", len(sensitive), numFixtures)
+}
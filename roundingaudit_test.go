@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestAuditOddsRounding(t *testing.T) {
+	tests := []struct {
+		name                    string
+		raw                     Odds
+		wantTruncated           Odds
+		wantRounded             Odds
+		wantTruncArb, wantRound bool
+		wantDiffers             bool
+	}{
+		// Truncation to 3.00 leaves a 100% book, rounding to 3.01 an arbitrage
+		{name: "hidden by truncation", raw: Odds{Win: 3.006, Draw: 3.006, Lose: 3.006},
+			wantTruncated: Odds{Win: 3, Draw: 3, Lose: 3}, wantRounded: Odds{Win: 3.01, Draw: 3.01, Lose: 3.01},
+			wantRound: true, wantDiffers: true},
+		{name: "no arbitrage either way", raw: Odds{Win: 3.001, Draw: 3.001, Lose: 3.001},
+			wantTruncated: Odds{Win: 3, Draw: 3, Lose: 3}, wantRounded: Odds{Win: 3, Draw: 3, Lose: 3}},
+		{name: "arbitrage either way", raw: Odds{Win: 3.506, Draw: 3.506, Lose: 3.506},
+			wantTruncated: Odds{Win: 3.5, Draw: 3.5, Lose: 3.5}, wantRounded: Odds{Win: 3.51, Draw: 3.51, Lose: 3.51},
+			wantTruncArb: true, wantRound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, differs := auditOddsRounding("g1", tt.raw)
+			want := RoundingAuditResult{GameID: "g1", Raw: tt.raw, Truncated: tt.wantTruncated, Rounded: tt.wantRounded,
+				TruncatedArbitrage: tt.wantTruncArb, RoundedArbitrage: tt.wantRound}
+			if !reflect.DeepEqual(got, want) || differs != tt.wantDiffers {
+				t.Errorf("auditOddsRounding() = %+v, %v, want %+v, %v", got, differs, want, tt.wantDiffers)
+			}
+		})
+	}
+}
+
+func TestRoundingAuditReportsOnlyFlippedFixtures(t *testing.T) {
+	sensitive := roundingAudit(20000, 1)
+	if len(sensitive) == 0 {
+		t.Fatal("no generated fixture is sensitive to rounding")
+	}
+	for _, result := range sensitive {
+		if result.TruncatedArbitrage == result.RoundedArbitrage {
+			t.Errorf("%s has the same status either way: %+v", result.GameID, result)
+		}
+	}
+	if again := roundingAudit(20000, 1); !reflect.DeepEqual(again, sensitive) {
+		t.Error("the same seed audited different fixtures")
+	}
+}
+
+func TestPrintRoundingAudit(t *testing.T) {
+	result, _ := auditOddsRounding("g1", Odds{Win: 3.006, Draw: 3.006, Lose: 3.006})
+	var buf bytes.Buffer
+	printRoundingAudit(&buf, []RoundingAuditResult{result}, 10, defaultOutputOptions)
+	want := "g1: truncated 3.00/3.00/3.00 is no arbitrage, rounded 3.01/3.01/3.01 is arbitrage\n" +
+		"1 of 10 generated fixtures change arbitrage status between truncated and rounded odds\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// Keep a random sample of fixtures, including each game ID with probability
+// rate. The decision depends only on the seed and the game ID, so a fixture
+// is kept or dropped at every bookmaker alike and the same seed always
+// draws the same sample. Also returns the number of fixtures kept and seen.
+func sampleFixtures(bookmakers []Bookmaker, rate float64, seed int64) (sampled []Bookmaker, kept, total int) {
+	decided := make(map[string]bool)
+	sampled = make([]Bookmaker, 0, len(bookmakers))
+	for _, bookmaker := range bookmakers {
+		games := make([]Game, 0, int(float64(len(bookmaker.Games))*rate))
+		for _, game := range bookmaker.Games {
+			keep, seen := decided[game.ID]
+			if !seen {
+				keep = sampleFraction(game.ID, seed) < rate
+				decided[game.ID] = keep
+				total++
+				if keep {
+					kept++
+				}
+			}
+			if keep {
+				games = append(games, game)
+			}
+		}
+		bookmaker.Games = games
+		sampled = append(sampled, bookmaker)
+	}
+	return sampled, kept, total
+}
+
+// Map a game ID to a pseudo-random fraction in [0, 1) derived from the seed
+func sampleFraction(gameID string, seed int64) float64 {
+	h := fnv.New64a()
+	var seedBytes [8]byte
+	binary.LittleEndian.PutUint64(seedBytes[:], uint64(seed))
+	h.Write(seedBytes[:])
+	h.Write([]byte(gameID))
+	// FNV only carries a change in the last bytes into the low bits, so IDs
+	// differing in a trailing counter would share their top bits; mix them
+	// with the MurmurHash3 finalizer before taking the fraction
+	sum := h.Sum64()
+	sum ^= sum >> 33
+	sum *= 0xff51afd7ed558ccd
+	sum ^= sum >> 33
+	sum *= 0xc4ceb9fe1a85ec53
+	sum ^= sum >> 33
+	return float64(sum>>11) / math.Exp2(53)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSampleFixtures(t *testing.T) {
+	bookmakers := sharedFixtureBookmakers(3, 400, 9)
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1}
+	full, _ := detectArbitrageOpportunities(context.Background(), bookmakers, opts)
+	if len(full) == 0 {
+		t.Fatal("the generated data has no arbitrages to sample")
+	}
+
+	tests := []struct {
+		name     string
+		rate     float64
+		wantKept int
+		wantFull bool
+	}{
+		{name: "rate 0 scans nothing", rate: 0, wantKept: 0},
+		{name: "rate 1 scans everything", rate: 1, wantKept: 400, wantFull: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sampled, kept, total := sampleFixtures(bookmakers, tt.rate, 7)
+			if kept != tt.wantKept || total != 400 {
+				t.Errorf("kept %d of %d fixtures, want %d of 400", kept, total, tt.wantKept)
+			}
+			detected, _ := detectArbitrageOpportunities(context.Background(), sampled, opts)
+			if tt.wantFull {
+				if !reflect.DeepEqual(detected, full) {
+					t.Errorf("sampled scan found %d opportunities, want the full scan's %d", len(detected), len(full))
+				}
+			} else if len(detected) != 0 {
+				t.Errorf("sampled scan found %d opportunities, want none", len(detected))
+			}
+		})
+	}
+}
+
+func TestSampleFixturesDeterministic(t *testing.T) {
+	bookmakers := sharedFixtureBookmakers(3, 400, 9)
+	first, kept, total := sampleFixtures(bookmakers, 0.25, 7)
+	if kept == 0 || kept == total {
+		t.Fatalf("kept %d of %d fixtures at rate 0.25", kept, total)
+	}
+	again, _, _ := sampleFixtures(bookmakers, 0.25, 7)
+	if !reflect.DeepEqual(first, again) {
+		t.Error("the same seed drew a different sample")
+	}
+	// A kept fixture is kept at every bookmaker
+	for _, bookmaker := range first[1:] {
+		if !reflect.DeepEqual(fixtureIDs(bookmaker), fixtureIDs(first[0])) {
+			t.Errorf("bookmaker %s kept different fixtures from %s", bookmaker.Name, first[0].Name)
+		}
+	}
+	if other, _, _ := sampleFixtures(bookmakers, 0.25, 8); reflect.DeepEqual(fixtureIDs(other[0]), fixtureIDs(first[0])) {
+		t.Error("a different seed drew the same sample")
+	}
+}
+
+// Return a bookmaker's game IDs, sorted
+func fixtureIDs(bookmaker Bookmaker) []string {
+	ids := make([]string, len(bookmaker.Games))
+	for i, game := range bookmaker.Games {
+		ids[i] = game.ID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestRunReportsSampledResults(t *testing.T) {
+	file := writeTestData(t, plantedBookmakers())
+	code, _, stderr := runCLI(t, "-sample-rate", "0", "-seed", "1", "-file", file)
+	if code != exitNoOpportunities {
+		t.Errorf("exit code = %d, want %d", code, exitNoOpportunities)
+	}
+	if !strings.Contains(stderr, "results are sampled: scanning 0 of 1 fixtures") {
+		t.Errorf("stderr = %q, want the sampling warning", stderr)
+	}
+}
+
+func TestSampleFractionSpreadsSequentialIDs(t *testing.T) {
+	// IDs differing only in a trailing counter must still land all over
+	// [0, 1), or a sample of sequential fixtures keeps all or none of them
+	var below int
+	for i := 0; i < 1000; i++ {
+		if sampleFraction(fmt.Sprintf("fixture-%06d", i), 7) < 0.5 {
+			below++
+		}
+	}
+	if below < 400 || below > 600 {
+		t.Errorf("%d of 1000 sequential IDs fall below 0.5, want about half", below)
+	}
+}
@@ -1,11 +1,22 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	cryptorand "crypto/rand"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
 	"math/rand"
+	"net/http"
 	"os"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,6 +30,47 @@ type Odds struct {
 	Lose float64 `json:"lose"`
 }
 
+// Decimal places odds are rounded to when encoded as JSON in reports, so
+// floating point noise from conversions such as 2.0999999 is written as 2.1.
+// Negative writes them unrounded. Odds in memory are never rounded by it,
+// and neither are games, so data files keep full precision.
+var jsonOddsPrecision = 2
+
+// Encode odds with each value rounded to jsonOddsPrecision decimal places.
+// Rounded values encode as their shortest representation, so decoding and
+// encoding again gives the same output.
+func (o Odds) MarshalJSON() ([]byte, error) {
+	type plainOdds Odds
+	if jsonOddsPrecision >= 0 {
+		scale := math.Pow(10, float64(jsonOddsPrecision))
+		round := func(val float64) float64 {
+			return math.Round(val*scale) / scale
+		}
+		o = Odds{Win: round(o.Win), Draw: round(o.Draw), Lose: round(o.Lose)}
+	}
+	return json.Marshal(plainOdds(o))
+}
+
+// Names of the outcomes a set of odds covers
+const (
+	OutcomeWin  = "win"
+	OutcomeDraw = "draw"
+	OutcomeLose = "lose"
+)
+
+// Return the odds for a named outcome
+func oddsForOutcome(odds Odds, outcome string) (float64, error) {
+	switch outcome {
+	case OutcomeWin:
+		return odds.Win, nil
+	case OutcomeDraw:
+		return odds.Draw, nil
+	case OutcomeLose:
+		return odds.Lose, nil
+	}
+	return 0, fmt.Errorf("unknown outcome %q", outcome)
+}
+
 // Define the structure for a game
 type Game struct {
 	ID      string `json:"id"`
@@ -26,21 +78,86 @@ type Game struct {
 	TeamB   string `json:"team_b"`
 	Odds    Odds   `json:"odds"`
 	EventAt string `json:"event_at"`
+	Sport   string `json:"sport,omitempty"`
+	League  string `json:"league,omitempty"`
+	// Draw no bet market odds, with win and lose holding the home and away
+	// sides, when the bookmaker offers one
+	DrawNoBet *Odds `json:"draw_no_bet,omitempty"`
+	// Free-form metadata about the fixture from external sources, such as
+	// team news
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Available is false while the bookmaker has suspended the market, in
+	// which case its odds must not be used
+	Available bool `json:"available,omitempty"`
+}
+
+// Odds encoded at full precision, without the report rounding of Odds
+type fullPrecisionOdds Odds
+
+// Encode a game with its odds at full precision, so data files written from
+// it read back exactly as they were in memory. Available is only written for
+// suspended games, since games without it decode as available.
+func (g Game) MarshalJSON() ([]byte, error) {
+	type gameAlias Game
+	var suspended *bool
+	if !g.Available {
+		suspended = &g.Available
+	}
+	return json.Marshal(struct {
+		gameAlias
+		Odds      fullPrecisionOdds  `json:"odds"`
+		DrawNoBet *fullPrecisionOdds `json:"draw_no_bet,omitempty"`
+		Available *bool              `json:"available,omitempty"`
+	}{gameAlias(g), fullPrecisionOdds(g.Odds), (*fullPrecisionOdds)(g.DrawNoBet), suspended})
+}
+
+// Decode a game, treating games without an available field as available
+func (g *Game) UnmarshalJSON(data []byte) error {
+	type plainGame Game
+	game := plainGame{Available: true}
+	if err := json.Unmarshal(data, &game); err != nil {
+		return err
+	}
+	*g = Game(game)
+	return nil
 }
 
 // Define the structure for a bookmaker
 type Bookmaker struct {
-	Name  string `json:"name"`
-	Games []Game `json:"games"`
+	Name        string  `json:"name"`
+	Games       []Game  `json:"games"`
+	Reliability float64 `json:"reliability,omitempty"`
+	// LinkTemplate builds a link to a game at the bookmaker from the {book}
+	// and {id} placeholders
+	LinkTemplate string `json:"link_template,omitempty"`
+	// Currency the bookmaker quotes stakes in, empty for the base currency
+	Currency string `json:"currency,omitempty"`
+	// Largest total payout the bookmaker accepts on a single bet, zero for
+	// no limit
+	MaxPayout float64 `json:"max_payout,omitempty"`
+	// Largest stake the bookmaker accepts on a single bet keyed by sport,
+	// with "default" covering sports not listed
+	MaxStakes map[string]float64 `json:"max_stakes,omitempty"`
+	// Smallest stake the bookmaker accepts on a single bet, zero for none
+	MinStake float64 `json:"min_stake,omitempty"`
 }
 
 // Generate random odds
-func generateOdds() Odds {
-	rand.Seed(time.Now().UnixNano())
+func generateOdds(rng *rand.Rand) Odds {
+	raw := generateRawOdds(rng)
 	return Odds{
-		Win:  roundToTwoDecimal(rand.Float64()*2 + 1),
-		Draw: roundToTwoDecimal(rand.Float64()*3 + 2),
-		Lose: roundToTwoDecimal(rand.Float64()*4 + 2),
+		Win:  roundToTwoDecimal(raw.Win),
+		Draw: roundToTwoDecimal(raw.Draw),
+		Lose: roundToTwoDecimal(raw.Lose),
+	}
+}
+
+// Generate random odds before they are cut to two decimal places
+func generateRawOdds(rng *rand.Rand) Odds {
+	return Odds{
+		Win:  rng.Float64()*2 + 1,
+		Draw: rng.Float64()*3 + 2,
+		Lose: rng.Float64()*4 + 2,
 	}
 }
 
@@ -49,16 +166,33 @@ func roundToTwoDecimal(val float64) float64 {
 	return float64(int(val*100)) / 100
 }
 
-// Generate a list of fake games
-func generateGames(numGames int) []Game {
+// Generate a list of fake games, using eventAt to produce each game's date
+// and names to choose how teams are named
+func generateGames(rng *rand.Rand, numGames int, eventAt func() string, names NameMode) []Game {
 	var games []Game
 	for i := 0; i < numGames; i++ {
+		odds := generateOdds(rng)
 		game := Game{
-			ID:      faker.UUIDDigit(),
-			TeamA:   faker.Word(),
-			TeamB:   faker.Word(),
-			Odds:    generateOdds(),
-			EventAt: faker.Date(),
+			ID:        faker.UUIDDigit(),
+			Odds:      odds,
+			EventAt:   eventAt(),
+			Available: true,
+		}
+		if names == NamesRealistic {
+			nameRealistically(rng, &game)
+		} else {
+			game.TeamA = faker.Word()
+			game.TeamB = faker.Word()
+		}
+		// Re-pick teams the same way, so realistic fixtures keep bundled
+		// names and the odds of whichever league is picked
+		for isSelfMatch(game) {
+			if names == NamesRealistic {
+				game.Odds = odds
+				nameRealistically(rng, &game)
+			} else {
+				game.TeamB = faker.Word()
+			}
 		}
 		games = append(games, game)
 	}
@@ -66,21 +200,25 @@ func generateGames(numGames int) []Game {
 }
 
 // Generate a list of bookmakers with games using goroutines and channels
-func generateBookmakers(numBookmakers, numGamesPerBookmaker int) []Bookmaker {
+func generateBookmakers(numBookmakers, numGamesPerBookmaker int, names NameMode) []Bookmaker {
+	generationMu.Lock()
+	defer generationMu.Unlock()
+
 	var bookmakers []Bookmaker
 	var wg sync.WaitGroup
 	bookmakerCh := make(chan Bookmaker, numBookmakers)
 
 	for i := 0; i < numBookmakers; i++ {
 		wg.Add(1)
-		go func() {
+		go func(i int) {
 			defer wg.Done()
+			rng := rand.New(rand.NewSource(time.Now().UnixNano() + int64(i)))
 			bookmaker := Bookmaker{
 				Name:  faker.DomainName(),
-				Games: generateGames(numGamesPerBookmaker),
+				Games: generateGames(rng, numGamesPerBookmaker, faker.Date, names),
 			}
 			bookmakerCh <- bookmaker
-		}()
+		}(i)
 	}
 
 	go func() {
@@ -92,41 +230,158 @@ func generateBookmakers(numBookmakers, numGamesPerBookmaker int) []Bookmaker {
 		bookmakers = append(bookmakers, bookmaker)
 	}
 
+	ensureUniqueBookmakerNames(bookmakers)
+	return bookmakers
+}
+
+// Latest date produced by seeded generation. faker.Date bounds its dates by
+// the current time, which would make seeded output change from day to day.
+var seededEventAtLimit = time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Serialize generation, since seeded generation temporarily replaces faker's
+// package-level random sources
+var generationMu sync.Mutex
+
+// Generate a list of bookmakers with games deterministically from a seed.
+// Bookmakers are generated sequentially so the same seed always produces the
+// same data.
+func generateBookmakersWithSeed(numBookmakers, numGamesPerBookmaker int, seed int64, names NameMode) []Bookmaker {
+	generationMu.Lock()
+	defer generationMu.Unlock()
+
+	faker.SetRandomSource(faker.NewSafeSource(rand.NewSource(seed)))
+	faker.SetCryptoSource(rand.New(rand.NewSource(seed + 1)))
+	defer func() {
+		faker.SetRandomSource(faker.NewSafeSource(rand.NewSource(time.Now().UnixNano())))
+		faker.SetCryptoSource(cryptorand.Reader)
+	}()
+
+	rng := rand.New(rand.NewSource(seed + 2))
+	eventAt := func() string {
+		return time.Unix(rng.Int63n(seededEventAtLimit.Unix()), 0).UTC().Format(faker.BaseDateFormat)
+	}
+
+	bookmakers := make([]Bookmaker, 0, numBookmakers)
+	for i := 0; i < numBookmakers; i++ {
+		bookmakers = append(bookmakers, Bookmaker{
+			Name:  faker.DomainName(),
+			Games: generateGames(rng, numGamesPerBookmaker, eventAt, names),
+		})
+	}
+
+	ensureUniqueBookmakerNames(bookmakers)
 	return bookmakers
 }
 
+// Generate bookmakers, deterministically when seed is non-zero
+func generateBookmakersFromSeed(numBookmakers, numGamesPerBookmaker int, seed int64, names NameMode) []Bookmaker {
+	if seed != 0 {
+		return generateBookmakersWithSeed(numBookmakers, numGamesPerBookmaker, seed, names)
+	}
+	return generateBookmakers(numBookmakers, numGamesPerBookmaker, names)
+}
+
 // Write bookmakers data to a JSON file
 func writeBookmakersToFile(bookmakers []Bookmaker, filename string) error {
 	data, err := json.MarshalIndent(bookmakers, "", "  ")
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(filename, data, 0644)
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		return newFileError(filename, err)
+	}
+	return nil
 }
 
 // Read bookmakers data from a JSON file
-func readBookmakersFromFile(filename string) ([]Bookmaker, error) {
-	data, err := ioutil.ReadFile(filename)
+func readBookmakersFromFile(filename string, opts loadOptions) ([]Bookmaker, error) {
+	data, release, err := readFileData(filename, opts.Mmap)
 	if err != nil {
-		return nil, err
+		return nil, newFileError(filename, err)
+	}
+	defer release()
+	bookmakers, err := decodeBookmakersJSON(data, opts.Lenient)
+	if err != nil {
+		if !opts.Lenient || !errors.Is(err, errTrailingData) {
+			return nil, &FileError{Path: filename, Err: ErrParse, Cause: err}
+		}
+		warn("%s: %v, ignoring it", filename, err)
+	}
+	if len(bookmakers) == 0 {
+		return nil, &FileError{Path: filename, Err: ErrNoData}
 	}
+	return bookmakers, nil
+}
+
+// Reported when a JSON file has data after its bookmakers array
+var errTrailingData = errors.New("trailing data after JSON array")
+
+// Decode a JSON array of bookmakers. Data after the array is an error; in
+// lenient mode the decoded bookmakers are returned along with that error.
+func decodeBookmakersJSON(data []byte, lenient bool) ([]Bookmaker, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
 	var bookmakers []Bookmaker
-	err = json.Unmarshal(data, &bookmakers)
-	return bookmakers, err
+	if err := decoder.Decode(&bookmakers); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		err := fmt.Errorf("%w at offset %d", errTrailingData, decoder.InputOffset())
+		if lenient {
+			return bookmakers, err
+		}
+		return nil, err
+	}
+	return bookmakers, nil
 }
 
-// Calculate the arbitrage percentage for a set of odds
+// Calculate the arbitrage percentage for a set of odds. Zero draw odds mean
+// the market has no draw, as in two-way sports, and the draw leg is ignored.
 func calculateArbitragePercentage(odds Odds) float64 {
+	if odds.Draw == 0 {
+		return (1 / odds.Win) + (1 / odds.Lose)
+	}
 	return (1 / odds.Win) + (1 / odds.Draw) + (1 / odds.Lose)
 }
 
+// Define the structure for the stakes placed on each outcome of a position
+type StakeAllocation struct {
+	Win  float64 `json:"win"`
+	Draw float64 `json:"draw"`
+	Lose float64 `json:"lose"`
+}
+
+// Return the total staked across every outcome
+func (s StakeAllocation) Total() float64 {
+	return s.Win + s.Draw + s.Lose
+}
+
+// Return the stake on a named outcome
+func (s StakeAllocation) Stake(outcome string) float64 {
+	switch outcome {
+	case OutcomeDraw:
+		return s.Draw
+	case OutcomeLose:
+		return s.Lose
+	}
+	return s.Win
+}
+
+// Return what each outcome's stake pays out if that outcome happens
+func (s StakeAllocation) Payout(odds Odds) (win, draw, lose float64) {
+	return s.Win * odds.Win, s.Draw * odds.Draw, s.Lose * odds.Lose
+}
+
 // Calculate the stake allocation for an arbitrage opportunity
-func calculateStakes(odds Odds, totalBet float64) (winStake, drawStake, loseStake float64) {
+func calculateStakes(odds Odds, totalBet float64) StakeAllocation {
 	arbitragePercentage := calculateArbitragePercentage(odds)
-	winStake = (totalBet / arbitragePercentage) / odds.Win
-	drawStake = (totalBet / arbitragePercentage) / odds.Draw
-	loseStake = (totalBet / arbitragePercentage) / odds.Lose
-	return winStake, drawStake, loseStake
+	stakes := StakeAllocation{
+		Win:  (totalBet / arbitragePercentage) / odds.Win,
+		Lose: (totalBet / arbitragePercentage) / odds.Lose,
+	}
+	if odds.Draw != 0 {
+		stakes.Draw = (totalBet / arbitragePercentage) / odds.Draw
+	}
+	return stakes
 }
 
 // Find the best odds for each game across different bookmakers
@@ -134,6 +389,9 @@ func findBestOdds(bookmakers []Bookmaker) map[string]Odds {
 	bestOdds := make(map[string]Odds)
 	for _, bookmaker := range bookmakers {
 		for _, game := range bookmaker.Games {
+			if !game.Available {
+				continue
+			}
 			currentBest, exists := bestOdds[game.ID]
 			if !exists || game.Odds.Win > currentBest.Win {
 				currentBest.Win = game.Odds.Win
@@ -150,46 +408,1176 @@ func findBestOdds(bookmakers []Bookmaker) map[string]Odds {
 	return bestOdds
 }
 
-// Find arbitrage opportunities among a list of games
-func findArbitrageOpportunities(bookmakers []Bookmaker) {
-	bestOdds := findBestOdds(bookmakers)
-	for gameID, odds := range bestOdds {
-		arbitragePercentage := calculateArbitragePercentage(odds)
-		if arbitragePercentage < 1 {
-			fmt.Printf("Arbitrage opportunity found for game %s\n", gameID)
-			fmt.Printf("Odds: Win: %.2f, Draw: %.2f, Lose: %.2f\n", odds.Win, odds.Draw, odds.Lose)
-			totalBet := 100.0 // Example total bet amount
-			winStake, drawStake, loseStake := calculateStakes(odds, totalBet)
-			fmt.Printf("Stakes: Win: %.2f, Draw: %.2f, Lose: %.2f\n", winStake, drawStake, loseStake)
-			totalStake := winStake + drawStake + loseStake
-			guaranteedProfit := (totalBet / arbitragePercentage) - totalStake
-			fmt.Printf("Guaranteed profit: %.2f\n", guaranteedProfit)
-			fmt.Println()
+// Define the structure for the best odds of a game with the bookmaker offering each leg
+type BestOddsWithSource struct {
+	Odds       Odds   `json:"odds"`
+	WinSource  string `json:"win_source"`
+	DrawSource string `json:"draw_source"`
+	LoseSource string `json:"lose_source"`
+}
+
+// Find the best odds for each game across different bookmakers, along with
+// the bookmaker offering each leg. Ties go to the bookmaker listed first.
+func findBestOddsWithSource(bookmakers []Bookmaker) map[string]BestOddsWithSource {
+	bestOdds := make(map[string]BestOddsWithSource)
+	for _, bookmaker := range bookmakers {
+		for _, game := range bookmaker.Games {
+			if !game.Available {
+				continue
+			}
+			currentBest, exists := bestOdds[game.ID]
+			if !exists || game.Odds.Win > currentBest.Odds.Win {
+				currentBest.Odds.Win = game.Odds.Win
+				currentBest.WinSource = bookmaker.Name
+			}
+			if !exists || game.Odds.Draw > currentBest.Odds.Draw {
+				currentBest.Odds.Draw = game.Odds.Draw
+				currentBest.DrawSource = bookmaker.Name
+			}
+			if !exists || game.Odds.Lose > currentBest.Odds.Lose {
+				currentBest.Odds.Lose = game.Odds.Lose
+				currentBest.LoseSource = bookmaker.Name
+			}
+			bestOdds[game.ID] = currentBest
 		}
 	}
+	return bestOdds
 }
 
-func main() {
-	const filename = "bookmakers.json"
-	numBookmakers := 100          // Number of bookmakers
-	numGamesPerBookmaker := 10000 // Number of games per bookmaker
+// Count the legs left out of detection because their market is suspended
+func suspendedLegs(bookmakers []Bookmaker) int {
+	suspended := 0
+	for _, bookmaker := range bookmakers {
+		for _, game := range bookmaker.Games {
+			if game.Available {
+				continue
+			}
+			if game.Odds.Draw == 0 {
+				suspended += 2
+			} else {
+				suspended += 3
+			}
+		}
+	}
+	return suspended
+}
+
+// Define the structure for an arbitrage opportunity
+type ArbitrageOpportunity struct {
+	ID                  string  `json:"id"`
+	GameID              string  `json:"game_id"`
+	Sport               string  `json:"sport,omitempty"`
+	Market              string  `json:"market,omitempty"`
+	Bookmaker           string  `json:"bookmaker,omitempty"`
+	WinBookmaker        string  `json:"win_bookmaker,omitempty"`
+	DrawBookmaker       string  `json:"draw_bookmaker,omitempty"`
+	LoseBookmaker       string  `json:"lose_bookmaker,omitempty"`
+	Reliability         float64 `json:"reliability,omitempty"`
+	Odds                Odds    `json:"odds"`
+	ArbitragePercentage float64 `json:"arbitrage_percentage"`
+	TotalBet            float64 `json:"total_bet"`
+	WinStake            float64 `json:"win_stake"`
+	DrawStake           float64 `json:"draw_stake"`
+	LoseStake           float64 `json:"lose_stake"`
+	GuaranteedProfit    float64 `json:"guaranteed_profit"`
+	// Guaranteed profit as a fraction of the total bet
+	ReturnOnCapital float64 `json:"return_on_capital"`
+	ScaledFrom      float64 `json:"scaled_from,omitempty"`
+	// Links to each leg at its bookmaker keyed by outcome, for bookmakers
+	// with a configured link template
+	Links map[string]string `json:"links,omitempty"`
+	// Payout caps of the legs keyed by outcome, for bookmakers that limit
+	// the payout of a single bet
+	MaxPayouts map[string]float64 `json:"max_payouts,omitempty"`
+	// Stake limits of the legs keyed by outcome, for bookmakers that limit
+	// the stake on the fixture's sport
+	MaxStakes map[string]float64 `json:"max_stakes,omitempty"`
+	// Minimum stakes of the legs keyed by outcome, for bookmakers with one
+	MinStakes map[string]float64 `json:"min_stakes,omitempty"`
+	// Base currency of the monetary values when currencies are in use, and
+	// the stake for each leg in its bookmaker's currency when that differs
+	Currency    string                `json:"currency,omitempty"`
+	LocalStakes map[string]LocalStake `json:"local_stakes,omitempty"`
+}
+
+// Return the stakes of an opportunity's legs
+func (o ArbitrageOpportunity) Stakes() StakeAllocation {
+	return StakeAllocation{Win: o.WinStake, Draw: o.DrawStake, Lose: o.LoseStake}
+}
+
+// Build an arbitrage opportunity for a set of odds and a total bet
+func newArbitrageOpportunity(gameID string, odds Odds, totalBet float64) ArbitrageOpportunity {
+	arbitragePercentage := calculateArbitragePercentage(odds)
+	stakes := calculateStakes(odds, totalBet)
+	opportunity := ArbitrageOpportunity{
+		GameID:              gameID,
+		Odds:                odds,
+		ArbitragePercentage: arbitragePercentage,
+		TotalBet:            totalBet,
+		WinStake:            stakes.Win,
+		DrawStake:           stakes.Draw,
+		LoseStake:           stakes.Lose,
+		GuaranteedProfit:    (totalBet / arbitragePercentage) - stakes.Total(),
+	}
+	opportunity.ReturnOnCapital = returnOnCapital(opportunity, totalBet)
+	return opportunity
+}
+
+// Evaluate a fixture's odds, returning an opportunity when they are valid and
+// their arbitrage percentage is below threshold. A threshold of 1 accepts any
+// arbitrage; a lower one leaves a safety buffer against odds drifting while
+// the legs are placed.
+func evaluateFixture(gameID, sport string, odds Odds, totalBet, threshold float64) (ArbitrageOpportunity, bool) {
+	odds = oddsForSport(odds, sport)
+	if checkOdds(odds, sport) != nil || calculateArbitragePercentage(odds) >= threshold {
+		return ArbitrageOpportunity{}, false
+	}
+	opportunity := newArbitrageOpportunity(gameID, odds, totalBet)
+	opportunity.Sport = sport
+	return opportunity, true
+}
+
+// Call fn for each arbitrage opportunity in the best odds across bookmakers,
+// stopping early when fn returns false. Each opportunity records the
+// bookmakers offering its legs and the lowest reliability among them.
+// Games are visited in game ID order so streamed output is reproducible.
+// Detection stops once ctx is done, such as when a time budget runs out, and
+// reports whether it was cut short; the opportunities already passed to fn
+// are genuine but others may have been missed.
+func eachArbitrageOpportunity(ctx context.Context, bookmakers []Bookmaker, opts scanOptions, fn func(ArbitrageOpportunity) bool) (partial bool) {
+	sports := fixtureSports(bookmakers)
+	reliabilities := bookmakerReliabilities(bookmakers)
+	templates := bookmakerLinkTemplates(bookmakers)
+	payoutCaps := bookmakerMaxPayouts(bookmakers)
+	stakeLimits := bookmakerStakeLimits(bookmakers)
+	minStakes := bookmakerMinStakes(bookmakers)
+	bestOdds := findBestOddsWithStrategy(ctx, bookmakers, opts.BestOdds)
+	gameIDs := make([]string, 0, len(bestOdds))
+	for gameID := range bestOdds {
+		gameIDs = append(gameIDs, gameID)
+	}
+	sort.Strings(gameIDs)
+	for i, gameID := range gameIDs {
+		if i%1024 == 0 && ctx.Err() != nil {
+			return true
+		}
+		best := bestOdds[gameID]
+		sport := effectiveSport(sports[gameID], best.Odds, opts.TwoWayWithoutDraw)
+		opportunity, ok := evaluateFixture(gameID, sport, best.Odds, opts.TotalBet, opts.ArbThreshold)
+		if !ok {
+			continue
+		}
+		opportunity.WinBookmaker = best.WinSource
+		opportunity.LoseBookmaker = best.LoseSource
+		opportunity.Reliability = math.Min(reliabilities[best.WinSource], reliabilities[best.LoseSource])
+		if opportunity.Odds.Draw != 0 {
+			opportunity.DrawBookmaker = best.DrawSource
+			opportunity.Reliability = math.Min(opportunity.Reliability, reliabilities[best.DrawSource])
+		}
+		opportunity.Links = opportunityLinks(opportunity, templates)
+		opportunity.MaxPayouts = opportunityMaxPayouts(opportunity, payoutCaps)
+		opportunity.MaxStakes = opportunityMaxStakes(opportunity, stakeLimits)
+		opportunity.MinStakes = opportunityMinStakes(opportunity, minStakes)
+		opportunity.ID = opportunityID(opportunity)
+		if !fn(opportunity) {
+			return false
+		}
+	}
+	return ctx.Err() != nil
+}
+
+// Detect arbitrage opportunities using the best odds across bookmakers,
+// reporting whether ctx cut detection short
+func detectArbitrageOpportunities(ctx context.Context, bookmakers []Bookmaker, opts scanOptions) ([]ArbitrageOpportunity, bool) {
+	var opportunities []ArbitrageOpportunity
+	partial := eachArbitrageOpportunity(ctx, bookmakers, opts, func(opportunity ArbitrageOpportunity) bool {
+		opportunities = append(opportunities, opportunity)
+		return true
+	})
+	return opportunities, partial
+}
+
+// Warning given when detection ran out of time
+const partialResultsWarning = "detection stopped at the -deadline, results are partial"
+
+// Find arbitrage opportunities within a single bookmaker's own odds, such as
+// those created by promotional boosts
+func findIntraBookmakerArbitrage(bookmaker Bookmaker, totalBet, threshold float64) []ArbitrageOpportunity {
+	var opportunities []ArbitrageOpportunity
+	for _, game := range bookmaker.Games {
+		if !game.Available {
+			continue
+		}
+		if opportunity, ok := evaluateFixture(game.ID, game.Sport, game.Odds, totalBet, threshold); ok {
+			opportunity.Bookmaker = bookmaker.Name
+			opportunity.Reliability = bookmaker.Reliability
+			opportunity.Links = opportunityLinks(opportunity, bookmakerLinkTemplates([]Bookmaker{bookmaker}))
+			opportunity.MaxPayouts = opportunityMaxPayouts(opportunity, bookmakerMaxPayouts([]Bookmaker{bookmaker}))
+			opportunity.MaxStakes = opportunityMaxStakes(opportunity, bookmakerStakeLimits([]Bookmaker{bookmaker}))
+			opportunity.MinStakes = opportunityMinStakes(opportunity, bookmakerMinStakes([]Bookmaker{bookmaker}))
+			opportunity.ID = opportunityID(opportunity)
+			opportunities = append(opportunities, opportunity)
+		}
+	}
+	return opportunities
+}
+
+// Sort opportunities by game ID, then bookmaker, so output is stable across
+// runs regardless of map iteration order
+func sortOpportunitiesByGame(opportunities []ArbitrageOpportunity) {
+	sort.SliceStable(opportunities, func(i, j int) bool {
+		if opportunities[i].GameID != opportunities[j].GameID {
+			return opportunities[i].GameID < opportunities[j].GameID
+		}
+		return opportunities[i].Bookmaker < opportunities[j].Bookmaker
+	})
+}
+
+// Define the structure for one leg of an opportunity: a stake on one outcome
+type Leg struct {
+	Outcome   string  `json:"outcome"`
+	Odds      float64 `json:"odds"`
+	Stake     float64 `json:"stake"`
+	Bookmaker string  `json:"bookmaker,omitempty"`
+	MaxPayout float64 `json:"max_payout,omitempty"`
+	MaxStake  float64 `json:"max_stake,omitempty"`
+	MinStake  float64 `json:"min_stake,omitempty"`
+}
+
+// Return the legs of an opportunity in win, draw, lose order, leaving out the
+// draw in markets without one
+func opportunityLegs(opportunity ArbitrageOpportunity) []Leg {
+	source := func(bookmaker string) string {
+		if opportunity.Bookmaker != "" {
+			return opportunity.Bookmaker
+		}
+		return bookmaker
+	}
+	legs := []Leg{{Outcome: OutcomeWin, Odds: opportunity.Odds.Win, Stake: opportunity.WinStake, Bookmaker: source(opportunity.WinBookmaker),
+		MaxPayout: opportunity.MaxPayouts[OutcomeWin], MaxStake: opportunity.MaxStakes[OutcomeWin], MinStake: opportunity.MinStakes[OutcomeWin]}}
+	if opportunity.Odds.Draw != 0 {
+		legs = append(legs, Leg{Outcome: OutcomeDraw, Odds: opportunity.Odds.Draw, Stake: opportunity.DrawStake, Bookmaker: source(opportunity.DrawBookmaker),
+			MaxPayout: opportunity.MaxPayouts[OutcomeDraw], MaxStake: opportunity.MaxStakes[OutcomeDraw], MinStake: opportunity.MinStakes[OutcomeDraw]})
+	}
+	return append(legs, Leg{Outcome: OutcomeLose, Odds: opportunity.Odds.Lose, Stake: opportunity.LoseStake, Bookmaker: source(opportunity.LoseBookmaker),
+		MaxPayout: opportunity.MaxPayouts[OutcomeLose], MaxStake: opportunity.MaxStakes[OutcomeLose], MinStake: opportunity.MinStakes[OutcomeLose]})
+}
+
+// Return the label a sport uses for an outcome
+func outcomeLabel(labels SportOutcomes, outcome string) string {
+	switch outcome {
+	case OutcomeWin:
+		return labels.Win
+	case OutcomeDraw:
+		return labels.Draw
+	}
+	return labels.Lose
+}
+
+// Define the options controlling how opportunities are printed. Precision
+// only affects display; computed values are never rounded by it. Color adds
+// ANSI codes and must only be set when writing to a terminal.
+type outputOptions struct {
+	Precision     int
+	OddsPrecision int
+	Color         bool
+	// Also print the fair, zero-profit split of each opportunity's total bet
+	ShowFair bool
+	// Also print the odds each leg can drop to before the arbitrage is gone
+	Sensitivity bool
+	// Print opportunities in sections per sport, each sorted by profit
+	GroupBySport bool
+}
+
+// Default output options, matching two decimal places for money and odds
+var defaultOutputOptions = outputOptions{Precision: 2, OddsPrecision: 2}
+
+// Print an arbitrage opportunity
+func printArbitrageOpportunity(w io.Writer, opportunity ArbitrageOpportunity, out outputOptions) {
+	if opportunity.Bookmaker != "" {
+		fmt.Fprintf(w, "Arbitrage opportunity found for game %s at %s\n", opportunity.GameID, opportunity.Bookmaker)
+	} else {
+		fmt.Fprintf(w, "Arbitrage opportunity found for game %s\n", opportunity.GameID)
+	}
+	fmt.Fprintf(w, "ID: %s\n", opportunity.ID)
+
+	labels := outcomesForSport(opportunity.Sport)
+	var odds, bookmakers, links, stakes, localStakes []string
+	for _, leg := range opportunityLegs(opportunity) {
+		label := outcomeLabel(labels, leg.Outcome)
+		odds = append(odds, fmt.Sprintf("%s: %.*f", label, out.OddsPrecision, leg.Odds))
+		bookmakers = append(bookmakers, fmt.Sprintf("%s: %s", label, leg.Bookmaker))
+		link, exists := opportunity.Links[leg.Outcome]
+		if !exists {
+			link = leg.Bookmaker
+		}
+		links = append(links, fmt.Sprintf("%s: %s", label, link))
+		stakes = append(stakes, fmt.Sprintf("%s: %.*f", label, out.Precision, leg.Stake))
+		if local, exists := opportunity.LocalStakes[leg.Outcome]; exists {
+			localStakes = append(localStakes, fmt.Sprintf("%s: %.*f %s", label, out.Precision, local.Amount, local.Currency))
+		}
+	}
+	fmt.Fprintf(w, "Odds: %s\n", strings.Join(odds, ", "))
+	if opportunity.Bookmaker == "" {
+		fmt.Fprintf(w, "Bookmakers: %s\n", strings.Join(bookmakers, ", "))
+	}
+	if len(opportunity.Links) > 0 {
+		fmt.Fprintf(w, "Links: %s\n", strings.Join(links, ", "))
+	}
+	if opportunity.Currency != "" {
+		fmt.Fprintf(w, "Stakes (%s): %s\n", opportunity.Currency, strings.Join(stakes, ", "))
+	} else {
+		fmt.Fprintf(w, "Stakes: %s\n", strings.Join(stakes, ", "))
+	}
+	if len(localStakes) > 0 {
+		fmt.Fprintf(w, "Local stakes: %s\n", strings.Join(localStakes, ", "))
+	}
+	if opportunity.ScaledFrom > 0 {
+		fmt.Fprintf(w, "Position scaled from %.*f to %.*f to fit stake and payout limits\n",
+			out.Precision, opportunity.ScaledFrom, out.Precision, opportunity.TotalBet)
+	}
+	// Fair odds only make sense for a single market's outcomes
+	if out.ShowFair && opportunity.Market == "" {
+		fair := fairStakes(opportunity.Odds, opportunity.TotalBet)
+		var fairLegs []string
+		for _, leg := range opportunityLegs(opportunity) {
+			fairLegs = append(fairLegs, fmt.Sprintf("%s: %.*f", outcomeLabel(labels, leg.Outcome), out.Precision, fair.Stake(leg.Outcome)))
+		}
+		fmt.Fprintf(w, "Fair stakes: %s, paying back %.*f at fair odds for no profit\n",
+			strings.Join(fairLegs, ", "), out.Precision, opportunity.TotalBet)
+	}
+	// Break-even odds assume straight bets on a single market's outcomes
+	if out.Sensitivity && opportunity.Market == "" {
+		thresholds := sensitivity(opportunity)
+		var breakEven []string
+		for _, leg := range opportunityLegs(opportunity) {
+			threshold := thresholds[leg.Outcome]
+			breakEven = append(breakEven, fmt.Sprintf("%s: %.*f (%.*f%% drop)", outcomeLabel(labels, leg.Outcome),
+				out.OddsPrecision, threshold, out.Precision, (1-threshold/leg.Odds)*100))
+		}
+		fmt.Fprintf(w, "Break-even odds: %s\n", strings.Join(breakEven, ", "))
+	}
+	profit := fmt.Sprintf("Guaranteed profit: %.*f", out.Precision, opportunity.GuaranteedProfit)
+	fmt.Fprintln(w, colorProfit(profit, opportunity.GuaranteedProfit, out.Color))
+	fmt.Fprintf(w, "Return on capital: %.*f%%\n", out.Precision, opportunity.ReturnOnCapital*100)
+	fmt.Fprintln(w)
+}
+
+// Define the options applied to detected arbitrage opportunities
+type scanOptions struct {
+	TotalBet float64
+	// Arbitrage percentage an opportunity must fall below. A lower threshold
+	// is safer against odds drift but finds rarer opportunities.
+	ArbThreshold float64
+	RoundTo      float64
+	Rounding     RoundingMode
+	Bankroll     float64
+	Verify       bool
+	// Most opportunities to report, keeping the most profitable; zero
+	// reports all
+	MaxResults int
+
+	MaxLegStake float64
+	ScaleToFit  bool
+	// Largest total a position may be scaled up to so every leg meets its
+	// bookmaker's minimum stake; zero for no limit
+	MaxTotalBet float64
+
+	BestOdds BestOddsStrategy
+
+	// Order opportunities are reported in
+	Sort SortKey
+
+	// Evaluate fixtures without a sport that no bookmaker quotes a draw for
+	// as two-way markets instead of skipping them for the missing leg
+	TwoWayWithoutDraw bool
+
+	// Base currency for reported amounts, empty when currencies are not in
+	// use, and each foreign-currency bookmaker's rate from it
+	BaseCurrency string
+	Currencies   map[string]localCurrency
+
+	// Filters every opportunity must pass, applied in order once its stakes
+	// are final
+	Filters []OpportunityFilter
+}
+
+// Report whether every leg of an opportunity is within the odds range
+func legsWithinOddsRange(opportunity ArbitrageOpportunity, minOdds, maxOdds float64) bool {
+	for _, leg := range opportunityLegs(opportunity) {
+		if (minOdds > 0 && leg.Odds < minOdds) || (maxOdds > 0 && leg.Odds > maxOdds) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply scan options to a detected opportunity, reporting whether it should
+// still be reported
+func applyScanOptionsTo(opportunity ArbitrageOpportunity, opts scanOptions) (ArbitrageOpportunity, bool) {
+	opportunity, fits := fitMaxLegStake(opportunity, opts.MaxLegStake, opts.ScaleToFit)
+	if !fits {
+		return opportunity, false
+	}
+	if opportunity, fits = fitMaxPayouts(opportunity); !fits {
+		return opportunity, false
+	}
+	if opportunity, fits = fitMaxStakes(opportunity); !fits {
+		return opportunity, false
+	}
+	if opportunity, fits = fitMinStakes(opportunity, opts.MaxTotalBet, opts.MaxLegStake); !fits {
+		warn("game %s: %v", opportunity.GameID, errInfeasibleMinStakes)
+		return opportunity, false
+	}
+	opportunity = roundStakes(opportunity, opts.RoundTo, opts.Rounding)
+	opportunity.ReturnOnCapital = returnOnCapital(opportunity, opportunity.TotalBet)
+	if opts.Verify && !verifyOpportunity(opportunity) {
+		return opportunity, false
+	}
+	if !passesFilters(opportunity, opts.Filters) {
+		return opportunity, false
+	}
+	return localizeStakes(opportunity, opts.BaseCurrency, opts.Currencies), true
+}
+
+// Apply scan options to detected opportunities, dropping those that no
+// longer qualify
+func applyScanOptions(opportunities []ArbitrageOpportunity, opts scanOptions) []ArbitrageOpportunity {
+	var kept []ArbitrageOpportunity
+	for _, opportunity := range opportunities {
+		if opportunity, ok := applyScanOptionsTo(opportunity, opts); ok {
+			kept = append(kept, opportunity)
+		}
+	}
+	return kept
+}
+
+// Find arbitrage opportunities among a list of games, recording the reported
+// ones in metrics when set
+func findArbitrageOpportunities(ctx context.Context, w io.Writer, bookmakers []Bookmaker, opts scanOptions, out outputOptions, metrics *ScanMetrics) int {
+	detected, partial := detectArbitrageOpportunities(ctx, bookmakers, opts)
+	return len(reportArbitrageOpportunities(w, bookmakers, applyScanOptions(detected, opts), partial, opts, out, metrics))
+}
+
+// Print the opportunities a scan found, with scan options already applied,
+// after the bankroll selection and result cap. The reported ones are
+// recorded in metrics when set and returned.
+func reportArbitrageOpportunities(w io.Writer, bookmakers []Bookmaker, opportunities []ArbitrageOpportunity, partial bool, opts scanOptions, out outputOptions, metrics *ScanMetrics) []ArbitrageOpportunity {
+	if opts.Bankroll > 0 {
+		opportunities = selectOpportunities(opportunities, opts.Bankroll)
+	}
+	opportunities, omitted := limitOpportunities(opportunities, opts.MaxResults)
+	if out.GroupBySport {
+		printOpportunitiesBySport(w, opportunities, out)
+	} else {
+		sortOpportunities(opportunities, opts.Sort)
+		for _, opportunity := range opportunities {
+			printArbitrageOpportunity(w, opportunity, out)
+		}
+	}
+	if omitted > 0 {
+		warn("%d opportunities omitted by -max-results", omitted)
+		fmt.Fprintf(w, "Reported the %d most profitable of %d opportunities\n", len(opportunities), len(opportunities)+omitted)
+	}
+	if opts.Bankroll > 0 {
+		profit, capital := selectionTotals(opportunities)
+		fmt.Fprintf(w, "Selected %d opportunities using %.*f of %.*f bankroll for %.*f guaranteed profit\n",
+			len(opportunities), out.Precision, capital, out.Precision, opts.Bankroll, out.Precision, profit)
+	}
+	if suspended := suspendedLegs(bookmakers); suspended > 0 {
+		fmt.Fprintf(w, "Skipped %d suspended legs\n", suspended)
+	}
+	if partial {
+		warn(partialResultsWarning)
+		fmt.Fprintln(w, "Partial results: detection stopped at the deadline")
+	}
+	for _, opportunity := range opportunities {
+		metrics.add(opportunity)
+	}
+	if metrics != nil && partial {
+		metrics.Partial = true
+	}
+	return opportunities
+}
+
+// Find arbitrage opportunities within each bookmaker's own odds, recording
+// them in metrics when set and returning them
+func findIntraBookmakerArbitrageOpportunities(w io.Writer, bookmakers []Bookmaker, opts scanOptions, out outputOptions, metrics *ScanMetrics) []ArbitrageOpportunity {
+	var found []ArbitrageOpportunity
+	for _, bookmaker := range bookmakers {
+		opportunities := applyScanOptions(findIntraBookmakerArbitrage(bookmaker, opts.TotalBet, opts.ArbThreshold), opts)
+		sortOpportunities(opportunities, opts.Sort)
+		for _, opportunity := range opportunities {
+			printArbitrageOpportunity(w, opportunity, out)
+			metrics.add(opportunity)
+		}
+		found = append(found, opportunities...)
+	}
+	return found
+}
+
+// Process exit codes. A scan exits with exitOpportunities when it reports at
+// least one opportunity so scripts can tell the outcomes apart without
+// parsing output. -validate-only exits with exitInvalidData when the data
+// has issues.
+const (
+	exitNoOpportunities = 0
+	exitError           = 1
+	exitInvalidData     = 2
+	exitOpportunities   = 10
+)
+
+// Return the exit code for a scan that reported found opportunities
+func scanExitCode(found int) int {
+	if found > 0 {
+		return exitOpportunities
+	}
+	return exitNoOpportunities
+}
+
+// Parse flags and run the requested command, returning the process exit code
+func run() int {
+	filename := flag.String("file", "bookmakers.json", "Bookmakers data file (.json, .csv or .xlsx), generated when missing")
+	workers := flag.Int("workers", runtime.NumCPU(), "Number of files read concurrently when merging")
+	numBookmakers := flag.Int("bookmakers", 100, "Number of bookmakers to generate")
+	numGamesPerBookmaker := flag.Int("games", 10000, "Number of games per bookmaker to generate")
+	intra := flag.Bool("intra", false, "Also look for arbitrage within each bookmaker's own odds")
+	roundTo := flag.Float64("round-to", 0, "Round stakes to multiples of this amount (0 disables)")
+	rounding := flag.String("rounding", "down", "Stake rounding mode: down, nearest or up")
+	bankroll := flag.Float64("bankroll", 0, "Only report the opportunities that best use this bankroll (0 reports all)")
+	distribution := flag.Int("distribution", 0, "Print a histogram of arbitrage percentages with this many buckets")
+	verify := flag.Bool("verify", true, "Only report opportunities where every outcome independently breaks even or better")
+	maxLegStake := flag.Float64("max-leg-stake", 0, "Exclude opportunities where any single leg's stake exceeds this amount (0 disables)")
+	scaleToFit := flag.Bool("scale-to-fit", false, "Scale positions down to fit -max-leg-stake instead of excluding them")
+	format := flag.String("format", "text", "Output format: text, json, ndjson or markdown")
+	configFile := flag.String("config", "", "JSON file with per-bookmaker settings such as reliability")
+	minReliability := flag.Float64("min-reliability", 0, "Exclude opportunities with a leg at a bookmaker less reliable than this (0 to 1)")
+	bookReport := flag.Bool("book-report", false, "Print bookmakers ranked by how often they offer the best odds")
+	lenient := flag.Bool("lenient", false, "Accept JSON files with trailing data after the bookmakers array, with a warning")
+	precision := flag.Int("precision", defaultOutputOptions.Precision, "Decimal places shown for stakes and profit")
+	oddsPrecision := flag.Int("odds-precision", defaultOutputOptions.OddsPrecision, "Decimal places shown for odds")
+	color := flag.Bool("color", true, "Highlight profit in text output when writing to a terminal")
+	bestOddsStrategy := flag.String("best-odds", "auto", "Best odds aggregation strategy: auto picks map, or parallel for very large datasets on several CPUs; map, sorted or parallel force one")
+	split := flag.Int("split", 0, "Split -file into this many shards by bookmaker and exit")
+	splitPattern := flag.String("split-pattern", "out-%d.json", "File name pattern for -split shards, with %d for the shard index")
+	splitDir := flag.String("split-bookmakers", "", "Write each bookmaker in -file to its own JSON file in this directory and exit")
+	minOdds := flag.Float64("min-odds", 0, "Exclude opportunities with any selected leg below these odds (0 disables)")
+	maxOdds := flag.Float64("max-odds", 0, "Exclude opportunities with any selected leg above these odds (0 disables)")
+	probabilitiesFile := flag.String("probabilities", "", "JSON file of our own outcome probabilities by game ID; ranks outcomes by expected value")
+	sheet := flag.String("sheet", "", "Only read this sheet of an .xlsx file (default reads every sheet)")
+	arbThreshold := flag.Float64("arb-threshold", 1, "Only report opportunities with an arbitrage percentage below this; lower is safer against odds drift but rarer")
+	benchmarkData := flag.String("benchmark-data", "", "Write seeded benchmark datasets (small, medium, large, comma separated, or all) to benchmark-<preset>.json and exit")
+	baseCurrency := flag.String("currency", "", "Base currency for reported amounts when bookmakers quote in different currencies, e.g. USD")
+	quiet := flag.Bool("quiet", false, "Print nothing, not even validation warnings, unless opportunities are found")
+	doctor := flag.Bool("doctor", false, "Check that detection works end to end on a planted arbitrage, print PASS or FAIL and exit")
+	drawNoBet := flag.Bool("dnb", false, "Also look for draw no bet markets hedged with the draw at another bookmaker")
+	useMmap := flag.Bool("mmap", false, "Memory-map JSON input files instead of reading them, for multi-gigabyte dumps")
+	maxResults := flag.Int("max-results", 0, "Report at most this many opportunities, keeping the most profitable (0 reports all)")
+	excludeFile := flag.String("exclude", "", "JSON file mapping game IDs to reasons; opportunities on those fixtures are not reported")
+	profitDistribution := flag.Int("profit-distribution", 0, "Print a histogram of guaranteed profit across reported opportunities with this many buckets")
+	source := flag.String("source", "file", "Where to read odds from: file, or grpc to stream them from -grpc-addr")
+	grpcAddr := flag.String("grpc-addr", "localhost:50051", "Address of the gRPC odds service used with -source grpc")
+	grpcAttempts := flag.Int("grpc-attempts", 5, "Attempts to stream odds from the gRPC service before giving up")
+	oddsBasis := flag.String("odds-basis", "decimal", "Basis of the odds in input files: decimal (including stake), net (excluding stake, converted as net + 1) or probability (converted as 1/p)")
+	diff := flag.Bool("diff", false, "Compare two bookmaker files given as arguments, old then new, print what changed and exit")
+	totalBet := flag.Float64("total-bet", 100, "Total amount to stake on each opportunity")
+	ioAttempts := flag.Int("io-attempts", 3, "Attempts to read or write a data file before giving up on transient filesystem errors")
+	ioRetryDelay := flag.Duration("io-retry-delay", 100*time.Millisecond, "Delay before the first retry of a data file read or write, doubling after each attempt")
+	names := flag.String("names", "random", "How generated teams are named: random words, or realistic teams and leagues from a bundled list")
+	excludeCorrelated := flag.Bool("exclude-correlated", false, "Exclude opportunities combining correlated markets instead of warning about them")
+	sampleRate := flag.Float64("sample-rate", 1, "Scan only a random sample of fixtures, each included with this probability (0-1), drawn from -seed when set")
+	stakePlanGame := flag.String("stake-plan", "", "Print only the best odds, stakes and profit for this game ID, using -total-bet, and exit")
+	sortKey := flag.String("sort", "game", "Order of reported opportunities: game, profit, or roi for return on capital")
+	sharpBook := flag.String("sharp-book", "", "Treat this bookmaker as the true line and list value bets at other bookmakers beating its fair odds")
+	valueEdge := flag.Float64("value-edge", 0.02, "Smallest edge over the sharp line's fair odds reported as a value bet, e.g. 0.02 for 2%")
+	gamesFile := flag.String("games-file", "", "File of game IDs, one per line, to restrict the scan to")
+	publish := flag.String("publish", "", "Publish opportunities as JSON to this NATS server, e.g. nats://localhost:4222, instead of writing them to stdout")
+	publishSubject := flag.String("publish-subject", "arbitrage.opportunities", "NATS subject -publish sends opportunities on")
+	migrateTimes := flag.Bool("migrate-event-times", false, "Rewrite the files given as arguments, or -file, with event times in RFC3339 and exit")
+	deadline := flag.Duration("deadline", 0, "Stop detection after this long, e.g. 5s, and report the opportunities found so far as partial results (0 disables)")
+	auditFixtures := flag.Int("rounding-audit", 0, "Generate this many fixtures' odds, from -seed when set, report those whose arbitrage status differs between truncated and rounded odds and exit")
+	kellyFraction := flag.Float64("kelly", 0, "Size arbitrages and -sharp-book value bets as a portfolio with this fraction of Kelly, e.g. 0.5, within -bankroll (0 disables)")
+	minLead := flag.Duration("min-lead", -1, "Exclude games already started or starting within this long from now, e.g. 5m, as too late to bet (negative disables)")
+	recursive := flag.Bool("recursive", false, "Also read JSON files in subdirectories of directories given as arguments")
+	pushFile := flag.String("push-probabilities", "", "JSON file of per-outcome push probabilities by game ID, for handicap and totals lines; -stake-plan also prints push-adjusted stakes")
+	flag.IntVar(&jsonOddsPrecision, "json-odds-precision", jsonOddsPrecision, "Decimal places odds are rounded to in JSON reports; data files keep full precision (negative leaves them unrounded)")
+	cpuProfile := flag.String("cpuprofile", "", "Write a CPU profile of the scan to this file, for go tool pprof")
+	memProfile := flag.String("memprofile", "", "Write a heap profile to this file once the scan is done, for go tool pprof")
+	bestMarketGame := flag.String("best-market", "", "Print the most profitable arbitrage on this game ID across its match result and draw no bet markets, and exit")
+	requireAllLegs := flag.Bool("require-all-legs", true, "Skip fixtures unless every outcome has a quote somewhere; when false, fixtures without a sport and no draw quote are treated as two-way markets")
+	dryRun := flag.Bool("dry-run", false, "Print the bookmaker and game counts and estimated size -file would be generated with, without generating it, and exit")
+	showFair := flag.Bool("show-fair", false, "Also print the stakes a perfectly efficient market would split each total bet into at de-vigged fair odds, for zero profit")
+	patchFile := flag.String("patch", "", "JSON array of {bookmaker, game_id, odds} updates to apply to -file, which is rewritten atomically, and exit")
+	showSensitivity := flag.Bool("sensitivity", false, "Also print the lowest odds each leg can drop to, with the others unchanged, before the arbitrage disappears")
+	groupBySport := flag.Bool("group-by-sport", false, "Print text output in sections per sport, in sport order, with each section sorted by profit")
+	validateOnly := flag.Bool("validate-only", false, "Validate the files given as arguments, or -file, print every issue and exit, non-zero when any are found")
+	drawOddsMin := flag.Float64("draw-odds-min", 0, "Flag draw odds below this as a likely data error, e.g. 2 (0 disables)")
+	drawOddsMax := flag.Float64("draw-odds-max", 0, "Flag draw odds above this as a likely data error such as mis-mapped columns, e.g. 15 (0 disables)")
+	metricsFile := flag.String("metrics-file", "", "After the scan, write its metrics in OpenMetrics text format to this file, e.g. for the node_exporter textfile collector")
+	nearArbCeiling := flag.Float64("near-arb", 0, "Also list near misses with an arbitrage percentage from 1 up to this ceiling, e.g. 1.03, and the odds each leg needs (0 disables)")
+	maxTotalBet := flag.Float64("max-total-bet", 0, "Largest total a position may be scaled up to so every leg meets its bookmaker's min_stake; larger ones are infeasible (0 disables)")
+	alertBelow := flag.Float64("alert-below", 0, "Replay bookmaker files given as arguments, oldest first, print an alert each time a fixture's arbitrage percentage crosses below this, e.g. 1, and exit (0 disables)")
+	seed := flag.Int64("seed", 0, "Seed for deterministic data generation (0 generates random data)")
+	serve := flag.String("serve", "", "Serve scans over HTTP on this address, e.g. :8080")
+	maxMemoryMB := flag.Uint64("max-memory", 2048, "Refuse to generate data estimated to need more megabytes than this (0 disables)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [file ...]\n\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "When files are given they are merged by bookmaker name instead of using -file. Directories are read for their .json files.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	roundingMode, err := parseRoundingMode(*rounding)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return exitError
+	}
+	strategy, err := parseBestOddsStrategy(*bestOddsStrategy)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return exitError
+	}
+	basis, err := parseOddsBasis(*oddsBasis)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return exitError
+	}
+	order, err := parseSortKey(*sortKey)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return exitError
+	}
+	nameMode, err := parseNameMode(*names)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return exitError
+	}
+	if *source != "file" && *source != "grpc" {
+		fmt.Println("Error: unknown source", *source)
+		return exitError
+	}
+	if *format != "text" && *format != "json" && *format != "ndjson" && *format != "markdown" {
+		fmt.Println("Error: unknown format", *format)
+		return exitError
+	}
+	opts := scanOptions{
+		TotalBet:     *totalBet,
+		ArbThreshold: *arbThreshold,
+		RoundTo:      *roundTo,
+		Rounding:     roundingMode,
+		Bankroll:     *bankroll,
+		Verify:       *verify,
+		MaxResults:   *maxResults,
+
+		MaxLegStake: *maxLegStake,
+		ScaleToFit:  *scaleToFit,
+		MaxTotalBet: *maxTotalBet,
+
+		BestOdds: strategy,
+		Sort:     order,
+
+		TwoWayWithoutDraw: !*requireAllLegs,
+	}
+	if *minReliability > 0 {
+		opts.Filters = append(opts.Filters, MinReliability(*minReliability))
+	}
+	if *minOdds > 0 || *maxOdds > 0 {
+		opts.Filters = append(opts.Filters, OddsRange(*minOdds, *maxOdds))
+	}
+	opts.Filters = append(opts.Filters, CorrelatedMarkets(defaultCorrelationRules, *excludeCorrelated))
+	if err := checkTotalBet(*totalBet); err != nil {
+		fmt.Println("Error: -total-bet:", err)
+		return exitError
+	}
+	if *kellyFraction < 0 || *kellyFraction > 1 || (*kellyFraction > 0 && !(*bankroll > 0)) {
+		fmt.Println("Error: -kelly must be between 0 and 1 and needs a -bankroll")
+		return exitError
+	}
+	if *sampleRate < 0 || *sampleRate > 1 {
+		fmt.Println("Error: -sample-rate must be between 0 and 1")
+		return exitError
+	}
+	if *arbThreshold <= 0 || *arbThreshold > 1 {
+		fmt.Println("Error: -arb-threshold must be above 0 and at most 1")
+		return exitError
+	}
+	if *precision < 0 || *oddsPrecision < 0 {
+		fmt.Println("Error: precision must not be negative")
+		return exitError
+	}
+	out := outputOptions{
+		Precision:     *precision,
+		OddsPrecision: *oddsPrecision,
+		Color:         *color && isTerminal(os.Stdout),
+		ShowFair:      *showFair,
+		Sensitivity:   *showSensitivity,
+		GroupBySport:  *groupBySport,
+	}
+	retry := retryPolicy{MaxAttempts: *ioAttempts, BaseDelay: *ioRetryDelay}
+	drawRange := DrawOddsRange{Min: *drawOddsMin, Max: *drawOddsMax}
+	loadOpts := loadOptions{Lenient: *lenient, Sheet: *sheet, Mmap: *useMmap, OddsBasis: basis, Retry: retry}
+	var cfg Config
+	if *configFile != "" {
+		if cfg, err = loadConfig(*configFile); err != nil {
+			fmt.Println("Error reading config:", err)
+			return exitError
+		}
+	}
+
+	if *excludeFile != "" {
+		exclusions, err := loadExclusionList(*excludeFile)
+		if err != nil {
+			fmt.Println("Error reading exclusion list:", err)
+			return exitError
+		}
+		opts.Filters = append(opts.Filters, ExcludeFixtures(exclusions))
+	}
+
+	if *doctor {
+		if !runDoctor(os.Stdout) {
+			return exitError
+		}
+		return exitNoOpportunities
+	}
+
+	if *split > 0 {
+		if err := splitBookmakersFile(*filename, *split, *splitPattern); err != nil {
+			fmt.Println("Error splitting bookmakers file:", err)
+			return exitError
+		}
+		fmt.Printf("Split %s into %d shards\n", *filename, *split)
+		return exitNoOpportunities
+	}
+
+	if *migrateTimes {
+		files := flag.Args()
+		if len(files) == 0 {
+			files = []string{*filename}
+		}
+		for _, file := range files {
+			converted, unparsed, err := migrateEventTimes(file, loadOpts, retry)
+			if err != nil {
+				fmt.Println("Error migrating event times:", err)
+				return exitError
+			}
+			fmt.Printf("%s: converted %d event times, %d could not be parsed\n", file, converted, unparsed)
+		}
+		return exitNoOpportunities
+	}
+
+	if *patchFile != "" {
+		patch, err := loadOddsPatch(*patchFile)
+		if err != nil {
+			fmt.Println("Error reading patch:", err)
+			return exitError
+		}
+		applied, err := patchBookmakersFile(*filename, patch, loadOpts, retry)
+		if err != nil {
+			fmt.Println("Error patching bookmakers:", err)
+			return exitError
+		}
+		fmt.Printf("%s: applied %d odds updates\n", *filename, applied)
+		return exitNoOpportunities
+	}
+
+	if *auditFixtures > 0 {
+		auditSeed := *seed
+		if auditSeed == 0 {
+			auditSeed = time.Now().UnixNano()
+		}
+		printRoundingAudit(os.Stdout, roundingAudit(*auditFixtures, auditSeed), *auditFixtures, out)
+		return exitNoOpportunities
+	}
+
+	if *splitDir != "" {
+		// Copy the bookmakers as written, so the files hold exactly what the
+		// input did
+		splitOpts := loadOpts
+		splitOpts.OddsBasis = OddsDecimal
+		splitOpts.RawEventTimes = true
+		bookmakers, err := loadBookmakers(*filename, splitOpts)
+		if err != nil {
+			fmt.Println("Error reading bookmakers from file:", err)
+			return exitError
+		}
+		files, err := writeBookmakersSeparately(bookmakers, *splitDir)
+		if err != nil {
+			fmt.Println("Error writing bookmaker files:", err)
+			return exitError
+		}
+		fmt.Printf("Wrote %d bookmaker files to %s\n", len(files), *splitDir)
+		return exitNoOpportunities
+	}
+
+	if *benchmarkData != "" {
+		files, err := writeBenchmarkData(*benchmarkData, *maxMemoryMB)
+		for _, file := range files {
+			fmt.Println("Wrote", file)
+		}
+		if err != nil {
+			fmt.Println("Error writing benchmark data:", err)
+			return exitError
+		}
+		return exitNoOpportunities
+	}
+
+	if *validateOnly {
+		files, err := expandBookmakerPaths(flag.Args(), *recursive)
+		if err != nil {
+			fmt.Println("Error listing files:", err)
+			return exitError
+		}
+		if len(files) == 0 {
+			files = []string{*filename}
+		}
+		if validateFiles(os.Stdout, files, loadOpts, drawRange) > 0 {
+			return exitInvalidData
+		}
+		return exitNoOpportunities
+	}
+
+	if *diff {
+		if flag.NArg() != 2 {
+			fmt.Println("Error: -diff needs an old and a new bookmaker file")
+			return exitError
+		}
+		old, err := loadBookmakers(flag.Arg(0), loadOpts)
+		if err != nil {
+			fmt.Println("Error reading old bookmakers file:", err)
+			return exitError
+		}
+		current, err := loadBookmakers(flag.Arg(1), loadOpts)
+		if err != nil {
+			fmt.Println("Error reading new bookmakers file:", err)
+			return exitError
+		}
+		changes := diffBookmakers(old, current)
+		if *format == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(changes); err != nil {
+				fmt.Println("Error writing diff:", err)
+				return exitError
+			}
+			return exitNoOpportunities
+		}
+		printBookmakerDiff(os.Stdout, changes, out)
+		return exitNoOpportunities
+	}
+
+	if *alertBelow > 0 {
+		if flag.NArg() < 2 {
+			fmt.Println("Error: -alert-below needs at least two bookmaker files, oldest first")
+			return exitError
+		}
+		engine := newAlertEngine(arbitrageBelowRule(*alertBelow))
+		var alerts []Alert
+		for _, snapshotFile := range flag.Args() {
+			snapshotBookmakers, err := loadBookmakers(snapshotFile, loadOpts)
+			if err != nil {
+				fmt.Println("Error reading bookmakers file:", err)
+				return exitError
+			}
+			snapshot := Snapshot{Bookmakers: snapshotBookmakers}
+			if info, err := os.Stat(snapshotFile); err == nil {
+				snapshot.TakenAt = info.ModTime().UTC()
+			}
+			alerts = append(alerts, engine.Evaluate(snapshot)...)
+		}
+		if *format == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(alerts); err != nil {
+				fmt.Println("Error writing alerts:", err)
+				return exitError
+			}
+		} else {
+			printAlerts(os.Stdout, alerts, out)
+		}
+		return scanExitCode(len(alerts))
+	}
 
 	var bookmakers []Bookmaker
-	var err error
 
-	if _, err = os.Stat(filename); os.IsNotExist(err) {
-		bookmakers = generateBookmakers(numBookmakers, numGamesPerBookmaker)
-		if err := writeBookmakersToFile(bookmakers, filename); err != nil {
+	if *source == "grpc" {
+		bookmakers, err = readBookmakersFromGRPC(context.Background(), grpcSourceOptions{
+			Address:     *grpcAddr,
+			MaxAttempts: *grpcAttempts,
+			BaseDelay:   500 * time.Millisecond,
+		})
+		if err != nil {
+			fmt.Println("Error reading bookmakers from gRPC:", err)
+			return exitError
+		}
+		normalizeEventTimes(bookmakers)
+	} else if flag.NArg() > 0 {
+		files, err := expandBookmakerPaths(flag.Args(), *recursive)
+		if err == nil {
+			bookmakers, err = loadMergedBookmakers(context.Background(), files, *workers, loadOpts)
+		}
+		if err != nil {
+			fmt.Println("Error merging bookmaker files:", err)
+			return exitError
+		}
+	} else if _, err = os.Stat(*filename); os.IsNotExist(err) {
+		if *dryRun {
+			printGenerationDryRun(os.Stdout, *filename, *numBookmakers, *numGamesPerBookmaker, nameMode, *maxMemoryMB)
+			return exitNoOpportunities
+		}
+		if err := checkGenerationSize(*numBookmakers, *numGamesPerBookmaker, *maxMemoryMB); err != nil {
+			fmt.Println("Error generating bookmakers:", err)
+			return exitError
+		}
+		bookmakers = generateBookmakersFromSeed(*numBookmakers, *numGamesPerBookmaker, *seed, nameMode)
+		if err := saveBookmakers(bookmakers, *filename, retry); err != nil {
 			fmt.Println("Error writing bookmakers to file:", err)
-			return
+			return exitError
 		}
+		normalizeEventTimes(bookmakers)
+	} else if *dryRun {
+		fmt.Printf("Dry run: %s exists and would be read, nothing would be generated\n", *filename)
+		return exitNoOpportunities
 	} else {
-		bookmakers, err = readBookmakersFromFile(filename)
+		bookmakers, err = loadBookmakers(*filename, loadOpts)
 		if err != nil {
 			fmt.Println("Error reading bookmakers from file:", err)
-			return
+			return exitError
 		}
 	}
 
-	findArbitrageOpportunities(bookmakers)
+	if !*quiet {
+		printValidationIssues(validateBookmakers(bookmakers, drawRange))
+		for _, fixture := range fixturesMissingLegs(bookmakers, opts.TwoWayWithoutDraw) {
+			warn("game %s: skipped, no bookmaker quotes %s", fixture.GameID, strings.Join(fixture.Outcomes, ", "))
+		}
+	}
+	bookmakers = removeSelfMatches(bookmakers)
+	applyConfig(bookmakers, cfg)
+	if *gamesFile != "" {
+		gameIDs, err := loadGameIDs(*gamesFile)
+		if err != nil {
+			fmt.Println("Error reading games file:", err)
+			return exitError
+		}
+		var missing []string
+		bookmakers, missing = restrictToGames(bookmakers, gameIDs)
+		if !*quiet {
+			for _, gameID := range missing {
+				warn("game %s: %v", gameID, ErrGameNotFound)
+			}
+		}
+	}
+	if *stakePlanGame != "" {
+		plan, err := stakePlan(bookmakers, *stakePlanGame, opts.TotalBet)
+		if err != nil {
+			fmt.Println("Error planning stakes:", err)
+			return exitError
+		}
+		printStakePlan(os.Stdout, plan, out)
+		if *pushFile != "" {
+			pushes, err := loadProbabilities(*pushFile)
+			if err != nil {
+				fmt.Println("Error reading push probabilities:", err)
+				return exitError
+			}
+			position, err := pushStakes(plan, pushes[plan.GameID], opts.TotalBet)
+			if err != nil {
+				fmt.Println("Error adjusting stakes for pushes:", err)
+				return exitError
+			}
+			printPushPosition(os.Stdout, position, out)
+		}
+		return exitNoOpportunities
+	}
+	if *minLead >= 0 {
+		var excluded int
+		bookmakers, excluded = excludeGamesStartingBefore(bookmakers, time.Now().Add(*minLead))
+		if excluded > 0 && !*quiet {
+			warn("excluded %d fixtures starting within %v", excluded, *minLead)
+		}
+	}
+	if *sampleRate < 1 {
+		sampleSeed := *seed
+		if sampleSeed == 0 {
+			sampleSeed = time.Now().UnixNano()
+		}
+		var kept, total int
+		bookmakers, kept, total = sampleFixtures(bookmakers, *sampleRate, sampleSeed)
+		if !*quiet {
+			warn("results are sampled: scanning %d of %d fixtures at -sample-rate %v", kept, total, *sampleRate)
+		}
+	}
+	if *baseCurrency != "" {
+		opts.BaseCurrency = *baseCurrency
+		opts.Currencies, err = bookmakerCurrencies(bookmakers, *baseCurrency, cfg.rateSource())
+		if err != nil {
+			fmt.Println("Error converting currencies:", err)
+			return exitError
+		}
+	}
+
+	if *bestMarketGame != "" {
+		opportunity, err := bestFixtureArbitrage(context.Background(), bookmakers, *bestMarketGame, opts)
+		if err != nil {
+			fmt.Println("Error finding the best market:", err)
+			return exitError
+		}
+		printBestFixtureArbitrage(os.Stdout, opportunity, out)
+		return exitNoOpportunities
+	}
+
+	if *serve != "" {
+		srv := newServer(*filename, bookmakers, cfg, opts, *maxMemoryMB, retry)
+		fmt.Println("Serving on", *serve)
+		if err := http.ListenAndServe(*serve, srv.routes()); err != nil {
+			fmt.Println("Error serving:", err)
+			return exitError
+		}
+		return exitNoOpportunities
+	}
+
+	// In quiet mode the report is held back and only written when something
+	// was found, so cron jobs that mail their output stay silent otherwise
+	var stdout io.Writer = os.Stdout
+	var held bytes.Buffer
+	if *quiet {
+		stdout = &held
+	}
+	// Publishing replaces the report on stdout. A server that cannot be
+	// reached is not fatal: the scan is reported as usual instead.
+	mode := *format
+	var publisher Publisher
+	if *publish != "" {
+		if publisher, err = newNATSPublisher(*publish, *publishSubject); err != nil {
+			warn("publishing to %s: %v, writing opportunities to stdout instead", *publish, err)
+		} else {
+			mode = "publish"
+		}
+	}
+	scanCtx := context.Background()
+	if *deadline > 0 {
+		var cancel context.CancelFunc
+		scanCtx, cancel = context.WithTimeout(scanCtx, *deadline)
+		defer cancel()
+	}
+	stopProfiling, err := startProfiling(*cpuProfile, *memProfile)
+	if err != nil {
+		fmt.Println("Error starting profile:", err)
+		return exitError
+	}
+	defer func() {
+		if err := stopProfiling(); err != nil {
+			warn("writing profile: %v", err)
+		}
+	}()
+	// Metrics summarize the opportunities as they are reported
+	var metrics *ScanMetrics
+	if *metricsFile != "" {
+		metrics = &ScanMetrics{}
+	}
+	found := 0
+	switch mode {
+	case "publish":
+		opportunities := collectOpportunities(scanCtx, bookmakers, opts, *intra)
+		for _, opportunity := range opportunities {
+			metrics.add(opportunity)
+		}
+		recordPartial(scanCtx, metrics)
+		published := publishOpportunities(publisher, opportunities)
+		if err := publisher.Close(); err != nil {
+			warn("publishing to %s: %v", *publish, err)
+		}
+		fmt.Fprintf(stdout, "Published %d of %d opportunities to %s\n", published, len(opportunities), *publishSubject)
+		found = len(opportunities)
+	case "json":
+		if found, err = writeOpportunitiesJSON(scanCtx, stdout, bookmakers, opts, *intra, metrics); err != nil {
+			fmt.Println("Error writing opportunities:", err)
+			return exitError
+		}
+	case "ndjson":
+		if found, err = writeOpportunitiesNDJSON(scanCtx, stdout, bookmakers, opts, *intra, metrics); err != nil {
+			fmt.Println("Error writing opportunities:", err)
+			return exitError
+		}
+	case "markdown":
+		opportunities := collectOpportunities(scanCtx, bookmakers, opts, *intra)
+		if err := writeOpportunitiesMarkdown(stdout, opportunities, out); err != nil {
+			fmt.Println("Error writing opportunities:", err)
+			return exitError
+		}
+		for _, opportunity := range opportunities {
+			metrics.add(opportunity)
+		}
+		recordPartial(scanCtx, metrics)
+		found = len(opportunities)
+	default:
+		// Detection runs once; the listing, portfolio and profit
+		// distribution all share its results so its warnings print once
+		detected, partial := detectArbitrageOpportunities(scanCtx, bookmakers, opts)
+		scanned := applyScanOptions(detected, opts)
+		reported := reportArbitrageOpportunities(stdout, bookmakers, scanned, partial, opts, out, metrics)
+		var intraReported []ArbitrageOpportunity
+		if *intra {
+			intraReported = findIntraBookmakerArbitrageOpportunities(stdout, bookmakers, opts, out, metrics)
+		}
+		found = len(reported) + len(intraReported)
+		if *drawNoBet {
+			opportunities := findDrawNoBetArbitrage(bookmakers, opts)
+			printDrawNoBetArbitrage(stdout, opportunities, out)
+			for _, opportunity := range opportunities {
+				metrics.add(opportunity)
+			}
+			found += len(opportunities)
+		}
+		if *probabilitiesFile != "" {
+			probabilities, err := loadProbabilities(*probabilitiesFile)
+			if err != nil {
+				fmt.Println("Error reading probabilities:", err)
+				return exitError
+			}
+			printOutcomeEVs(stdout, rankOutcomesByEV(bookmakers, probabilities, opts.TotalBet), opts.TotalBet, out)
+		}
+		if *nearArbCeiling > 0 {
+			printNearArbitrage(stdout, findNearArbitrage(bookmakers, *nearArbCeiling), *nearArbCeiling, out)
+		}
+		var valueBets []ValueBet
+		if *sharpBook != "" {
+			valueBets = findValueBets(bookmakers, *sharpBook, *valueEdge)
+			printValueBets(stdout, valueBets, *sharpBook, out)
+		}
+		if *kellyFraction > 0 {
+			// The portfolio sizes every opportunity itself rather than taking
+			// the bankroll selection
+			sized, _ := limitOpportunities(append(append([]ArbitrageOpportunity(nil), scanned...), intraReported...), opts.MaxResults)
+			candidates := append(arbitrageCandidates(sized), valueBetCandidates(valueBets)...)
+			printPortfolio(stdout, sizePortfolio(candidates, *bankroll, *kellyFraction), *bankroll, *kellyFraction, out)
+		}
+		if *bookReport {
+			printBookmakerReport(stdout, bookmakerReport(bookmakers))
+		}
+		if *distribution > 0 {
+			printArbitrageDistribution(stdout, bookmakers, *distribution)
+		}
+		if *profitDistribution > 0 {
+			printProfitDistribution(stdout, append(append([]ArbitrageOpportunity(nil), reported...), intraReported...), *profitDistribution)
+		}
+	}
+	if metrics != nil {
+		metrics.FinishedAt = time.Now()
+		if err := writeOpenMetricsFile(*metricsFile, *metrics); err != nil {
+			fmt.Println("Error writing metrics:", err)
+			return exitError
+		}
+	}
+	if *quiet && found > 0 {
+		if _, err := held.WriteTo(os.Stdout); err != nil {
+			fmt.Println("Error writing opportunities:", err)
+			return exitError
+		}
+	}
+	return scanExitCode(found)
+}
+
+func main() {
+	os.Exit(run())
 }
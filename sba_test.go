@@ -0,0 +1,659 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestOddsMarshalJSONPrecision(t *testing.T) {
+	defer func(precision int) { jsonOddsPrecision = precision }(jsonOddsPrecision)
+	decimals := regexp.MustCompile(`\d+(?:\.(\d+))?`)
+	tests := []struct {
+		precision int
+		odds      Odds
+		want      string
+	}{
+		{precision: 2, odds: Odds{Win: 2.0999999, Draw: 3.456, Lose: 4}, want: `{"win":2.1,"draw":3.46,"lose":4}`},
+		{precision: 0, odds: Odds{Win: 2.5, Draw: 3.4, Lose: 1.2}, want: `{"win":3,"draw":3,"lose":1}`},
+		{precision: 3, odds: Odds{Win: 1.23456, Draw: 0, Lose: 10.0005}, want: `{"win":1.235,"draw":0,"lose":10.001}`},
+		{precision: -1, odds: Odds{Win: 2.0999999, Draw: 3, Lose: 4}, want: `{"win":2.0999999,"draw":3,"lose":4}`},
+	}
+	for _, tt := range tests {
+		jsonOddsPrecision = tt.precision
+		data, err := json.Marshal(tt.odds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("precision %d: Marshal(%+v) = %s, want %s", tt.precision, tt.odds, data, tt.want)
+		}
+		if tt.precision >= 0 {
+			for _, match := range decimals.FindAllStringSubmatch(string(data), -1) {
+				if len(match[1]) > tt.precision {
+					t.Errorf("precision %d: %s has %d decimals", tt.precision, match[0], len(match[1]))
+				}
+			}
+		}
+		// Decoding and encoding again must give the same output
+		var decoded Odds
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		again, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(again) != string(data) {
+			t.Errorf("precision %d: round trip gave %s, want %s", tt.precision, again, data)
+		}
+	}
+}
+
+func TestGameMarshalJSONKeepsFullPrecision(t *testing.T) {
+	game := Game{
+		ID: "g", TeamA: "A", TeamB: "B",
+		Odds:      Odds{Win: 2.0999999, Draw: 3.333333, Lose: 1.0 / 3 * 10},
+		DrawNoBet: &Odds{Win: 1.6666667, Lose: 2.1234},
+		EventAt:   "2024-05-01T18:30:00Z", Sport: "soccer", League: "premier",
+		Metadata: map[string]string{"news": "injury"},
+	}
+	data, err := json.Marshal(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Game
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, game) {
+		t.Errorf("round trip gave %+v, want %+v", decoded, game)
+	}
+	if math.Abs(decoded.Odds.Win-2.0999999) > 0 {
+		t.Errorf("odds were rounded: %s", data)
+	}
+}
+
+// Every field of Game must survive encoding, with available only written for
+// suspended games
+func TestGameMarshalJSONRoundTripsEveryField(t *testing.T) {
+	tests := []struct {
+		name          string
+		available     bool
+		wantAvailable bool
+	}{
+		{"available", true, false},
+		{"suspended", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := Game{ID: "g", TeamA: "A", TeamB: "B", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, EventAt: "e",
+				Sport: "s", League: "l", DrawNoBet: &Odds{Win: 1.9, Lose: 2}, Metadata: map[string]string{"k": "v"},
+				Available: tt.available}
+			data, err := json.Marshal(game)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(data, &fields); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := fields["available"]; ok != tt.wantAvailable {
+				t.Errorf("available written = %v, want %v: %s", ok, tt.wantAvailable, data)
+			}
+			var decoded Game
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decoded, game) {
+				t.Errorf("round trip gave %+v, want %+v", decoded, game)
+			}
+		})
+	}
+}
+
+func TestFindIntraBookmakerArbitrage(t *testing.T) {
+	bookmaker := Bookmaker{
+		Name:        "boosted",
+		Reliability: 0.8,
+		Games: []Game{
+			// Internally an arbitrage: 1/3 + 1/4 + 1/4 < 1
+			{ID: "boost", Odds: Odds{Win: 3, Draw: 4, Lose: 4}, Available: true},
+			{ID: "normal", Odds: Odds{Win: 2, Draw: 3, Lose: 3.5}, Available: true},
+			{ID: "suspended", Odds: Odds{Win: 3, Draw: 4, Lose: 4}, Available: false},
+			{ID: "invalid", Odds: Odds{Win: 3, Draw: 4, Lose: 0.5}, Available: true},
+			{ID: "tennis", Sport: "tennis", Odds: Odds{Win: 2.1, Lose: 2.1}, Available: true},
+		},
+	}
+	opportunities := findIntraBookmakerArbitrage(bookmaker, 100, 1)
+	var ids []string
+	for _, opportunity := range opportunities {
+		ids = append(ids, opportunity.GameID)
+		if opportunity.Bookmaker != "boosted" || opportunity.Reliability != 0.8 {
+			t.Errorf("%s: bookmaker %q reliability %v, want boosted 0.8", opportunity.GameID, opportunity.Bookmaker, opportunity.Reliability)
+		}
+		if !(opportunity.GuaranteedProfit > 0) {
+			t.Errorf("%s: guaranteed profit %v, want positive", opportunity.GameID, opportunity.GuaranteedProfit)
+		}
+	}
+	if want := []string{"boost", "tennis"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("opportunities on %v, want %v", ids, want)
+	}
+	// The threshold applies as in the cross-bookmaker scan
+	if got := findIntraBookmakerArbitrage(bookmaker, 100, 0.9); len(got) != 1 || got[0].GameID != "boost" {
+		t.Errorf("with threshold 0.9 got %+v, want only boost", got)
+	}
+}
+
+func TestDecodeBookmakersJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		lenient   bool
+		wantNames []string
+		wantErr   error
+	}{
+		{name: "array", data: `[{"name":"a"},{"name":"b"}]`, wantNames: []string{"a", "b"}},
+		{name: "trailing whitespace", data: "[{\"name\":\"a\"}]\n\n", wantNames: []string{"a"}},
+		{name: "trailing junk strict", data: `[{"name":"a"}]{"name":"half`, wantErr: errTrailingData},
+		{name: "trailing junk lenient", data: `[{"name":"a"}]{"name":"half`, lenient: true, wantNames: []string{"a"}, wantErr: errTrailingData},
+		{name: "second array lenient", data: `[{"name":"a"}][{"name":"b"}]`, lenient: true, wantNames: []string{"a"}, wantErr: errTrailingData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bookmakers, err := decodeBookmakersJSON([]byte(tt.data), tt.lenient)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			var names []string
+			for _, bookmaker := range bookmakers {
+				names = append(names, bookmaker.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("decoded %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+	if _, err := decodeBookmakersJSON([]byte(`[{"name":`), true); err == nil || errors.Is(err, errTrailingData) {
+		t.Errorf("truncated array error = %v, want a syntax error even when lenient", err)
+	}
+}
+
+func TestLoadLenientTrailingData(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "appended.json")
+	if err := os.WriteFile(filename, []byte(`[{"name":"a","games":[{"id":"g1"}]}]`+"\n"+`[{"name":"b","ga`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadBookmakers(filename, loadOptions{}); !errors.Is(err, ErrParse) {
+		t.Errorf("strict load error = %v, want %v", err, ErrParse)
+	}
+	bookmakers, err := loadBookmakers(filename, loadOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("lenient load error = %v", err)
+	}
+	if len(bookmakers) != 1 || bookmakers[0].Name != "a" || len(bookmakers[0].Games) != 1 {
+		t.Errorf("lenient load = %+v, want bookmaker a with its game", bookmakers)
+	}
+}
+
+func TestPrintArbitrageOpportunityPrecision(t *testing.T) {
+	opportunity := newArbitrageOpportunity("g1", Odds{Win: 2.6, Draw: 3.6, Lose: 4.2}, 100)
+	before := opportunity
+	tests := []struct {
+		name string
+		out  outputOptions
+		want []string
+	}{
+		{
+			name: "two decimals",
+			out:  outputOptions{Precision: 2, OddsPrecision: 2},
+			want: []string{"Odds: Win: 2.60, Draw: 3.60, Lose: 4.20\n", "Stakes: Win: 42.71, Draw: 30.85, Lose: 26.44\n", "Guaranteed profit: 11.05\n"},
+		},
+		{
+			name: "four decimals",
+			out:  outputOptions{Precision: 4, OddsPrecision: 4},
+			want: []string{"Odds: Win: 2.6000, Draw: 3.6000, Lose: 4.2000\n", "Stakes: Win: 42.7119, Draw: 30.8475, Lose: 26.4407\n", "Guaranteed profit: 11.0508\n"},
+		},
+		{
+			name: "separate odds precision",
+			out:  outputOptions{Precision: 4, OddsPrecision: 1},
+			want: []string{"Odds: Win: 2.6, Draw: 3.6, Lose: 4.2\n", "Return on capital: 11.0508%\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			printArbitrageOpportunity(&buf, opportunity, tt.out)
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output lacks %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+	if !reflect.DeepEqual(opportunity, before) {
+		t.Errorf("printing changed the opportunity to %+v", opportunity)
+	}
+}
+
+func TestLegsWithinOddsRange(t *testing.T) {
+	// Best odds of 1.5, 6 and 8 across two bookmakers
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.5, Draw: 5, Lose: 8}, Available: true}}},
+		{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.4, Draw: 6, Lose: 7}, Available: true}}},
+	}
+	detected, _ := detectArbitrageOpportunities(context.Background(), bookmakers, scanOptions{TotalBet: 100, ArbThreshold: 1})
+	if len(detected) != 1 {
+		t.Fatalf("got %d opportunities, want 1", len(detected))
+	}
+	tests := []struct {
+		name             string
+		minOdds, maxOdds float64
+		want             bool
+	}{
+		{name: "no bounds", want: true},
+		{name: "within both bounds", minOdds: 1.5, maxOdds: 8, want: true},
+		{name: "below the minimum", minOdds: 1.6},
+		{name: "above the maximum", maxOdds: 7.5},
+		// The other bookmaker's 7 would fit, but the best leg decides
+		{name: "best leg out of range disqualifies", minOdds: 1.2, maxOdds: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := legsWithinOddsRange(detected[0], tt.minOdds, tt.maxOdds); got != tt.want {
+				t.Errorf("legsWithinOddsRange() = %v, want %v", got, tt.want)
+			}
+			opts := scanOptions{TotalBet: 100, ArbThreshold: 1, Filters: []OpportunityFilter{OddsRange(tt.minOdds, tt.maxOdds)}}
+			if got := len(applyScanOptions(detected, opts)) == 1; got != tt.want {
+				t.Errorf("reported = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameAvailableDefaultsToTrue(t *testing.T) {
+	var games []Game
+	if err := json.Unmarshal([]byte(`[{"id":"g1"},{"id":"g2","available":false},{"id":"g3","available":true}]`), &games); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []bool{true, false, true} {
+		if games[i].Available != want {
+			t.Errorf("game %s available = %v, want %v", games[i].ID, games[i].Available, want)
+		}
+	}
+}
+
+func TestSuspendedBestLegChangesChosenOdds(t *testing.T) {
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 3, Draw: 3.6, Lose: 4.2}, Available: true}}},
+		{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 2.4, Draw: 3.3, Lose: 3.3}, Available: true}}},
+	}
+	for _, strategy := range []BestOddsStrategy{BestOddsMap, BestOddsSorted, BestOddsParallel} {
+		if best := findBestOddsWithStrategy(context.Background(), bookmakers, strategy)["g1"]; best.WinSource != "a" || best.Odds.Win != 3 {
+			t.Fatalf("%s: best win %v at %s, want 3 at a", strategy, best.Odds.Win, best.WinSource)
+		}
+	}
+
+	bookmakers[0].Games[0].Available = false
+	for _, strategy := range []BestOddsStrategy{BestOddsMap, BestOddsSorted, BestOddsParallel} {
+		best := findBestOddsWithStrategy(context.Background(), bookmakers, strategy)["g1"]
+		if best.Odds != bookmakers[1].Games[0].Odds || best.WinSource != "b" || best.DrawSource != "b" || best.LoseSource != "b" {
+			t.Errorf("%s: with a suspended got %+v, want every leg at b", strategy, best)
+		}
+	}
+	if got := suspendedLegs(bookmakers); got != 3 {
+		t.Errorf("suspendedLegs() = %d, want 3", got)
+	}
+
+	var buf strings.Builder
+	findArbitrageOpportunities(context.Background(), &buf, bookmakers, scanOptions{TotalBet: 100, ArbThreshold: 1}, defaultOutputOptions, nil)
+	if buf.String() != "Skipped 3 suspended legs\n" {
+		t.Errorf("output = %q, want only the suspended leg count", buf.String())
+	}
+}
+
+func TestArbThresholdExcludesMarginalArbs(t *testing.T) {
+	// Arbitrage percentages of about 0.99 for g1 and 0.85 for g2
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{
+			{ID: "g1", Odds: Odds{Win: 3, Draw: 3, Lose: 3.1}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 3, Draw: 3.6, Lose: 4.2}, Available: true},
+		}},
+		{Name: "b", Games: []Game{
+			{ID: "g1", Odds: Odds{Win: 2.9, Draw: 3, Lose: 2.5}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 2.6, Draw: 3.3, Lose: 3.3}, Available: true},
+		}},
+	}
+	tests := []struct {
+		threshold float64
+		want      []string
+	}{
+		{threshold: 1, want: []string{"g1", "g2"}},
+		{threshold: 0.98, want: []string{"g2"}},
+		{threshold: 0.8, want: nil},
+	}
+	for _, tt := range tests {
+		detected, _ := detectArbitrageOpportunities(context.Background(), bookmakers, scanOptions{TotalBet: 100, ArbThreshold: tt.threshold})
+		var got []string
+		for _, opportunity := range detected {
+			got = append(got, opportunity.GameID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("at threshold %v got %v, want %v", tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateFixtureThreshold(t *testing.T) {
+	odds := Odds{Win: 2.5, Draw: 4, Lose: 5}
+	if _, ok := evaluateFixture("g1", "", odds, 100, 0.85+1e-9); !ok {
+		t.Error("0.85 percentage rejected at a threshold just above it")
+	}
+	if _, ok := evaluateFixture("g1", "", odds, 100, 0.85-1e-9); ok {
+		t.Error("0.85 percentage accepted at a threshold just under it")
+	}
+}
+
+func FuzzDecodeBookmakersJSON(f *testing.F) {
+	for _, seed := range []string{
+		`[{"name":"a","games":[{"id":"g1","team_a":"A","team_b":"B","odds":{"win":2.5,"draw":3.2,"lose":2.9},"event_at":"2024-05-01 15:00:00"}]}]`,
+		`[{"name":"b","games":[{"id":"g1","sport":"tennis","odds":{"win":1.7,"lose":2.2},"available":false,"draw_no_bet":{"win":1.9,"lose":2}}]}]`,
+		`[{"name":"a","games":[{"id":"g1","metadata":{"league":"x","round":3}}]}] trailing`,
+		`[{"name":"a"}][{"name":"b"}]`,
+		`[{"name":"a","games":[{"odds":{"win":-1,"draw":1e308,"lose":0}}]}]`,
+		`{"name":"not an array"}`,
+		`[`,
+	} {
+		f.Add([]byte(seed), false)
+		f.Add([]byte(seed), true)
+	}
+	f.Fuzz(func(t *testing.T, data []byte, lenient bool) {
+		bookmakers, err := decodeBookmakersJSON(data, lenient)
+		if err != nil && !(lenient && errors.Is(err, errTrailingData)) {
+			if bookmakers != nil {
+				t.Fatalf("failed decode returned bookmakers: %v", err)
+			}
+			return
+		}
+		if !lenient {
+			if _, lenientErr := decodeBookmakersJSON(data, true); lenientErr != nil {
+				t.Fatalf("strict decode succeeded but lenient failed: %v", lenientErr)
+			}
+		}
+		// Data files written from decoded data must read back the same
+		encoded, err := json.Marshal(bookmakers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := decodeBookmakersJSON(encoded, false)
+		if err != nil {
+			t.Fatalf("re-decoding %s: %v", encoded, err)
+		}
+		again, err := json.Marshal(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(again, encoded) {
+			t.Fatalf("round trip changed the data:\n%s\n%s", encoded, again)
+		}
+	})
+}
+
+// Run the command line with args and return its exit code and what it wrote
+// to standard output and standard error
+func runCLI(t *testing.T, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	dir := t.TempDir()
+	outFile, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer outFile.Close()
+	errFile, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer errFile.Close()
+
+	savedArgs, savedFlags, savedStdout, savedStderr := os.Args, flag.CommandLine, os.Stdout, os.Stderr
+	defer func() {
+		os.Args, flag.CommandLine, os.Stdout, os.Stderr = savedArgs, savedFlags, savedStdout, savedStderr
+	}()
+	os.Args = append([]string{"sports-betting-arbitrage"}, args...)
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	os.Stdout, os.Stderr = outFile, errFile
+	code = run()
+
+	out, err := os.ReadFile(outFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	errOut, err := os.ReadFile(errFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code, string(out), string(errOut)
+}
+
+// Write bookmakers to a JSON data file in a temporary directory
+func writeTestData(t *testing.T, bookmakers []Bookmaker) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "bookmakers.json")
+	if err := writeBookmakersToFile(bookmakers, filename); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestScanExitCode(t *testing.T) {
+	tests := []struct {
+		found int
+		want  int
+	}{
+		{found: 0, want: exitNoOpportunities},
+		{found: 1, want: exitOpportunities},
+		{found: 25, want: exitOpportunities},
+	}
+	for _, tt := range tests {
+		if got := scanExitCode(tt.found); got != tt.want {
+			t.Errorf("scanExitCode(%d) = %d, want %d", tt.found, got, tt.want)
+		}
+	}
+}
+
+func TestRunQuietExitCodes(t *testing.T) {
+	// The home team has no name, which is reported as a validation warning
+	noArbitrage := []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1", TeamB: "B", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, Available: true}}},
+		{Name: "b", Games: []Game{{ID: "g1", TeamB: "B", Odds: Odds{Win: 2.1, Draw: 3, Lose: 3.5}, Available: true}}},
+	}
+	noArbitrageFile := writeTestData(t, noArbitrage)
+	arbitrageFile := writeTestData(t, plantedBookmakers())
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr bool
+	}{
+		{name: "quiet without opportunities", args: []string{"-quiet", "-file", noArbitrageFile}, wantCode: exitNoOpportunities},
+		{name: "quiet with opportunities", args: []string{"-quiet", "-file", arbitrageFile}, wantCode: exitOpportunities, wantStdout: "Arbitrage opportunity found for game planted\n"},
+		{name: "without opportunities", args: []string{"-file", noArbitrageFile}, wantCode: exitNoOpportunities, wantStderr: true},
+		{name: "with opportunities", args: []string{"-file", arbitrageFile}, wantCode: exitOpportunities, wantStdout: "Arbitrage opportunity found for game planted\n"},
+		{name: "error", args: []string{"-quiet", "-rounding", "sideways", "-file", arbitrageFile}, wantCode: exitError, wantStdout: "Error:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCLI(t, tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			if tt.wantStdout == "" && stdout != "" || !strings.HasPrefix(stdout, tt.wantStdout) {
+				t.Errorf("stdout = %q, want it to start with %q", stdout, tt.wantStdout)
+			}
+			if (stderr != "") != tt.wantStderr {
+				t.Errorf("stderr = %q", stderr)
+			}
+		})
+	}
+}
+
+func TestRunMaxResultsTruncatesAndWarns(t *testing.T) {
+	var a, b []Game
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprintf("g%d", i)
+		draw := 3.5 + float64(i)/10
+		a = append(a, Game{ID: id, TeamA: "A", TeamB: "B", Odds: Odds{Win: 3, Draw: 3, Lose: 3}, Available: true})
+		b = append(b, Game{ID: id, TeamA: "A", TeamB: "B", Odds: Odds{Win: 2, Draw: draw, Lose: 4}, Available: true})
+	}
+	file := writeTestData(t, []Bookmaker{{Name: "a", Games: a}, {Name: "b", Games: b}})
+
+	code, stdout, stderr := runCLI(t, "-max-results", "2", "-file", file)
+	if code != exitOpportunities {
+		t.Errorf("exit code = %d, want %d", code, exitOpportunities)
+	}
+	if got := strings.Count(stdout, "Arbitrage opportunity found for game"); got != 2 {
+		t.Errorf("reported %d opportunities, want 2", got)
+	}
+	// The richest draw prices make the most profitable arbitrages
+	for _, id := range []string{"g5", "g4"} {
+		if !strings.Contains(stdout, "found for game "+id+"\n") {
+			t.Errorf("stdout does not report %s:\n%s", id, stdout)
+		}
+	}
+	if !strings.Contains(stdout, "Reported the 2 most profitable of 5 opportunities") {
+		t.Errorf("stdout is missing the full count:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "Warning: 3 opportunities omitted by -max-results") {
+		t.Errorf("stderr = %q, want the omitted warning", stderr)
+	}
+}
+
+func TestRunTextModeWarnsOnce(t *testing.T) {
+	var a, b []Game
+	for i := 1; i <= 5; i++ {
+		id := fmt.Sprintf("g%d", i)
+		a = append(a, Game{ID: id, TeamA: "A", TeamB: "B", Odds: Odds{Win: 3, Draw: 3, Lose: 3}, Available: true})
+		b = append(b, Game{ID: id, TeamA: "A", TeamB: "B", Odds: Odds{Win: 2, Draw: 3.5 + float64(i)/10, Lose: 4}, Available: true})
+	}
+	file := writeTestData(t, []Bookmaker{{Name: "a", Games: a}, {Name: "b", Games: b}})
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"listing only", nil},
+		{"with portfolio", []string{"-kelly", "0.5", "-bankroll", "1000"}},
+		{"with profit distribution", []string{"-profit-distribution", "2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, stderr := runCLI(t, append([]string{"-max-results", "2", "-file", file}, tt.args...)...)
+			if got := strings.Count(stderr, "omitted by -max-results"); got != 1 {
+				t.Errorf("warned %d times, want once:\n%s", got, stderr)
+			}
+		})
+	}
+}
+
+func TestRunRejectsInvalidTotalBet(t *testing.T) {
+	file := writeTestData(t, plantedBookmakers())
+	for _, totalBet := range []string{"0", "-50"} {
+		t.Run(totalBet, func(t *testing.T) {
+			code, stdout, _ := runCLI(t, "-total-bet", totalBet, "-file", file)
+			if code != exitError {
+				t.Errorf("exit code = %d, want %d", code, exitError)
+			}
+			if !strings.HasPrefix(stdout, "Error: -total-bet: "+ErrInvalidBet.Error()) {
+				t.Errorf("stdout = %q, want the invalid bet error", stdout)
+			}
+		})
+	}
+}
+
+func TestDetectStopsAtDeadline(t *testing.T) {
+	expired, cancel := context.WithCancel(context.Background())
+	cancel()
+	bookmakers := plantedBookmakers()
+	for _, strategy := range []BestOddsStrategy{BestOddsMap, BestOddsSorted, BestOddsParallel} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			opts := scanOptions{TotalBet: 100, ArbThreshold: 1, BestOdds: strategy}
+			detected, partial := detectArbitrageOpportunities(context.Background(), bookmakers, opts)
+			if partial || len(detected) != 1 {
+				t.Errorf("without a deadline detected %d, partial %v, want 1 complete", len(detected), partial)
+			}
+			detected, partial = detectArbitrageOpportunities(expired, bookmakers, opts)
+			if !partial || len(detected) != 0 {
+				t.Errorf("past the deadline detected %d, partial %v, want none and partial", len(detected), partial)
+			}
+		})
+	}
+}
+
+func TestRunDeadlineReportsPartialResults(t *testing.T) {
+	code, stdout, stderr := runCLI(t, "-deadline", "1ns", "-file", writeTestData(t, plantedBookmakers()))
+	if code != exitNoOpportunities {
+		t.Errorf("exit code = %d, want %d", code, exitNoOpportunities)
+	}
+	if !strings.Contains(stdout, "Partial results: detection stopped at the deadline\n") {
+		t.Errorf("stdout = %q, want the partial results line", stdout)
+	}
+	if !strings.Contains(stderr, "Warning: "+partialResultsWarning) {
+		t.Errorf("stderr = %q, want the partial results warning", stderr)
+	}
+}
+
+func TestStakeAllocation(t *testing.T) {
+	tests := []struct {
+		name                        string
+		stakes                      StakeAllocation
+		odds                        Odds
+		wantTotal                   float64
+		wantWin, wantDraw, wantLose float64
+	}{
+		{name: "three-way", stakes: StakeAllocation{Win: 40, Draw: 35, Lose: 25}, odds: Odds{Win: 2.5, Draw: 3, Lose: 4},
+			wantTotal: 100, wantWin: 100, wantDraw: 105, wantLose: 100},
+		{name: "two-way", stakes: StakeAllocation{Win: 60, Lose: 40}, odds: Odds{Win: 1.7, Lose: 2.5},
+			wantTotal: 100, wantWin: 102, wantLose: 100},
+		{name: "empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stakes.Total(); math.Abs(got-tt.wantTotal) > 1e-9 {
+				t.Errorf("Total() = %v, want %v", got, tt.wantTotal)
+			}
+			win, draw, lose := tt.stakes.Payout(tt.odds)
+			if math.Abs(win-tt.wantWin) > 1e-9 || math.Abs(draw-tt.wantDraw) > 1e-9 || math.Abs(lose-tt.wantLose) > 1e-9 {
+				t.Errorf("Payout() = %v, %v, %v, want %v, %v, %v", win, draw, lose, tt.wantWin, tt.wantDraw, tt.wantLose)
+			}
+			for outcome, want := range map[string]float64{OutcomeWin: tt.stakes.Win, OutcomeDraw: tt.stakes.Draw, OutcomeLose: tt.stakes.Lose} {
+				if got := tt.stakes.Stake(outcome); got != want {
+					t.Errorf("Stake(%q) = %v, want %v", outcome, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCalculateStakesEqualizesPayouts(t *testing.T) {
+	odds := Odds{Win: 3, Draw: 4, Lose: 4}
+	stakes := calculateStakes(odds, 100)
+	if math.Abs(stakes.Total()-100) > 1e-9 {
+		t.Errorf("stakes total %v, want 100", stakes.Total())
+	}
+	win, draw, lose := stakes.Payout(odds)
+	if math.Abs(win-120) > 1e-9 || math.Abs(draw-120) > 1e-9 || math.Abs(lose-120) > 1e-9 {
+		t.Errorf("payouts = %v, %v, %v, want 120 each", win, draw, lose)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+// Calculate how far each leg's odds can drop before an opportunity stops
+// being an arbitrage, keyed by outcome. Each value is the leg's
+// breakEvenOdds, the lowest odds at which it still breaks even with the other
+// legs' odds held fixed and the stakes recalculated; the further it sits below
+// the leg's current odds, the more robust the opportunity is to that price
+// moving. Two-way markets only consider their win and lose legs.
+func sensitivity(opportunity ArbitrageOpportunity) map[string]float64 {
+	legs := opportunityLegs(opportunity)
+	thresholds := make(map[string]float64, len(legs))
+	for _, leg := range legs {
+		thresholds[leg.Outcome] = breakEvenOdds(opportunity.Odds, leg.Outcome)
+	}
+	return thresholds
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestSensitivity(t *testing.T) {
+	tests := []struct {
+		name string
+		odds Odds
+		want map[string]float64
+	}{
+		{
+			name: "three way",
+			odds: Odds{Win: 2.5, Draw: 4, Lose: 5},
+			want: map[string]float64{OutcomeWin: 1 / (1 - 0.25 - 0.2), OutcomeDraw: 1 / (1 - 0.4 - 0.2), OutcomeLose: 1 / (1 - 0.4 - 0.25)},
+		},
+		{
+			name: "two way",
+			odds: Odds{Win: 2.2, Lose: 2.2},
+			want: map[string]float64{OutcomeWin: 1 / (1 - 1/2.2), OutcomeLose: 1 / (1 - 1/2.2)},
+		},
+		{
+			name: "other legs alone exceed 100%",
+			odds: Odds{Win: 1.5, Draw: 2, Lose: 20},
+			want: map[string]float64{OutcomeWin: 1 / (1 - 0.5 - 0.05), OutcomeDraw: 1 / (1 - 1/1.5 - 0.05), OutcomeLose: math.Inf(1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sensitivity(ArbitrageOpportunity{Odds: tt.odds})
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for outcome, want := range tt.want {
+				if g := got[outcome]; !(g == want || math.Abs(g-want) < 1e-9) {
+					t.Errorf("%s = %v, want %v", outcome, g, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintSensitivityOnlyForSingleMarkets(t *testing.T) {
+	out := outputOptions{Precision: 2, OddsPrecision: 2, Sensitivity: true}
+	straight := ArbitrageOpportunity{GameID: "g1", Odds: Odds{Win: 2.5, Draw: 4, Lose: 5}, TotalBet: 100}
+
+	var buf bytes.Buffer
+	printArbitrageOpportunity(&buf, straight, out)
+	if !strings.Contains(buf.String(), "Break-even odds: ") {
+		t.Errorf("straight opportunity output lacks break-even odds:\n%s", buf.String())
+	}
+
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.5, Draw: 3, Lose: 6}, DrawNoBet: &Odds{Win: 1.9, Lose: 2.3}, Available: true}}},
+		{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.5, Draw: 4, Lose: 6}, Available: true}}},
+	}
+	dnb := findDrawNoBetArbitrage(bookmakers, scanOptions{TotalBet: 100})
+	if len(dnb) != 1 {
+		t.Fatalf("got %d draw no bet opportunities, want 1", len(dnb))
+	}
+	buf.Reset()
+	printArbitrageOpportunity(&buf, dnb[0], out)
+	if strings.Contains(buf.String(), "Break-even odds") {
+		t.Errorf("draw no bet output has straight-bet break-even odds:\n%s", buf.String())
+	}
+}
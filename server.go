@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Serve arbitrage scans over HTTP
+type server struct {
+	mu          sync.RWMutex
+	filename    string
+	bookmakers  []Bookmaker
+	cfg         Config
+	opts        scanOptions
+	maxMemoryMB uint64
+	retry       retryPolicy
+}
+
+// Define the structure returned after regenerating data
+type generateSummary struct {
+	Bookmakers int    `json:"bookmakers"`
+	Games      int    `json:"games"`
+	Seed       int64  `json:"seed,omitempty"`
+	File       string `json:"file"`
+}
+
+// Define the structure of an ad-hoc calculation request
+type calculateRequest struct {
+	Win      float64 `json:"win"`
+	Draw     float64 `json:"draw"`
+	Lose     float64 `json:"lose"`
+	TotalBet float64 `json:"total_bet"`
+}
+
+// Define the structure of an ad-hoc calculation result. Stakes and profit
+// are only included when the odds are an arbitrage.
+type calculateResponse struct {
+	Arbitrage           bool             `json:"arbitrage"`
+	ArbitragePercentage float64          `json:"arbitrage_percentage"`
+	Stakes              *StakeAllocation `json:"stakes,omitempty"`
+	GuaranteedProfit    float64          `json:"guaranteed_profit,omitempty"`
+	Message             string           `json:"message,omitempty"`
+}
+
+// Define the structure for one bookmaker's quote for a fixture
+type fixtureQuote struct {
+	Bookmaker string `json:"bookmaker"`
+	Odds      Odds   `json:"odds"`
+	Available bool   `json:"available"`
+}
+
+// Define the structure comparing every bookmaker's odds for a fixture with
+// the best odds across them
+type fixtureReport struct {
+	GameID              string             `json:"game_id"`
+	Sport               string             `json:"sport,omitempty"`
+	Quotes              []fixtureQuote     `json:"quotes"`
+	Best                BestOddsWithSource `json:"best"`
+	ArbitragePercentage float64            `json:"arbitrage_percentage"`
+}
+
+// Create a server for a set of bookmakers persisted to filename
+func newServer(filename string, bookmakers []Bookmaker, cfg Config, opts scanOptions, maxMemoryMB uint64, retry retryPolicy) *server {
+	return &server{
+		filename:    filename,
+		bookmakers:  bookmakers,
+		cfg:         cfg,
+		opts:        opts,
+		maxMemoryMB: maxMemoryMB,
+		retry:       retry,
+	}
+}
+
+// Register the server's handlers
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/arbitrage", s.handleArbitrage)
+	mux.HandleFunc("/generate", s.handleGenerate)
+	mux.HandleFunc("/calculate", s.handleCalculate)
+	mux.HandleFunc("/best-odds", s.handleBestOdds)
+	mux.HandleFunc("/fixture/", s.handleFixture)
+	return mux
+}
+
+// Return the arbitrage opportunities in the current data, ordered by game ID
+func (s *server) handleArbitrage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.RLock()
+	detected, _ := detectArbitrageOpportunities(r.Context(), s.bookmakers, s.opts)
+	opportunities := applyScanOptions(detected, s.opts)
+	s.mu.RUnlock()
+
+	sortOpportunities(opportunities, s.opts.Sort)
+	if opportunities == nil {
+		opportunities = []ArbitrageOpportunity{}
+	}
+	writeJSON(w, http.StatusOK, opportunities)
+}
+
+// Return the best odds for each game in the current data along with the
+// bookmakers offering them, for checking the selection behind /arbitrage
+func (s *server) handleBestOdds(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.RLock()
+	bestOdds := findBestOddsWithStrategy(r.Context(), s.bookmakers, s.opts.BestOdds)
+	s.mu.RUnlock()
+
+	if bestOdds == nil {
+		bestOdds = map[string]BestOddsWithSource{}
+	}
+	writeJSON(w, http.StatusOK, bestOdds)
+}
+
+// Return every bookmaker's odds for the fixture named in the path, e.g.
+// /fixture/abc123, with the best odds per leg and their arbitrage percentage
+func (s *server) handleFixture(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	gameID := strings.TrimPrefix(r.URL.Path, "/fixture/")
+	if gameID == "" {
+		http.Error(w, "missing fixture ID", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.RLock()
+	report := fixtureReport{GameID: gameID, Quotes: []fixtureQuote{}}
+	var fixture []Bookmaker
+	for _, bookmaker := range s.bookmakers {
+		for _, game := range bookmaker.Games {
+			if game.ID != gameID {
+				continue
+			}
+			report.Quotes = append(report.Quotes, fixtureQuote{Bookmaker: bookmaker.Name, Odds: game.Odds, Available: game.Available})
+			fixture = append(fixture, Bookmaker{Name: bookmaker.Name, Games: []Game{game}})
+			if report.Sport == "" {
+				report.Sport = game.Sport
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(report.Quotes) == 0 {
+		http.Error(w, fmt.Sprintf("fixture %q not found", gameID), http.StatusNotFound)
+		return
+	}
+	if best, exists := findBestOddsWithSource(fixture)[gameID]; exists {
+		best.Odds = oddsForSport(best.Odds, report.Sport)
+		report.Best = best
+		report.ArbitragePercentage = calculateArbitragePercentage(best.Odds)
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// Regenerate the fake dataset from the query parameters and persist it. The
+// bookmakers' currencies are looked up again, since the new data replaces
+// the bookmakers they were found for.
+func (s *server) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	numBookmakers, err := strconv.Atoi(query.Get("bookmakers"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid bookmakers parameter %q", query.Get("bookmakers")), http.StatusBadRequest)
+		return
+	}
+	numGames, err := strconv.Atoi(query.Get("games"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid games parameter %q", query.Get("games")), http.StatusBadRequest)
+		return
+	}
+	var seed int64
+	if value := query.Get("seed"); value != "" {
+		seed, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid seed parameter %q", value), http.StatusBadRequest)
+			return
+		}
+	}
+	names := NamesRandom
+	if value := query.Get("names"); value != "" {
+		if names, err = parseNameMode(value); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := checkGenerationSize(numBookmakers, numGames, s.maxMemoryMB); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bookmakers := generateBookmakersFromSeed(numBookmakers, numGames, seed, names)
+	// The config's settings are applied to a copy so the file is written
+	// without them, and the new bookmakers' currencies replace the old ones
+	configured := append([]Bookmaker(nil), bookmakers...)
+	applyConfig(configured, s.cfg)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var currencies map[string]localCurrency
+	if s.opts.BaseCurrency != "" {
+		if currencies, err = bookmakerCurrencies(configured, s.opts.BaseCurrency, s.cfg.rateSource()); err != nil {
+			http.Error(w, "converting currencies: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := saveBookmakers(bookmakers, s.filename, s.retry); err != nil {
+		http.Error(w, "writing bookmakers: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	normalizeEventTimes(configured)
+	s.bookmakers = configured
+	s.opts.Currencies = currencies
+
+	writeJSON(w, http.StatusOK, generateSummary{
+		Bookmakers: numBookmakers,
+		Games:      numBookmakers * numGames,
+		Seed:       seed,
+		File:       s.filename,
+	})
+}
+
+// Calculate stakes and profit for odds posted as JSON, like an online
+// arbitrage calculator
+func (s *server) handleCalculate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req calculateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	odds := Odds{Win: req.Win, Draw: req.Draw, Lose: req.Lose}
+	if err := checkOdds(odds, ""); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := checkTotalBet(req.TotalBet); err != nil {
+		http.Error(w, "total_bet: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opportunity := newArbitrageOpportunity("", odds, req.TotalBet)
+	if opportunity.ArbitragePercentage >= 1 {
+		writeJSON(w, http.StatusOK, calculateResponse{
+			ArbitragePercentage: opportunity.ArbitragePercentage,
+			Message:             "not an arbitrage: the implied probabilities sum to 1 or more",
+		})
+		return
+	}
+	stakes := opportunity.Stakes()
+	writeJSON(w, http.StatusOK, calculateResponse{
+		Arbitrage:           true,
+		ArbitragePercentage: opportunity.ArbitragePercentage,
+		Stakes:              &stakes,
+		GuaranteedProfit:    opportunity.GuaranteedProfit,
+	})
+}
+
+// Write a value as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
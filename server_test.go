@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Send a request to the server's handlers and return the recorded response
+func serveRequest(t *testing.T, srv *server, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.routes().ServeHTTP(rec, req)
+	return rec
+}
+
+// Decode a JSON response body into v, failing the test on a bad status
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body.String(), err)
+	}
+}
+
+// Bookmakers with a single arbitrage on game "planted"
+func plantedBookmakers() []Bookmaker {
+	return []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "planted", TeamA: "A", TeamB: "B", Odds: Odds{Win: 3, Draw: 3.2, Lose: 2}, Available: true}}},
+		{Name: "b", Games: []Game{{ID: "planted", TeamA: "A", TeamB: "B", Odds: Odds{Win: 2, Draw: 4, Lose: 4}, Available: true}}},
+	}
+}
+
+func TestServerGenerateReplacesData(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	filename := filepath.Join(t.TempDir(), "bookmakers.json")
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1}
+	srv := newServer(filename, plantedBookmakers(), Config{}, opts, 64, retryPolicy{})
+
+	var before []ArbitrageOpportunity
+	decodeResponse(t, serveRequest(t, srv, http.MethodGet, "/arbitrage", ""), &before)
+	if len(before) != 1 || before[0].GameID != "planted" {
+		t.Fatalf("before regenerating got %+v, want the planted arbitrage", before)
+	}
+
+	var summary generateSummary
+	decodeResponse(t, serveRequest(t, srv, http.MethodPost, "/generate?bookmakers=20&games=200&seed=5", ""), &summary)
+	if want := (generateSummary{Bookmakers: 20, Games: 4000, Seed: 5, File: filename}); summary != want {
+		t.Errorf("summary = %+v, want %+v", summary, want)
+	}
+
+	saved, err := loadBookmakers(filename, loadOptions{})
+	if err != nil {
+		t.Fatalf("regenerated data was not persisted: %v", err)
+	}
+	if len(saved) != 20 {
+		t.Errorf("persisted %d bookmakers, want 20", len(saved))
+	}
+	detected, _ := detectArbitrageOpportunities(context.Background(), saved, opts)
+	want := applyScanOptions(detected, opts)
+	if want == nil {
+		want = []ArbitrageOpportunity{}
+	}
+	var after []ArbitrageOpportunity
+	decodeResponse(t, serveRequest(t, srv, http.MethodGet, "/arbitrage", ""), &after)
+	if len(after) != len(want) {
+		t.Fatalf("after regenerating got %d opportunities, want %d from the new data", len(after), len(want))
+	}
+	for i := range want {
+		if after[i].ID != want[i].ID || after[i].GameID == "planted" {
+			t.Errorf("opportunity %d = %s on %s, want %s", i, after[i].ID, after[i].GameID, want[i].ID)
+		}
+	}
+}
+
+func TestServerGenerateRebuildsCurrencies(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	// The config prices one of the bookmakers seed 5 generates in euros
+	euro := generateBookmakersFromSeed(2, 5, 5, NamesRandom)[0].Name
+	cfg := Config{Bookmakers: map[string]BookmakerConfig{euro: {Currency: "EUR"}}}
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1, BaseCurrency: "USD",
+		Currencies: map[string]localCurrency{"a": {Currency: "GBP", Rate: 0.8}}}
+	srv := newServer(filepath.Join(t.TempDir(), "bookmakers.json"), plantedBookmakers(), cfg, opts, 64, retryPolicy{})
+
+	decodeResponse(t, serveRequest(t, srv, http.MethodPost, "/generate?bookmakers=2&games=5&seed=5", ""), &generateSummary{})
+	rate, err := defaultRates.Rate("USD", "EUR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]localCurrency{euro: {Currency: "EUR", Rate: rate}}
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	if !reflect.DeepEqual(srv.opts.Currencies, want) {
+		t.Errorf("currencies = %+v, want %+v for the new data", srv.opts.Currencies, want)
+	}
+	if saved, err := loadBookmakers(srv.filename, loadOptions{}); err != nil || saved[0].Currency != "" {
+		t.Errorf("persisted %+v, %v, want the config left out of the file", saved, err)
+	}
+}
+
+func TestServerGenerateRejectsBadParameters(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   int
+	}{
+		{name: "wrong method", method: http.MethodGet, target: "/generate?bookmakers=2&games=2", want: http.StatusMethodNotAllowed},
+		{name: "missing bookmakers", method: http.MethodPost, target: "/generate?games=2", want: http.StatusBadRequest},
+		{name: "non-numeric games", method: http.MethodPost, target: "/generate?bookmakers=2&games=many", want: http.StatusBadRequest},
+		{name: "bad seed", method: http.MethodPost, target: "/generate?bookmakers=2&games=2&seed=x", want: http.StatusBadRequest},
+		{name: "zero games", method: http.MethodPost, target: "/generate?bookmakers=2&games=0", want: http.StatusBadRequest},
+		{name: "above the memory limit", method: http.MethodPost, target: "/generate?bookmakers=1000&games=100000", want: http.StatusBadRequest},
+		{name: "unknown name mode", method: http.MethodPost, target: "/generate?bookmakers=2&games=2&names=fancy", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "bookmakers.json")
+			srv := newServer(filename, plantedBookmakers(), Config{}, scanOptions{TotalBet: 100, ArbThreshold: 1}, 64, retryPolicy{})
+			if rec := serveRequest(t, srv, tt.method, tt.target, ""); rec.Code != tt.want {
+				t.Errorf("status %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+			if len(srv.bookmakers) != 2 {
+				t.Errorf("a rejected request replaced the data")
+			}
+		})
+	}
+}
+
+// Run with -race: regenerating must not race with scans of the current data
+func TestServerGenerateDuringScans(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	filename := filepath.Join(t.TempDir(), "bookmakers.json")
+	srv := newServer(filename, plantedBookmakers(), Config{}, scanOptions{TotalBet: 100, ArbThreshold: 1}, 64, retryPolicy{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if rec := serveRequest(t, srv, http.MethodGet, "/arbitrage", ""); rec.Code != http.StatusOK {
+					t.Errorf("scan status %d", rec.Code)
+				}
+			}
+		}()
+	}
+	for seed := 1; seed <= 3; seed++ {
+		if rec := serveRequest(t, srv, http.MethodPost, fmt.Sprintf("/generate?bookmakers=5&games=50&seed=%d", seed), ""); rec.Code != http.StatusOK {
+			t.Errorf("generate status %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	wg.Wait()
+}
+
+func TestServerCalculate(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	srv := newServer(filepath.Join(t.TempDir(), "bookmakers.json"), nil, Config{}, scanOptions{}, 64, retryPolicy{})
+
+	var arb calculateResponse
+	decodeResponse(t, serveRequest(t, srv, http.MethodPost, "/calculate", `{"win":2,"draw":4,"lose":5,"total_bet":95}`), &arb)
+	if !arb.Arbitrage || arb.Stakes == nil || *arb.Stakes != (StakeAllocation{Win: 50, Draw: 25, Lose: 20}) {
+		t.Errorf("arbitrage response = %+v, want stakes of 50, 25 and 20", arb)
+	}
+	if math.Abs(arb.ArbitragePercentage-0.95) > 1e-9 || math.Abs(arb.GuaranteedProfit-5) > 1e-9 {
+		t.Errorf("arbitrage percentage %v and profit %v, want 0.95 and 5", arb.ArbitragePercentage, arb.GuaranteedProfit)
+	}
+
+	var none calculateResponse
+	decodeResponse(t, serveRequest(t, srv, http.MethodPost, "/calculate", `{"win":2,"draw":3,"lose":4,"total_bet":100}`), &none)
+	if none.Arbitrage || none.Stakes != nil || none.GuaranteedProfit != 0 || !strings.HasPrefix(none.Message, "not an arbitrage") {
+		t.Errorf("non-arbitrage response = %+v", none)
+	}
+}
+
+func TestServerCalculateRejectsBadInput(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	srv := newServer(filepath.Join(t.TempDir(), "bookmakers.json"), nil, Config{}, scanOptions{}, 64, retryPolicy{})
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		want   int
+	}{
+		{name: "wrong method", method: http.MethodGet, want: http.StatusMethodNotAllowed},
+		{name: "malformed body", method: http.MethodPost, body: `{"win":`, want: http.StatusBadRequest},
+		{name: "odds of 1", method: http.MethodPost, body: `{"win":1,"draw":4,"lose":5,"total_bet":100}`, want: http.StatusBadRequest},
+		{name: "negative odds", method: http.MethodPost, body: `{"win":-2,"draw":4,"lose":5,"total_bet":100}`, want: http.StatusBadRequest},
+		{name: "zero bet", method: http.MethodPost, body: `{"win":2,"draw":4,"lose":5}`, want: http.StatusBadRequest},
+		{name: "negative bet", method: http.MethodPost, body: `{"win":2,"draw":4,"lose":5,"total_bet":-10}`, want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := serveRequest(t, srv, tt.method, "/calculate", tt.body); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestServerBestOdds(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1}
+	tests := []struct {
+		name       string
+		bookmakers []Bookmaker
+		want       string
+	}{
+		{name: "best leg sources", bookmakers: plantedBookmakers(),
+			want: `{"planted":{"odds":{"win":3,"draw":4,"lose":4},"win_source":"a","draw_source":"b","lose_source":"b"}}`},
+		{name: "no data", want: `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newServer("", tt.bookmakers, Config{}, opts, 64, retryPolicy{})
+			rec := serveRequest(t, srv, http.MethodGet, "/best-odds", "")
+			var got, want map[string]interface{}
+			decodeResponse(t, rec, &got)
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("body = %s, want %s", rec.Body.String(), tt.want)
+			}
+		})
+	}
+
+	srv := newServer("", plantedBookmakers(), Config{}, opts, 64, retryPolicy{})
+	if rec := serveRequest(t, srv, http.MethodPost, "/best-odds", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestServerFixture(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	srv := newServer("", plantedBookmakers(), Config{}, scanOptions{TotalBet: 100, ArbThreshold: 1}, 64, retryPolicy{})
+	var report fixtureReport
+	decodeResponse(t, serveRequest(t, srv, http.MethodGet, "/fixture/planted", ""), &report)
+	wantQuotes := []fixtureQuote{
+		{Bookmaker: "a", Odds: Odds{Win: 3, Draw: 3.2, Lose: 2}, Available: true},
+		{Bookmaker: "b", Odds: Odds{Win: 2, Draw: 4, Lose: 4}, Available: true},
+	}
+	if report.GameID != "planted" || !reflect.DeepEqual(report.Quotes, wantQuotes) {
+		t.Errorf("report = %+v, want both bookmakers' quotes", report)
+	}
+	wantBest := BestOddsWithSource{Odds: Odds{Win: 3, Draw: 4, Lose: 4}, WinSource: "a", DrawSource: "b", LoseSource: "b"}
+	if report.Best != wantBest {
+		t.Errorf("best = %+v, want %+v", report.Best, wantBest)
+	}
+	if want := 1/3.0 + 1/4.0 + 1/4.0; math.Abs(report.ArbitragePercentage-want) > 1e-9 {
+		t.Errorf("arbitrage percentage = %v, want %v", report.ArbitragePercentage, want)
+	}
+
+	tests := []struct {
+		method, target string
+		want           int
+	}{
+		{method: http.MethodGet, target: "/fixture/unknown", want: http.StatusNotFound},
+		{method: http.MethodGet, target: "/fixture/", want: http.StatusBadRequest},
+		{method: http.MethodPost, target: "/fixture/planted", want: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			if rec := serveRequest(t, srv, tt.method, tt.target, ""); rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
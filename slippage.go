@@ -0,0 +1,43 @@
+package main
+
+// Fetch the current odds for one leg of an opportunity, typically from a
+// bookmaker's API just before placing bets
+type LegFetcher func(gameID string, leg Leg) (float64, error)
+
+// Re-fetch every leg of an opportunity and confirm its odds still hold. The
+// opportunity is dropped if any leg cannot be fetched or its odds moved
+// against us; otherwise stakes and profit are recomputed from the current
+// odds, which may have improved, and everything else about the opportunity
+// is kept.
+func confirmOpportunity(opportunity ArbitrageOpportunity, fetchLeg LegFetcher) (ArbitrageOpportunity, bool) {
+	current := opportunity.Odds
+	for _, leg := range opportunityLegs(opportunity) {
+		odds, err := fetchLeg(opportunity.GameID, leg)
+		if err != nil || odds < leg.Odds {
+			return opportunity, false
+		}
+		switch leg.Outcome {
+		case OutcomeWin:
+			current.Win = odds
+		case OutcomeDraw:
+			current.Draw = odds
+		case OutcomeLose:
+			current.Lose = odds
+		}
+	}
+
+	recomputed := newArbitrageOpportunity(opportunity.GameID, current, opportunity.TotalBet)
+	if recomputed.ArbitragePercentage >= 1 {
+		return opportunity, false
+	}
+	confirmed := opportunity
+	confirmed.Odds = recomputed.Odds
+	confirmed.ArbitragePercentage = recomputed.ArbitragePercentage
+	confirmed.WinStake = recomputed.WinStake
+	confirmed.DrawStake = recomputed.DrawStake
+	confirmed.LoseStake = recomputed.LoseStake
+	confirmed.GuaranteedProfit = recomputed.GuaranteedProfit
+	confirmed.ReturnOnCapital = recomputed.ReturnOnCapital
+	confirmed.ID = opportunityID(confirmed)
+	return confirmed, true
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// Fetch each leg at its reported odds, adjusted for the outcomes in moves
+func movedOdds(moves map[string]float64) LegFetcher {
+	return func(gameID string, leg Leg) (float64, error) {
+		return leg.Odds + moves[leg.Outcome], nil
+	}
+}
+
+func TestConfirmOpportunity(t *testing.T) {
+	opportunity := newArbitrageOpportunity("g1", Odds{Win: 2.6, Draw: 3.6, Lose: 4.2}, 100)
+	opportunity.WinBookmaker, opportunity.DrawBookmaker, opportunity.LoseBookmaker = "a", "b", "c"
+
+	tests := []struct {
+		name     string
+		fetch    LegFetcher
+		wantOK   bool
+		wantOdds Odds
+	}{
+		{name: "unchanged", fetch: movedOdds(nil), wantOK: true, wantOdds: opportunity.Odds},
+		{name: "one leg improved", fetch: movedOdds(map[string]float64{OutcomeDraw: 0.4}), wantOK: true, wantOdds: Odds{Win: 2.6, Draw: 4, Lose: 4.2}},
+		{name: "one leg lowered", fetch: movedOdds(map[string]float64{OutcomeLose: -0.1})},
+		{name: "fetch fails", fetch: func(string, Leg) (float64, error) { return 0, errors.New("timeout") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			confirmed, ok := confirmOpportunity(opportunity, tt.fetch)
+			if ok != tt.wantOK {
+				t.Fatalf("confirmOpportunity() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if confirmed.Odds != tt.wantOdds {
+				t.Errorf("odds = %+v, want %+v", confirmed.Odds, tt.wantOdds)
+			}
+			want := newArbitrageOpportunity("g1", tt.wantOdds, 100)
+			if math.Abs(confirmed.GuaranteedProfit-want.GuaranteedProfit) > 1e-9 || confirmed.Stakes() != want.Stakes() {
+				t.Errorf("stakes %+v and profit %v, want them recomputed as %+v and %v",
+					confirmed.Stakes(), confirmed.GuaranteedProfit, want.Stakes(), want.GuaranteedProfit)
+			}
+			if confirmed.WinBookmaker != "a" || confirmed.DrawBookmaker != "b" || confirmed.LoseBookmaker != "c" {
+				t.Errorf("bookmakers not kept: %+v", confirmed)
+			}
+		})
+	}
+}
+
+func TestConfirmOpportunityKeepsLegDetails(t *testing.T) {
+	opportunity := newArbitrageOpportunity("g1", Odds{Win: 2.6, Draw: 3.6, Lose: 4.2}, 100)
+	opportunity.Sport = "football"
+	opportunity.Links = map[string]string{OutcomeWin: "https://a.example/g1"}
+	opportunity.MaxPayouts = map[string]float64{OutcomeDraw: 500}
+	opportunity.MaxStakes = map[string]float64{OutcomeLose: 200}
+	opportunity.MinStakes = map[string]float64{OutcomeWin: 5}
+
+	confirmed, ok := confirmOpportunity(opportunity, movedOdds(map[string]float64{OutcomeDraw: 0.4}))
+	if !ok {
+		t.Fatal("confirmOpportunity() ok = false, want true")
+	}
+	tests := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"sport", confirmed.Sport, opportunity.Sport},
+		{"links", confirmed.Links, opportunity.Links},
+		{"max payouts", confirmed.MaxPayouts, opportunity.MaxPayouts},
+		{"max stakes", confirmed.MaxStakes, opportunity.MaxStakes},
+		{"min stakes", confirmed.MinStakes, opportunity.MinStakes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v kept", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirmOpportunityFetchesEveryLeg(t *testing.T) {
+	opportunity := newArbitrageOpportunity("g1", Odds{Win: 2.2, Lose: 2.2}, 100)
+	var fetched []string
+	confirmOpportunity(opportunity, func(gameID string, leg Leg) (float64, error) {
+		if gameID != "g1" {
+			t.Errorf("fetched game %s, want g1", gameID)
+		}
+		fetched = append(fetched, leg.Outcome)
+		return leg.Odds, nil
+	})
+	if len(fetched) != 2 || fetched[0] != OutcomeWin || fetched[1] != OutcomeLose {
+		t.Errorf("fetched legs %v, want win and lose only", fetched)
+	}
+}
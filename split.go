@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Split a bookmakers file into n shards distributed round-robin by bookmaker.
+// outPattern names each shard with a %d verb for its index, e.g.
+// "out-%d.json" writes out-0.json to out-(n-1).json. Every shard must hold at
+// least one bookmaker so each can be loaded on its own. The input is read as
+// written, without converting odds or event times, so the shards together
+// hold exactly what it did.
+func splitBookmakersFile(input string, n int, outPattern string) error {
+	if n < 1 {
+		return fmt.Errorf("shard count must be positive, got %d", n)
+	}
+	if !strings.Contains(outPattern, "%d") {
+		return fmt.Errorf("output pattern %q must contain %%d for the shard index", outPattern)
+	}
+
+	bookmakers, err := loadBookmakers(input, loadOptions{OddsBasis: OddsDecimal, RawEventTimes: true})
+	if err != nil {
+		return err
+	}
+	if n > len(bookmakers) {
+		return fmt.Errorf("cannot split %d bookmakers into %d shards", len(bookmakers), n)
+	}
+
+	shards := make([][]Bookmaker, n)
+	for i, bookmaker := range bookmakers {
+		shards[i%n] = append(shards[i%n], bookmaker)
+	}
+	for i, shard := range shards {
+		if err := saveBookmakers(shard, fmt.Sprintf(outPattern, i), retryPolicy{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write each bookmaker to its own JSON file in dir, named after the
+// bookmaker with characters that are unsafe in file names replaced. Names
+// that sanitize to the same file, ignoring case for case-insensitive
+// filesystems, get a numeric suffix. Returns the files written.
+func writeBookmakersSeparately(bookmakers []Bookmaker, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, newFileError(dir, err)
+	}
+	used := make(map[string]bool)
+	var files []string
+	for _, bookmaker := range bookmakers {
+		base := sanitizeFileName(bookmaker.Name)
+		name := base
+		for i := 2; used[strings.ToLower(name)]; i++ {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		used[strings.ToLower(name)] = true
+
+		filename := filepath.Join(dir, name+".json")
+		if err := writeBookmakersToFile([]Bookmaker{bookmaker}, filename); err != nil {
+			return files, err
+		}
+		files = append(files, filename)
+	}
+	return files, nil
+}
+
+// Replace characters other than letters, digits, dots, dashes and
+// underscores, and leading dots, so a name is safe as a file name
+func sanitizeFileName(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+	sanitized = strings.TrimLeft(sanitized, ".")
+	if sanitized == "" {
+		return "bookmaker"
+	}
+	return sanitized
+}
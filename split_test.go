@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSplitBookmakersFileKeepsEveryBookmaker(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "bookmakers.json")
+	var bookmakers []Bookmaker
+	for i := 0; i < 5; i++ {
+		bookmakers = append(bookmakers, Bookmaker{
+			Name: fmt.Sprintf("book-%d", i),
+			Games: []Game{{
+				ID: fmt.Sprintf("g%d", i), TeamA: "A", TeamB: "B",
+				// Unrounded odds and an event time in a non-RFC3339 layout
+				// must come through unchanged
+				Odds:      Odds{Win: 2.0999999, Draw: 3.123456, Lose: 4 + float64(i)/7},
+				EventAt:   "2024-05-01 18:30",
+				Available: true,
+			}},
+			Reliability: 0.5,
+		})
+	}
+	if err := writeBookmakersToFile(bookmakers, input); err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		shards     int
+		wantShards [][]string
+	}{
+		{shards: 1, wantShards: [][]string{{"book-0", "book-1", "book-2", "book-3", "book-4"}}},
+		{shards: 2, wantShards: [][]string{{"book-0", "book-2", "book-4"}, {"book-1", "book-3"}}},
+		{shards: 5, wantShards: [][]string{{"book-0"}, {"book-1"}, {"book-2"}, {"book-3"}, {"book-4"}}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.shards), func(t *testing.T) {
+			pattern := filepath.Join(t.TempDir(), "out-%d.json")
+			if err := splitBookmakersFile(input, tt.shards, pattern); err != nil {
+				t.Fatal(err)
+			}
+			var union []Bookmaker
+			for i, wantNames := range tt.wantShards {
+				shard, err := loadBookmakers(fmt.Sprintf(pattern, i), loadOptions{RawEventTimes: true})
+				if err != nil {
+					t.Fatalf("shard %d does not load: %v", i, err)
+				}
+				var names []string
+				for _, bookmaker := range shard {
+					names = append(names, bookmaker.Name)
+				}
+				if !reflect.DeepEqual(names, wantNames) {
+					t.Errorf("shard %d holds %v, want %v", i, names, wantNames)
+				}
+				union = append(union, shard...)
+			}
+			sort.Slice(union, func(i, j int) bool { return union[i].Name < union[j].Name })
+			unionFile := filepath.Join(t.TempDir(), "union.json")
+			if err := writeBookmakersToFile(union, unionFile); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(unionFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("union of shards differs from input:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestSplitBookmakersFileErrors(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "bookmakers.json")
+	if err := writeBookmakersToFile([]Bookmaker{{Name: "a"}, {Name: "b"}}, input); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		n       int
+		pattern string
+	}{
+		{name: "zero shards", n: 0, pattern: "out-%d.json"},
+		{name: "more shards than bookmakers", n: 3, pattern: "out-%d.json"},
+		{name: "pattern without index", n: 2, pattern: "out.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern := filepath.Join(t.TempDir(), tt.pattern)
+			if err := splitBookmakersFile(input, tt.n, pattern); err == nil {
+				t.Error("splitBookmakersFile() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "bet365.com", want: "bet365.com"},
+		{name: "Book Maker", want: "Book_Maker"},
+		{name: "../../etc/passwd", want: "_.._etc_passwd"},
+		{name: "a/b\\c:d", want: "a_b_c_d"},
+		{name: ".hidden", want: "hidden"},
+		{name: "Überwetten", want: "_berwetten"},
+		{name: "", want: "bookmaker"},
+		{name: "...", want: "bookmaker"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeFileName(tt.name); got != tt.want {
+			t.Errorf("sanitizeFileName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteBookmakersSeparately(t *testing.T) {
+	// The last three names collide once sanitized, ignoring case
+	bookmakers := []Bookmaker{
+		{Name: "alpha.com", Games: []Game{{ID: "g1", Odds: Odds{Win: 2.0999999, Draw: 3, Lose: 4}, EventAt: "2024-05-01 18:30", Available: true}}},
+		{Name: "Beta Bet", Games: []Game{{ID: "g2", Odds: Odds{Win: 1.5, Draw: 4, Lose: 6}, Available: true}}},
+		{Name: "Beta/Bet", Games: []Game{{ID: "g3", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, Available: true}}},
+		{Name: "beta_bet", Games: []Game{{ID: "g4", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, Available: true}}},
+	}
+	dir := filepath.Join(t.TempDir(), "books")
+	files, err := writeBookmakersSeparately(bookmakers, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNames := []string{"alpha.com.json", "Beta_Bet.json", "Beta_Bet-2.json", "beta_bet-3.json"}
+	if len(files) != len(wantNames) {
+		t.Fatalf("wrote %d files, want %d", len(files), len(wantNames))
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(wantNames) {
+		t.Errorf("directory holds %d files, want %d", len(entries), len(wantNames))
+	}
+	for i, file := range files {
+		if filepath.Base(file) != wantNames[i] {
+			t.Errorf("file %d = %s, want %s", i, filepath.Base(file), wantNames[i])
+		}
+		loaded, err := loadBookmakers(file, loadOptions{RawEventTimes: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(loaded, bookmakers[i:i+1]) {
+			t.Errorf("%s loads %+v, want %+v", file, loaded, bookmakers[i:i+1])
+		}
+	}
+}
+
+func TestRunSplitBookmakersKeepsDataAsWritten(t *testing.T) {
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.0999999, Draw: 2, Lose: 3}, EventAt: "2024-05-01 18:30", Available: true}}},
+		{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.2, Draw: 1.8, Lose: 2.5}, EventAt: "2024-05-01 18:30", Available: true}}},
+	}
+	dir := t.TempDir()
+	// Net odds and event times must not be converted on the way through
+	code, stdout, _ := runCLI(t, "-odds-basis", "net", "-split-bookmakers", dir, "-file", writeTestData(t, bookmakers))
+	if code != exitNoOpportunities || stdout != fmt.Sprintf("Wrote 2 bookmaker files to %s\n", dir) {
+		t.Fatalf("exit code %d, stdout %q", code, stdout)
+	}
+	for _, bookmaker := range bookmakers {
+		loaded, err := loadBookmakers(filepath.Join(dir, bookmaker.Name+".json"), loadOptions{RawEventTimes: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(loaded, []Bookmaker{bookmaker}) {
+			t.Errorf("%s file holds %+v, want %+v", bookmaker.Name, loaded, bookmaker)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import "strings"
+
+// Define the outcome labels a sport uses for the win, draw and lose legs of
+// its odds, and whether it has a draw at all. Sports without a draw are
+// two-way markets: their draw odds are zero and take no stake.
+type SportOutcomes struct {
+	Win     string
+	Draw    string
+	Lose    string
+	HasDraw bool
+}
+
+// Outcome labels used for fixtures without a sport or with an unknown one
+var defaultSportOutcomes = SportOutcomes{Win: "Win", Draw: "Draw", Lose: "Lose", HasDraw: true}
+
+// Outcome labels for known sports, keyed by lower-case sport name
+var sportOutcomes = map[string]SportOutcomes{
+	"soccer":     defaultSportOutcomes,
+	"football":   defaultSportOutcomes,
+	"hockey":     defaultSportOutcomes,
+	"rugby":      defaultSportOutcomes,
+	"tennis":     {Win: "Player A", Lose: "Player B"},
+	"basketball": {Win: "Home", Lose: "Away"},
+	"baseball":   {Win: "Home", Lose: "Away"},
+	"mma":        {Win: "Fighter A", Lose: "Fighter B"},
+	sportTwoWay:  {Win: "Win", Lose: "Lose"},
+}
+
+// Return the outcome labels a sport uses
+func outcomesForSport(sport string) SportOutcomes {
+	if outcomes, exists := sportOutcomes[strings.ToLower(strings.TrimSpace(sport))]; exists {
+		return outcomes
+	}
+	return defaultSportOutcomes
+}
+
+// Drop the draw leg from odds for sports that have no draw
+func oddsForSport(odds Odds, sport string) Odds {
+	if !outcomesForSport(sport).HasDraw {
+		odds.Draw = 0
+	}
+	return odds
+}
+
+// Map each fixture to its sport, taken from the first bookmaker that lists one
+func fixtureSports(bookmakers []Bookmaker) map[string]string {
+	sports := make(map[string]string)
+	for _, bookmaker := range bookmakers {
+		for _, game := range bookmaker.Games {
+			if _, exists := sports[game.ID]; !exists && game.Sport != "" {
+				sports[game.ID] = game.Sport
+			}
+		}
+	}
+	return sports
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestOutcomesForSport(t *testing.T) {
+	tests := []struct {
+		sport string
+		want  SportOutcomes
+	}{
+		{sport: "", want: defaultSportOutcomes},
+		{sport: "Soccer", want: defaultSportOutcomes},
+		{sport: "curling", want: defaultSportOutcomes},
+		{sport: " Tennis ", want: SportOutcomes{Win: "Player A", Lose: "Player B"}},
+		{sport: "basketball", want: SportOutcomes{Win: "Home", Lose: "Away"}},
+	}
+	for _, tt := range tests {
+		if got := outcomesForSport(tt.sport); got != tt.want {
+			t.Errorf("outcomesForSport(%q) = %+v, want %+v", tt.sport, got, tt.want)
+		}
+	}
+}
+
+func TestOddsForSport(t *testing.T) {
+	odds := Odds{Win: 2, Draw: 3.5, Lose: 4}
+	if got := oddsForSport(odds, "soccer"); got != odds {
+		t.Errorf("soccer odds = %+v, want %+v", got, odds)
+	}
+	if got, want := oddsForSport(odds, "tennis"), (Odds{Win: 2, Lose: 4}); got != want {
+		t.Errorf("tennis odds = %+v, want %+v", got, want)
+	}
+}
+
+func TestFixtureSports(t *testing.T) {
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1"}, {ID: "g2", Sport: "tennis"}}},
+		{Name: "b", Games: []Game{{ID: "g1", Sport: "soccer"}, {ID: "g2", Sport: "mma"}}},
+	}
+	got := fixtureSports(bookmakers)
+	if len(got) != 2 || got["g1"] != "soccer" || got["g2"] != "tennis" {
+		t.Errorf("fixtureSports() = %v, want g1 soccer and g2 tennis", got)
+	}
+}
+
+func TestPrintTennisOpportunity(t *testing.T) {
+	// The stray draw quote is ignored for a sport without a draw
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "t1", Sport: "tennis", Odds: Odds{Win: 2.2, Draw: 50, Lose: 1.8}, Available: true}}},
+		{Name: "b", Games: []Game{{ID: "t1", Sport: "tennis", Odds: Odds{Win: 1.8, Lose: 2.2}, Available: true}}},
+	}
+	opportunities, _ := detectArbitrageOpportunities(context.Background(), bookmakers, scanOptions{TotalBet: 100, ArbThreshold: 1})
+	if len(opportunities) != 1 {
+		t.Fatalf("got %d opportunities, want 1", len(opportunities))
+	}
+	if legs := opportunityLegs(opportunities[0]); len(legs) != 2 {
+		t.Fatalf("got %d legs, want 2", len(legs))
+	}
+
+	var buf bytes.Buffer
+	printArbitrageOpportunity(&buf, opportunities[0], defaultOutputOptions)
+	output := buf.String()
+	if !strings.Contains(output, "Odds: Player A: 2.20, Player B: 2.20\n") {
+		t.Errorf("output lacks player-labelled odds:\n%s", output)
+	}
+	if !strings.Contains(output, "Bookmakers: Player A: a, Player B: b\n") {
+		t.Errorf("output lacks player-labelled bookmakers:\n%s", output)
+	}
+	if strings.Contains(output, "Draw") || strings.Contains(output, "Win") {
+		t.Errorf("tennis output uses three-way labels:\n%s", output)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"math"
+)
+
+// Return the largest single-leg stake of an opportunity
+func largestLegStake(opportunity ArbitrageOpportunity) float64 {
+	return math.Max(opportunity.WinStake, math.Max(opportunity.DrawStake, opportunity.LoseStake))
+}
+
+// Scale every stake of an opportunity, and with them its total bet and
+// guaranteed profit, by factor
+func scalePosition(opportunity ArbitrageOpportunity, factor float64) ArbitrageOpportunity {
+	opportunity.WinStake *= factor
+	opportunity.DrawStake *= factor
+	opportunity.LoseStake *= factor
+	opportunity.TotalBet *= factor
+	opportunity.GuaranteedProfit *= factor
+	return opportunity
+}
+
+// Fit an opportunity within a per-leg stake cap. Opportunities whose largest
+// leg exceeds the cap are excluded unless scale is set, in which case the
+// whole position is scaled down so the largest leg equals the cap. A cap of
+// zero disables the check.
+func fitMaxLegStake(opportunity ArbitrageOpportunity, maxLegStake float64, scale bool) (ArbitrageOpportunity, bool) {
+	largest := largestLegStake(opportunity)
+	if maxLegStake <= 0 || largest <= maxLegStake {
+		return opportunity, true
+	}
+	if !scale {
+		return opportunity, false
+	}
+	originalTotal := opportunity.TotalBet
+	opportunity = scalePosition(opportunity, maxLegStake/largest)
+	opportunity.ScaledFrom = originalTotal
+	return opportunity, true
+}
+
+// Map bookmaker names to their maximum payout per bet, leaving out
+// bookmakers without a cap
+func bookmakerMaxPayouts(bookmakers []Bookmaker) map[string]float64 {
+	caps := make(map[string]float64)
+	for _, bookmaker := range bookmakers {
+		if bookmaker.MaxPayout > 0 {
+			caps[bookmaker.Name] = bookmaker.MaxPayout
+		}
+	}
+	return caps
+}
+
+// Look up the payout cap of each leg of an opportunity keyed by outcome,
+// leaving out legs at bookmakers without a cap
+func opportunityMaxPayouts(opportunity ArbitrageOpportunity, caps map[string]float64) map[string]float64 {
+	var payouts map[string]float64
+	for _, leg := range opportunityLegs(opportunity) {
+		limit, exists := caps[leg.Bookmaker]
+		if !exists {
+			continue
+		}
+		if payouts == nil {
+			payouts = make(map[string]float64)
+		}
+		payouts[leg.Outcome] = limit
+	}
+	return payouts
+}
+
+// Fit an opportunity within its legs' payout caps by scaling the whole
+// position down until no leg's payout, stake times odds, exceeds its cap.
+// Scaling keeps outcomes balanced, so the achievable profit shrinks in
+// proportion. Opportunities left with no profit are excluded.
+func fitMaxPayouts(opportunity ArbitrageOpportunity) (ArbitrageOpportunity, bool) {
+	factor := 1.0
+	for _, leg := range opportunityLegs(opportunity) {
+		if leg.MaxPayout <= 0 {
+			continue
+		}
+		if payout := leg.Stake * leg.Odds; payout > leg.MaxPayout {
+			factor = math.Min(factor, leg.MaxPayout/payout)
+		}
+	}
+	if factor == 1 {
+		return opportunity, true
+	}
+	if opportunity.ScaledFrom == 0 {
+		opportunity.ScaledFrom = opportunity.TotalBet
+	}
+	opportunity = scalePosition(opportunity, factor)
+	return opportunity, opportunity.GuaranteedProfit > 0
+}
+
+// Sport key of a bookmaker's stake limits covering every sport not listed
+const defaultSportLimit = "default"
+
+// Map bookmaker names to their per-sport stake limits, leaving out
+// bookmakers without any
+func bookmakerStakeLimits(bookmakers []Bookmaker) map[string]map[string]float64 {
+	limits := make(map[string]map[string]float64)
+	for _, bookmaker := range bookmakers {
+		if len(bookmaker.MaxStakes) > 0 {
+			limits[bookmaker.Name] = bookmaker.MaxStakes
+		}
+	}
+	return limits
+}
+
+// Return the stake limit for a sport from a set of per-sport limits, falling
+// back to the default limit, or zero when there is none
+func sportStakeLimit(limits map[string]float64, sport string) float64 {
+	if limit, exists := limits[sport]; exists {
+		return limit
+	}
+	return limits[defaultSportLimit]
+}
+
+// Look up the stake limit of each leg of an opportunity for its sport keyed
+// by outcome, leaving out legs at bookmakers without a limit
+func opportunityMaxStakes(opportunity ArbitrageOpportunity, limits map[string]map[string]float64) map[string]float64 {
+	var stakes map[string]float64
+	for _, leg := range opportunityLegs(opportunity) {
+		limit := sportStakeLimit(limits[leg.Bookmaker], opportunity.Sport)
+		if limit <= 0 {
+			continue
+		}
+		if stakes == nil {
+			stakes = make(map[string]float64)
+		}
+		stakes[leg.Outcome] = limit
+	}
+	return stakes
+}
+
+// Fit an opportunity within its legs' stake limits by scaling the whole
+// position down until no leg's stake exceeds its limit, keeping outcomes
+// balanced as fitMaxPayouts does. Opportunities left with no profit are
+// excluded.
+func fitMaxStakes(opportunity ArbitrageOpportunity) (ArbitrageOpportunity, bool) {
+	factor := 1.0
+	for _, leg := range opportunityLegs(opportunity) {
+		if leg.MaxStake > 0 && leg.Stake > leg.MaxStake {
+			factor = math.Min(factor, leg.MaxStake/leg.Stake)
+		}
+	}
+	if factor == 1 {
+		return opportunity, true
+	}
+	if opportunity.ScaledFrom == 0 {
+		opportunity.ScaledFrom = opportunity.TotalBet
+	}
+	opportunity = scalePosition(opportunity, factor)
+	return opportunity, opportunity.GuaranteedProfit > 0
+}
+
+// Reported for positions that cannot meet their legs' minimum stakes
+var errInfeasibleMinStakes = errors.New("meeting the bookmakers' minimum stakes needs a position above -max-total-bet or the stake limits")
+
+// Map bookmaker names to their minimum stake, leaving out bookmakers
+// without one
+func bookmakerMinStakes(bookmakers []Bookmaker) map[string]float64 {
+	minimums := make(map[string]float64)
+	for _, bookmaker := range bookmakers {
+		if bookmaker.MinStake > 0 {
+			minimums[bookmaker.Name] = bookmaker.MinStake
+		}
+	}
+	return minimums
+}
+
+// Look up the minimum stake of each leg of an opportunity keyed by outcome,
+// leaving out legs at bookmakers without one
+func opportunityMinStakes(opportunity ArbitrageOpportunity, minimums map[string]float64) map[string]float64 {
+	var stakes map[string]float64
+	for _, leg := range opportunityLegs(opportunity) {
+		minimum, exists := minimums[leg.Bookmaker]
+		if !exists {
+			continue
+		}
+		if stakes == nil {
+			stakes = make(map[string]float64)
+		}
+		stakes[leg.Outcome] = minimum
+	}
+	return stakes
+}
+
+// Fit an opportunity to its legs' minimum stakes by scaling the whole
+// position up until every leg, typically the underdog's small stake, meets
+// its bookmaker's minimum. Scaling keeps outcomes balanced and grows the
+// profit in proportion. The opportunity is infeasible when the scaled total
+// would exceed maxTotalBet or a leg would break the per-leg cap or its stake
+// and payout limits; zero caps are not checked.
+func fitMinStakes(opportunity ArbitrageOpportunity, maxTotalBet, maxLegStake float64) (ArbitrageOpportunity, bool) {
+	factor := 1.0
+	for _, leg := range opportunityLegs(opportunity) {
+		if leg.MinStake > 0 && leg.Stake < leg.MinStake {
+			factor = math.Max(factor, leg.MinStake/leg.Stake)
+		}
+	}
+	if factor == 1 {
+		return opportunity, true
+	}
+	if maxTotalBet > 0 && opportunity.TotalBet*factor > maxTotalBet {
+		return opportunity, false
+	}
+	if opportunity.ScaledFrom == 0 {
+		opportunity.ScaledFrom = opportunity.TotalBet
+	}
+	opportunity = scalePosition(opportunity, factor)
+	for _, leg := range opportunityLegs(opportunity) {
+		if (maxLegStake > 0 && leg.Stake > maxLegStake) ||
+			(leg.MaxStake > 0 && leg.Stake > leg.MaxStake) ||
+			(leg.MaxPayout > 0 && leg.Stake*leg.Odds > leg.MaxPayout) {
+			return opportunity, false
+		}
+	}
+	return opportunity, true
+}
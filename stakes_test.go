@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLargestLegStake(t *testing.T) {
+	opportunity := ArbitrageOpportunity{WinStake: 20, DrawStake: 45, LoseStake: 35}
+	if got := largestLegStake(opportunity); got != 45 {
+		t.Errorf("largestLegStake() = %v, want 45", got)
+	}
+}
+
+func TestFitMaxLegStake(t *testing.T) {
+	// A heavy favourite takes about 67 of the 100 staked
+	favourite := newArbitrageOpportunity("g1", Odds{Win: 1.5, Draw: 5, Lose: 8}, 100)
+
+	tests := []struct {
+		name        string
+		maxLegStake float64
+		scale       bool
+		wantFits    bool
+		wantLargest float64
+	}{
+		{name: "no cap", wantFits: true, wantLargest: favourite.WinStake},
+		{name: "cap above every leg", maxLegStake: 80, wantFits: true, wantLargest: favourite.WinStake},
+		{name: "low cap filters", maxLegStake: 50},
+		{name: "low cap scales down", maxLegStake: 50, scale: true, wantFits: true, wantLargest: 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fits := fitMaxLegStake(favourite, tt.maxLegStake, tt.scale)
+			if fits != tt.wantFits {
+				t.Fatalf("fits = %v, want %v", fits, tt.wantFits)
+			}
+			if !fits {
+				return
+			}
+			if largest := largestLegStake(got); math.Abs(largest-tt.wantLargest) > 1e-9 {
+				t.Errorf("largest leg = %v, want %v", largest, tt.wantLargest)
+			}
+		})
+	}
+}
+
+func TestFitMaxLegStakeScalesProfit(t *testing.T) {
+	favourite := newArbitrageOpportunity("g1", Odds{Win: 1.5, Draw: 5, Lose: 8}, 100)
+	scaled, _ := fitMaxLegStake(favourite, 50, true)
+	factor := 50 / favourite.WinStake
+	if scaled.ScaledFrom != 100 {
+		t.Errorf("ScaledFrom = %v, want 100", scaled.ScaledFrom)
+	}
+	if math.Abs(scaled.TotalBet-100*factor) > 1e-9 || math.Abs(scaled.GuaranteedProfit-favourite.GuaranteedProfit*factor) > 1e-9 {
+		t.Errorf("scaled total %v and profit %v, want %v and %v",
+			scaled.TotalBet, scaled.GuaranteedProfit, 100*factor, favourite.GuaranteedProfit*factor)
+	}
+
+	var buf bytes.Buffer
+	printArbitrageOpportunity(&buf, scaled, defaultOutputOptions)
+	if !strings.Contains(buf.String(), "Position scaled from 100.00 to ") {
+		t.Errorf("output does not report the scaling:\n%s", buf.String())
+	}
+}
+
+func TestUnderdogPayoutCapConstrainsPosition(t *testing.T) {
+	// The underdog's bookmaker pays out at most 40 on a bet, about 40% of the
+	// equalized payout on a 100 position
+	bookmakers := []Bookmaker{
+		{Name: "fav", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.5, Draw: 5, Lose: 6}, Available: true}}},
+		{Name: "dog", MaxPayout: 40, Games: []Game{{ID: "g1", Odds: Odds{Win: 1.4, Draw: 4.5, Lose: 8}, Available: true}}},
+	}
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1}
+	detected, _ := detectArbitrageOpportunities(context.Background(), bookmakers, opts)
+	if len(detected) != 1 {
+		t.Fatalf("got %d opportunities, want 1", len(detected))
+	}
+	uncapped := detected[0]
+	if uncapped.MaxPayouts[OutcomeLose] != 40 || len(uncapped.MaxPayouts) != 1 {
+		t.Fatalf("payout caps = %v, want 40 on the lose leg only", uncapped.MaxPayouts)
+	}
+
+	got := applyScanOptions(detected, opts)
+	if len(got) != 1 {
+		t.Fatalf("got %d opportunities after fitting, want 1", len(got))
+	}
+	capped := got[0]
+	if payout := capped.LoseStake * capped.Odds.Lose; math.Abs(payout-40) > 1e-9 {
+		t.Errorf("underdog payout = %v, want the cap of 40", payout)
+	}
+	factor := capped.TotalBet / uncapped.TotalBet
+	if factor >= 1 || math.Abs(capped.GuaranteedProfit-uncapped.GuaranteedProfit*factor) > 1e-9 {
+		t.Errorf("profit %v on %v staked, want %v scaled by %v", capped.GuaranteedProfit, capped.TotalBet, uncapped.GuaranteedProfit, factor)
+	}
+	if capped.ScaledFrom != 100 {
+		t.Errorf("ScaledFrom = %v, want 100", capped.ScaledFrom)
+	}
+	for outcome, net := range outcomeNetResults(capped) {
+		if math.Abs(net-capped.GuaranteedProfit) > 1e-9 {
+			t.Errorf("%s nets %v, want the balanced %v", outcome, net, capped.GuaranteedProfit)
+		}
+	}
+}
+
+func TestFitMaxPayouts(t *testing.T) {
+	opportunity := newArbitrageOpportunity("g1", Odds{Win: 2.6, Draw: 3.6, Lose: 4.2}, 100)
+	tests := []struct {
+		name      string
+		caps      map[string]float64
+		profit    float64
+		wantFits  bool
+		wantTotal float64
+	}{
+		{name: "no caps", wantFits: true, wantTotal: 100},
+		{name: "cap above the payout", caps: map[string]float64{OutcomeWin: 500}, wantFits: true, wantTotal: 100},
+		{name: "tightest cap wins", caps: map[string]float64{OutcomeWin: 90, OutcomeDraw: 55.5}, wantFits: true, wantTotal: 100 * 55.5 / (100 / opportunity.ArbitragePercentage)},
+		{name: "no profit left", caps: map[string]float64{OutcomeWin: 90}, profit: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidate := opportunity
+			candidate.MaxPayouts = tt.caps
+			if tt.profit != 0 {
+				candidate.GuaranteedProfit = tt.profit
+			}
+			got, fits := fitMaxPayouts(candidate)
+			if fits != tt.wantFits {
+				t.Fatalf("fits = %v, want %v", fits, tt.wantFits)
+			}
+			if fits && math.Abs(got.TotalBet-tt.wantTotal) > 1e-9 {
+				t.Errorf("total bet = %v, want %v", got.TotalBet, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestSportStakeLimitConstrainsLeg(t *testing.T) {
+	// The underdog's bookmaker takes at most 5 on soccer and 1000 elsewhere,
+	// against an uncapped lose stake of about 12.60
+	game := func(id, sport string, odds Odds) Game {
+		return Game{ID: id, Sport: sport, Odds: odds, Available: true}
+	}
+	fav, dog := Odds{Win: 1.5, Draw: 5, Lose: 6}, Odds{Win: 1.4, Draw: 4.5, Lose: 8}
+	bookmakers := []Bookmaker{
+		{Name: "fav", Games: []Game{game("soccer", "soccer", fav), game("hockey", "hockey", fav)}},
+		{Name: "dog", Games: []Game{game("soccer", "soccer", dog), game("hockey", "hockey", dog)}},
+	}
+	applyConfig(bookmakers, Config{Bookmakers: map[string]BookmakerConfig{
+		"dog": {MaxStakes: map[string]float64{"soccer": 5, defaultSportLimit: 1000}},
+	}})
+
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1}
+	detected, _ := detectArbitrageOpportunities(context.Background(), bookmakers, opts)
+	got := applyScanOptions(detected, opts)
+	if len(got) != 2 {
+		t.Fatalf("got %d opportunities, want 2", len(got))
+	}
+	limited, unlimited := got[1], got[0]
+	if limited.GameID != "soccer" {
+		limited, unlimited = unlimited, limited
+	}
+	if limited.MaxStakes[OutcomeLose] != 5 || len(limited.MaxStakes) != 1 {
+		t.Errorf("soccer stake limits = %v, want 5 on the lose leg only", limited.MaxStakes)
+	}
+	if math.Abs(limited.LoseStake-5) > 1e-9 || limited.ScaledFrom != 100 {
+		t.Errorf("soccer lose stake = %v scaled from %v, want 5 scaled from 100", limited.LoseStake, limited.ScaledFrom)
+	}
+	for outcome, net := range outcomeNetResults(limited) {
+		if math.Abs(net-limited.GuaranteedProfit) > 1e-9 {
+			t.Errorf("%s nets %v, want the balanced %v", outcome, net, limited.GuaranteedProfit)
+		}
+	}
+	if unlimited.TotalBet != 100 || unlimited.ScaledFrom != 0 {
+		t.Errorf("hockey total bet = %v scaled from %v, want 100 unscaled", unlimited.TotalBet, unlimited.ScaledFrom)
+	}
+}
+
+func TestFitMinStakesScalesUpUnderdog(t *testing.T) {
+	// The underdog at 30 takes about 3.51 of a 100 position on a 95% book,
+	// below its bookmaker's minimum of 5, so the position grows to 142.50
+	bookmakers := []Bookmaker{
+		{Name: "fav", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.2, Draw: 12, Lose: 15}, Available: true}}},
+		{Name: "dog", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.1, Draw: 10, Lose: 30}, Available: true}}},
+	}
+	applyConfig(bookmakers, Config{Bookmakers: map[string]BookmakerConfig{"dog": {MinStake: 5}}})
+	detected, _ := detectArbitrageOpportunities(context.Background(), bookmakers, scanOptions{TotalBet: 100, ArbThreshold: 1})
+	if len(detected) != 1 {
+		t.Fatalf("got %d opportunities, want 1", len(detected))
+	}
+	opportunity := detected[0]
+	if opportunity.MinStakes[OutcomeLose] != 5 || len(opportunity.MinStakes) != 1 {
+		t.Fatalf("minimum stakes = %v, want 5 on the lose leg only", opportunity.MinStakes)
+	}
+	scaledTotal := 5 * 30 * 0.95
+
+	tests := []struct {
+		name                     string
+		maxTotalBet, maxLegStake float64
+		wantFits                 bool
+	}{
+		{name: "uncapped", wantFits: true},
+		{name: "within the total cap", maxTotalBet: 150, wantFits: true},
+		{name: "beyond the total cap", maxTotalBet: 120},
+		// The favourite's stake grows from about 87.72 to 125
+		{name: "beyond the leg cap", maxLegStake: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fits := fitMinStakes(opportunity, tt.maxTotalBet, tt.maxLegStake)
+			if fits != tt.wantFits {
+				t.Fatalf("fits = %v, want %v", fits, tt.wantFits)
+			}
+			if !fits {
+				return
+			}
+			if math.Abs(got.LoseStake-5) > 1e-9 || math.Abs(got.TotalBet-scaledTotal) > 1e-9 || got.ScaledFrom != 100 {
+				t.Errorf("lose stake %v of %v scaled from %v, want 5 of %v from 100", got.LoseStake, got.TotalBet, got.ScaledFrom, scaledTotal)
+			}
+			if want := opportunity.GuaranteedProfit * scaledTotal / 100; math.Abs(got.GuaranteedProfit-want) > 1e-9 {
+				t.Errorf("profit = %v, want %v", got.GuaranteedProfit, want)
+			}
+		})
+	}
+
+	var kept []ArbitrageOpportunity
+	warning := captureStderr(t, func() { kept = applyScanOptions(detected, scanOptions{TotalBet: 100, MaxTotalBet: 120}) })
+	if len(kept) != 0 || !strings.Contains(warning, "Warning: game g1: "+errInfeasibleMinStakes.Error()) {
+		t.Errorf("kept %d opportunities with warning %q, want g1 reported infeasible", len(kept), warning)
+	}
+}
+
+func TestMinStakeSurvivesRounding(t *testing.T) {
+	// The underdog's stake is scaled up to exactly its minimum of 5, which
+	// rounding down or to the nearest unit could take below it
+	bookmakers := []Bookmaker{
+		{Name: "fav", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.2, Draw: 12, Lose: 15}, Available: true}}},
+		{Name: "dog", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.1, Draw: 10, Lose: 30}, Available: true}}},
+	}
+	applyConfig(bookmakers, Config{Bookmakers: map[string]BookmakerConfig{"dog": {MinStake: 5}}})
+	detected, _ := detectArbitrageOpportunities(context.Background(), bookmakers, scanOptions{TotalBet: 100, ArbThreshold: 1})
+
+	tests := []struct {
+		name      string
+		unit      float64
+		mode      RoundingMode
+		wantStake float64
+	}{
+		{name: "down to whole units", unit: 1, mode: RoundDown, wantStake: 5},
+		{name: "down to a unit of 2", unit: 2, mode: RoundDown, wantStake: 6},
+		{name: "nearest 10", unit: 10, mode: RoundNearest, wantStake: 10},
+		{name: "up to a unit of 2", unit: 2, mode: RoundUp, wantStake: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept := applyScanOptions(detected, scanOptions{TotalBet: 100, RoundTo: tt.unit, Rounding: tt.mode})
+			if len(kept) != 1 {
+				t.Fatalf("kept %d opportunities, want 1", len(kept))
+			}
+			if got := kept[0].LoseStake; math.Abs(got-tt.wantStake) > 1e-9 {
+				t.Errorf("lose stake = %v, want %v to meet the minimum of 5", got, tt.wantStake)
+			}
+		})
+	}
+}
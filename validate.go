@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Define the structure for a problem found while validating bookmakers data
+type ValidationIssue struct {
+	Bookmaker string `json:"bookmaker,omitempty"`
+	GameID    string `json:"game_id,omitempty"`
+	Problem   string `json:"problem"`
+}
+
+// Describe a validation issue
+func (i ValidationIssue) String() string {
+	switch {
+	case i.Bookmaker != "" && i.GameID != "":
+		return fmt.Sprintf("%s: game %s: %s", i.Bookmaker, i.GameID, i.Problem)
+	case i.Bookmaker != "":
+		return fmt.Sprintf("%s: %s", i.Bookmaker, i.Problem)
+	}
+	return i.Problem
+}
+
+// Define a plausible range for draw odds. Draw prices far outside it, such as
+// 1.05 or 250 on a soccer match, usually mean the columns of a feed were
+// mapped to the wrong outcomes. A zero bound is not checked.
+type DrawOddsRange struct {
+	Min float64
+	Max float64
+}
+
+// Check draw odds against the range. Two-way markets without draw odds pass.
+func (r DrawOddsRange) check(draw float64) error {
+	if draw == 0 {
+		return nil
+	}
+	switch {
+	case r.Min > 0 && draw < r.Min:
+		return fmt.Errorf("draw odds %v below the expected minimum %v, check the column mapping", draw, r.Min)
+	case r.Max > 0 && draw > r.Max:
+		return fmt.Errorf("draw odds %v above the expected maximum %v, check the column mapping", draw, r.Max)
+	}
+	return nil
+}
+
+// Validate bookmakers data, returning every issue found. Draw odds are also
+// checked against drawRange.
+func validateBookmakers(bookmakers []Bookmaker, drawRange DrawOddsRange) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, name := range findDuplicateBookmakerNames(bookmakers) {
+		issues = append(issues, ValidationIssue{Bookmaker: name, Problem: "duplicate bookmaker name"})
+	}
+	for _, bookmaker := range bookmakers {
+		if strings.TrimSpace(bookmaker.Name) == "" {
+			issues = append(issues, ValidationIssue{Problem: "bookmaker without a name"})
+		}
+		seen := make(map[string]bool, len(bookmaker.Games))
+		for _, game := range bookmaker.Games {
+			switch {
+			case strings.TrimSpace(game.ID) == "":
+				issues = append(issues, ValidationIssue{Bookmaker: bookmaker.Name, Problem: "game without an ID"})
+			case seen[game.ID]:
+				issues = append(issues, ValidationIssue{Bookmaker: bookmaker.Name, GameID: game.ID, Problem: "duplicate game ID"})
+			}
+			seen[game.ID] = true
+			if err := checkOdds(game.Odds, game.Sport); err != nil {
+				issues = append(issues, ValidationIssue{Bookmaker: bookmaker.Name, GameID: game.ID, Problem: err.Error()})
+			} else if err := drawRange.check(oddsForSport(game.Odds, game.Sport).Draw); err != nil {
+				issues = append(issues, ValidationIssue{Bookmaker: bookmaker.Name, GameID: game.ID, Problem: err.Error()})
+			}
+			switch {
+			case strings.TrimSpace(game.TeamA) == "" || strings.TrimSpace(game.TeamB) == "":
+				issues = append(issues, ValidationIssue{Bookmaker: bookmaker.Name, GameID: game.ID, Problem: "missing team name"})
+			case isSelfMatch(game):
+				issues = append(issues, ValidationIssue{Bookmaker: bookmaker.Name, GameID: game.ID, Problem: fmt.Sprintf("team %q plays itself", game.TeamA)})
+			}
+			if game.EventAt != "" {
+				if _, err := parseEventAt(game.EventAt); err != nil {
+					issues = append(issues, ValidationIssue{Bookmaker: bookmaker.Name, GameID: game.ID, Problem: err.Error()})
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// Normalize a team name for comparison
+func normalizeTeamName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Report whether both teams of a game are the same team
+func isSelfMatch(game Game) bool {
+	return normalizeTeamName(game.TeamA) == normalizeTeamName(game.TeamB)
+}
+
+// Remove games in which a team plays itself
+func removeSelfMatches(bookmakers []Bookmaker) []Bookmaker {
+	cleaned := make([]Bookmaker, len(bookmakers))
+	for i, bookmaker := range bookmakers {
+		cleaned[i] = bookmaker
+		cleaned[i].Games = nil
+		for _, game := range bookmaker.Games {
+			if !isSelfMatch(game) {
+				cleaned[i].Games = append(cleaned[i].Games, game)
+			}
+		}
+	}
+	return cleaned
+}
+
+// Find bookmaker names that appear more than once, sorted
+func findDuplicateBookmakerNames(bookmakers []Bookmaker) []string {
+	counts := make(map[string]int)
+	for _, bookmaker := range bookmakers {
+		counts[bookmaker.Name]++
+	}
+	var duplicates []string
+	for name, count := range counts {
+		if count > 1 {
+			duplicates = append(duplicates, name)
+		}
+	}
+	sort.Strings(duplicates)
+	return duplicates
+}
+
+// Make bookmaker names unique by appending a numeric suffix to repeated names.
+// The first bookmaker with a name keeps it; later ones become "name-2",
+// "name-3" and so on, skipping suffixed names that are already taken.
+func ensureUniqueBookmakerNames(bookmakers []Bookmaker) {
+	taken := make(map[string]bool, len(bookmakers))
+	for _, bookmaker := range bookmakers {
+		taken[bookmaker.Name] = true
+	}
+	seen := make(map[string]bool, len(bookmakers))
+	for i, bookmaker := range bookmakers {
+		if !seen[bookmaker.Name] {
+			seen[bookmaker.Name] = true
+			continue
+		}
+		for n := 2; ; n++ {
+			candidate := bookmaker.Name + "-" + strconv.Itoa(n)
+			if !taken[candidate] {
+				bookmakers[i].Name = candidate
+				taken[candidate] = true
+				seen[candidate] = true
+				break
+			}
+		}
+	}
+}
+
+// Validate bookmakers files without scanning them, printing every issue
+// found prefixed by its file. Returns the number of issues; files that cannot
+// be read count as one issue each.
+func validateFiles(w io.Writer, files []string, opts loadOptions, drawRange DrawOddsRange) int {
+	found := 0
+	for _, file := range files {
+		bookmakers, err := loadBookmakers(file, opts)
+		if err != nil {
+			fmt.Fprintln(w, err)
+			found++
+			continue
+		}
+		issues := validateBookmakers(bookmakers, drawRange)
+		for _, issue := range issues {
+			fmt.Fprintf(w, "%s: %s\n", file, issue)
+		}
+		found += len(issues)
+	}
+	if found == 0 {
+		fmt.Fprintln(w, "No issues found")
+	} else {
+		fmt.Fprintf(w, "%d issues found\n", found)
+	}
+	return found
+}
+
+// Print validation issues as warnings
+func printValidationIssues(issues []ValidationIssue) {
+	for _, issue := range issues {
+		warn("%s", issue)
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnsureUniqueBookmakerNames(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  []string
+	}{
+		{names: []string{"a", "b"}, want: []string{"a", "b"}},
+		{names: []string{"a", "a", "a"}, want: []string{"a", "a-2", "a-3"}},
+		// Suffixed names already in use are skipped
+		{names: []string{"a", "a-2", "a"}, want: []string{"a", "a-2", "a-3"}},
+		{names: []string{"a", "a", "a-2"}, want: []string{"a", "a-3", "a-2"}},
+	}
+	for _, tt := range tests {
+		bookmakers := make([]Bookmaker, len(tt.names))
+		for i, name := range tt.names {
+			bookmakers[i].Name = name
+		}
+		ensureUniqueBookmakerNames(bookmakers)
+		var got []string
+		for _, bookmaker := range bookmakers {
+			got = append(got, bookmaker.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ensureUniqueBookmakerNames(%v) = %v, want %v", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratedBookmakerNamesAreUnique(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	for _, bookmakers := range [][]Bookmaker{
+		generateBookmakers(500, 1, NamesRandom),
+		generateBookmakersWithSeed(500, 1, 7, NamesRandom),
+	} {
+		if duplicates := findDuplicateBookmakerNames(bookmakers); len(duplicates) > 0 {
+			t.Errorf("generated duplicate names %v", duplicates)
+		}
+		if len(bookmakers) != 500 {
+			t.Errorf("generated %d bookmakers, want 500", len(bookmakers))
+		}
+	}
+}
+
+func TestValidateBookmakersReportsDuplicateNames(t *testing.T) {
+	game := Game{ID: "g", TeamA: "A", TeamB: "B", Odds: Odds{Win: 2, Draw: 3, Lose: 4}, Available: true}
+	bookmakers := []Bookmaker{
+		{Name: "b", Games: []Game{game}},
+		{Name: "a", Games: []Game{game}},
+		{Name: "b", Games: []Game{game}},
+		{Name: "a", Games: []Game{game}},
+	}
+	got := validateBookmakers(bookmakers, DrawOddsRange{})
+	want := []ValidationIssue{
+		{Bookmaker: "a", Problem: "duplicate bookmaker name"},
+		{Bookmaker: "b", Problem: "duplicate bookmaker name"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("validateBookmakers() = %v, want %v", got, want)
+	}
+}
+
+func TestIsSelfMatch(t *testing.T) {
+	tests := []struct {
+		teamA, teamB string
+		want         bool
+	}{
+		{teamA: "Arsenal", teamB: "Chelsea"},
+		{teamA: "Arsenal", teamB: "Arsenal", want: true},
+		{teamA: "Real  Madrid", teamB: " real madrid", want: true},
+		{teamA: "Real Madrid", teamB: "RealMadrid"},
+	}
+	for _, tt := range tests {
+		if got := isSelfMatch(Game{TeamA: tt.teamA, TeamB: tt.teamB}); got != tt.want {
+			t.Errorf("isSelfMatch(%q, %q) = %v, want %v", tt.teamA, tt.teamB, got, tt.want)
+		}
+	}
+}
+
+func TestGenerationNeverYieldsSelfMatches(t *testing.T) {
+	verifyNoGoroutineLeaks(t)
+	for _, names := range []NameMode{NamesRandom, NamesRealistic} {
+		for _, bookmaker := range generateBookmakersWithSeed(5, 500, 11, names) {
+			for _, game := range bookmaker.Games {
+				if isSelfMatch(game) {
+					t.Fatalf("generated game %s with %q playing itself", game.ID, game.TeamA)
+				}
+			}
+		}
+	}
+}
+
+func TestLoadingReportsSelfMatches(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bookmakers.json")
+	content := `[{"name":"a","games":[
+		{"id":"g1","team_a":"Arsenal","team_b":"Chelsea","odds":{"win":2,"draw":3,"lose":4},"available":true},
+		{"id":"g2","team_a":"Arsenal","team_b":"arsenal","odds":{"win":2,"draw":3,"lose":4},"available":true}]}]`
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bookmakers, err := loadBookmakers(filename, loadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ValidationIssue{{Bookmaker: "a", GameID: "g2", Problem: `team "Arsenal" plays itself`}}
+	if got := validateBookmakers(bookmakers, DrawOddsRange{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("validateBookmakers() = %v, want %v", got, want)
+	}
+	var buf bytes.Buffer
+	if found := validateFiles(&buf, []string{filename}, loadOptions{}, DrawOddsRange{}); found != 1 {
+		t.Errorf("validateFiles() found %d issues, want 1", found)
+	}
+	if !strings.Contains(buf.String(), filename+`: a: game g2: team "Arsenal" plays itself`) {
+		t.Errorf("validateFiles() output = %q", buf.String())
+	}
+
+	cleaned := removeSelfMatches(bookmakers)
+	if len(cleaned[0].Games) != 1 || cleaned[0].Games[0].ID != "g1" {
+		t.Errorf("removeSelfMatches() kept %v, want only g1", cleaned[0].Games)
+	}
+	if len(bookmakers[0].Games) != 2 {
+		t.Errorf("removeSelfMatches() changed its input")
+	}
+}
+
+// A bookmakers file with one defect of each kind validation looks for
+const defectiveBookmakers = `[
+	{"name":"a","games":[
+		{"id":"g1","team_a":"Arsenal","team_b":"Chelsea","odds":{"win":0.9,"draw":3,"lose":4},"available":true},
+		{"id":"","team_a":"Arsenal","team_b":"Chelsea","odds":{"win":2,"draw":3,"lose":4},"available":true},
+		{"id":"g3","team_a":"","team_b":"Chelsea","odds":{"win":2,"draw":3,"lose":4},"available":true},
+		{"id":"g3","team_a":"Leeds","team_b":"leeds","odds":{"win":2,"draw":3,"lose":4},"available":true},
+		{"id":"g5","team_a":"Arsenal","team_b":"Chelsea","odds":{"win":2,"draw":3,"lose":4},"event_at":"next tuesday","available":true}]},
+	{"name":"a","games":[]},
+	{"name":" ","games":[]}
+]`
+
+func TestValidateBookmakersReportsEveryDefect(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bookmakers.json")
+	if err := os.WriteFile(filename, []byte(defectiveBookmakers), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bookmakers, err := loadBookmakers(filename, loadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, eventErr := parseEventAt("next tuesday")
+	want := []string{
+		"a: duplicate bookmaker name",
+		"a: game g1: " + checkOdds(Odds{Win: 0.9, Draw: 3, Lose: 4}, "").Error(),
+		"a: game without an ID",
+		"a: game g3: missing team name",
+		"a: game g3: duplicate game ID",
+		`a: game g3: team "Leeds" plays itself`,
+		"a: game g5: " + eventErr.Error(),
+		"bookmaker without a name",
+	}
+	var got []string
+	for _, issue := range validateBookmakers(bookmakers, DrawOddsRange{}) {
+		got = append(got, issue.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRunValidateOnly(t *testing.T) {
+	defective := filepath.Join(t.TempDir(), "bookmakers.json")
+	if err := os.WriteFile(defective, []byte(defectiveBookmakers), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		file       string
+		wantCode   int
+		wantOutput string
+	}{
+		{name: "defects", file: defective, wantCode: exitInvalidData, wantOutput: "8 issues found\n"},
+		{name: "clean", file: writeTestData(t, plantedBookmakers()), wantCode: exitNoOpportunities, wantOutput: "No issues found\n"},
+		{name: "missing", file: filepath.Join(t.TempDir(), "missing.json"), wantCode: exitInvalidData, wantOutput: "1 issues found\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, _ := runCLI(t, "-validate-only", "-file", tt.file)
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			if !strings.HasSuffix(stdout, tt.wantOutput) || strings.Contains(stdout, "Arbitrage opportunity found") {
+				t.Errorf("stdout = %q, want only validation ending in %q", stdout, tt.wantOutput)
+			}
+			if _, err := os.Stat(tt.file); tt.name == "missing" && err == nil {
+				t.Error("validation generated the missing file")
+			}
+		})
+	}
+}
+
+func TestDrawOddsRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       DrawOddsRange
+		draw    float64
+		wantErr bool
+	}{
+		{name: "within range", r: DrawOddsRange{Min: 2, Max: 15}, draw: 3.4},
+		{name: "absurd draw price", r: DrawOddsRange{Min: 2, Max: 15}, draw: 250, wantErr: true},
+		{name: "below minimum", r: DrawOddsRange{Min: 2, Max: 15}, draw: 1.05, wantErr: true},
+		{name: "no draw", r: DrawOddsRange{Min: 2, Max: 15}},
+		{name: "bounds disabled", draw: 250},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.r.check(tt.draw); (err != nil) != tt.wantErr {
+				t.Errorf("check(%v) = %v, wantErr %v", tt.draw, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateBookmakersFlagsAbsurdDraw(t *testing.T) {
+	// Draw and away columns swapped on g2 put a 250 price on the draw, while
+	// tennis has no draw to check
+	bookmakers := []Bookmaker{{Name: "feed", Games: []Game{
+		{ID: "g1", TeamA: "A", TeamB: "B", Odds: Odds{Win: 2, Draw: 3.4, Lose: 250}, Available: true},
+		{ID: "g2", TeamA: "A", TeamB: "B", Odds: Odds{Win: 2, Draw: 250, Lose: 3.4}, Available: true},
+		{ID: "g3", TeamA: "A", TeamB: "B", Sport: "tennis", Odds: Odds{Win: 1.01, Lose: 20}, Available: true},
+	}}}
+	want := []ValidationIssue{{Bookmaker: "feed", GameID: "g2", Problem: DrawOddsRange{Max: 15}.check(250).Error()}}
+	if got := validateBookmakers(bookmakers, DrawOddsRange{Min: 2, Max: 15}); !reflect.DeepEqual(got, want) {
+		t.Errorf("validateBookmakers() = %v, want %v", got, want)
+	}
+	if got := validateBookmakers(bookmakers, DrawOddsRange{}); len(got) != 0 {
+		t.Errorf("without a range validateBookmakers() = %v, want no issues", got)
+	}
+
+	code, stdout, _ := runCLI(t, "-validate-only", "-draw-odds-max", "15", "-file", writeTestData(t, bookmakers))
+	if code != exitInvalidData || !strings.Contains(stdout, "feed: game g2: draw odds 250 above the expected maximum 15") {
+		t.Errorf("exit code = %d, stdout = %q, want g2's draw flagged", code, stdout)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Define the structure for a soft bookmaker's price beating the fair odds of
+// the sharp line
+type ValueBet struct {
+	GameID    string  `json:"game_id"`
+	Outcome   string  `json:"outcome"`
+	Bookmaker string  `json:"bookmaker"`
+	Odds      float64 `json:"odds"`
+	FairOdds  float64 `json:"fair_odds"`
+	// Expected profit per unit staked if the sharp line's fair probability
+	// is right, odds / fair odds - 1
+	Edge float64 `json:"edge"`
+}
+
+// Find value bets: legs at other bookmakers whose odds beat the de-vigged
+// odds of the sharp bookmaker for the same game by more than edgeThreshold.
+// Unlike arbitrage a value bet is not hedged and only pays off on average.
+// Games the sharp bookmaker does not quote, or has suspended, are skipped.
+// Results are ordered by edge, largest first.
+func findValueBets(bookmakers []Bookmaker, sharpBookName string, edgeThreshold float64) []ValueBet {
+	fair := make(map[string]Odds)
+	for _, bookmaker := range bookmakers {
+		if bookmaker.Name != sharpBookName {
+			continue
+		}
+		for _, game := range bookmaker.Games {
+			odds := oddsForSport(game.Odds, game.Sport)
+			if game.Available && checkOdds(odds, game.Sport) == nil {
+				fair[game.ID] = fairOdds(odds)
+			}
+		}
+	}
+
+	var bets []ValueBet
+	for _, bookmaker := range bookmakers {
+		if bookmaker.Name == sharpBookName {
+			continue
+		}
+		for _, game := range bookmaker.Games {
+			line, exists := fair[game.ID]
+			if !exists || !game.Available {
+				continue
+			}
+			for _, leg := range []struct {
+				outcome string
+				odds    float64
+				fair    float64
+			}{
+				{OutcomeWin, game.Odds.Win, line.Win},
+				{OutcomeDraw, game.Odds.Draw, line.Draw},
+				{OutcomeLose, game.Odds.Lose, line.Lose},
+			} {
+				if leg.fair == 0 || !(leg.odds > 1) {
+					continue
+				}
+				if edge := leg.odds/leg.fair - 1; edge > edgeThreshold {
+					bets = append(bets, ValueBet{
+						GameID:    game.ID,
+						Outcome:   leg.outcome,
+						Bookmaker: bookmaker.Name,
+						Odds:      leg.odds,
+						FairOdds:  leg.fair,
+						Edge:      edge,
+					})
+				}
+			}
+		}
+	}
+	sort.Slice(bets, func(i, j int) bool {
+		if bets[i].Edge != bets[j].Edge {
+			return bets[i].Edge > bets[j].Edge
+		}
+		if bets[i].GameID != bets[j].GameID {
+			return bets[i].GameID < bets[j].GameID
+		}
+		if bets[i].Outcome != bets[j].Outcome {
+			return bets[i].Outcome < bets[j].Outcome
+		}
+		return bets[i].Bookmaker < bets[j].Bookmaker
+	})
+	return bets
+}
+
+// Print value bets by edge
+func printValueBets(w io.Writer, bets []ValueBet, sharpBookName string, out outputOptions) {
+	fmt.Fprintf(w, "Value bets against the fair line of %s:\n", sharpBookName)
+	for _, bet := range bets {
+		fmt.Fprintf(w, "%s %s at %s: odds %.*f, fair odds %.*f, edge %.2f%%\n",
+			bet.GameID, bet.Outcome, bet.Bookmaker, out.OddsPrecision, bet.Odds,
+			out.OddsPrecision, bet.FairOdds, bet.Edge*100)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+// A sharp bookmaker whose line de-vigs to 2.0714/3.6250/4.1429, and a soft
+// bookmaker beating it on the win leg of g1 and the lose leg of g2
+func valueBetBookmakers() []Bookmaker {
+	line := Odds{Win: 2, Draw: 3.5, Lose: 4}
+	return []Bookmaker{
+		{Name: "sharp", Games: []Game{
+			{ID: "g1", Odds: line, Available: true},
+			{ID: "g2", Odds: line, Available: true},
+			{ID: "suspended", Odds: line},
+		}},
+		{Name: "soft", Games: []Game{
+			{ID: "g1", Odds: Odds{Win: 2.2, Draw: 3.4, Lose: 4}, Available: true},
+			{ID: "g2", Odds: Odds{Win: 1.9, Draw: 3.5, Lose: 4.5}, Available: true},
+			{ID: "suspended", Odds: Odds{Win: 3, Draw: 5, Lose: 6}, Available: true},
+			{ID: "unquoted", Odds: Odds{Win: 3, Draw: 5, Lose: 6}, Available: true},
+		}},
+		{Name: "closed", Games: []Game{{ID: "g1", Odds: Odds{Win: 3, Draw: 5, Lose: 6}}}},
+	}
+}
+
+func TestFindValueBets(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold float64
+		want      []ValueBet
+	}{
+		{name: "every positive edge", threshold: 0.05, want: []ValueBet{
+			{GameID: "g2", Outcome: OutcomeLose, Bookmaker: "soft", Odds: 4.5, FairOdds: 4 * 29 / 28.0, Edge: 4.5/(4*29/28.0) - 1},
+			{GameID: "g1", Outcome: OutcomeWin, Bookmaker: "soft", Odds: 2.2, FairOdds: 2 * 29 / 28.0, Edge: 2.2/(2*29/28.0) - 1},
+		}},
+		{name: "high threshold", threshold: 0.07, want: []ValueBet{
+			{GameID: "g2", Outcome: OutcomeLose, Bookmaker: "soft", Odds: 4.5, FairOdds: 4 * 29 / 28.0, Edge: 4.5/(4*29/28.0) - 1},
+		}},
+		{name: "nothing above threshold", threshold: 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findValueBets(valueBetBookmakers(), "sharp", tt.threshold)
+			if len(got) != len(tt.want) {
+				t.Fatalf("found %+v, want %+v", got, tt.want)
+			}
+			for i, bet := range got {
+				want := tt.want[i]
+				if bet.GameID != want.GameID || bet.Outcome != want.Outcome || bet.Bookmaker != want.Bookmaker || bet.Odds != want.Odds ||
+					math.Abs(bet.FairOdds-want.FairOdds) > 1e-9 || math.Abs(bet.Edge-want.Edge) > 1e-9 {
+					t.Errorf("bet %d = %+v, want %+v", i, bet, want)
+				}
+			}
+		})
+	}
+
+	if got := findValueBets(valueBetBookmakers(), "missing", 0); len(got) != 0 {
+		t.Errorf("without the sharp bookmaker found %+v, want none", got)
+	}
+}
+
+func TestPrintValueBets(t *testing.T) {
+	var buf bytes.Buffer
+	printValueBets(&buf, findValueBets(valueBetBookmakers(), "sharp", 0.07), "sharp", defaultOutputOptions)
+	want := "Value bets against the fair line of sharp:\ng2 lose at soft: odds 4.50, fair odds 4.14, edge 8.62%\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
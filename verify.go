@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// Tolerance for floating point error when checking that no outcome loses money
+const verifyEpsilon = 1e-9
+
+// Calculate the net result of each outcome for an opportunity's stakes,
+// independently of the arbitrage percentage. Outcomes without odds, such as
+// the draw in two-way markets, are left out.
+func outcomeNetResults(opportunity ArbitrageOpportunity) map[string]float64 {
+	stakes := opportunity.Stakes()
+	totalStake := stakes.Total()
+	win, draw, lose := stakes.Payout(opportunity.Odds)
+	results := map[string]float64{
+		OutcomeWin:  win - totalStake,
+		OutcomeLose: lose - totalStake,
+	}
+	if opportunity.Odds.Draw != 0 {
+		results[OutcomeDraw] = draw - totalStake
+	}
+	return results
+}
+
+// Check that an opportunity stakes something and that every outcome breaks
+// even or better. A warning is printed when the check disagrees with the
+// arbitrage percentage, which happens when stake rounding has eaten the margin.
+func verifyOpportunity(opportunity ArbitrageOpportunity) bool {
+	if opportunity.Stakes().Total() <= 0 {
+		warn("game %s has no stake left after rounding", opportunity.GameID)
+		return false
+	}
+	verified := true
+	for outcome, net := range outcomeNetResults(opportunity) {
+		if net < -verifyEpsilon {
+			verified = false
+			if opportunity.ArbitragePercentage < 1 {
+				warn("game %s has arbitrage percentage %.4f but the %s outcome loses %.4f with the computed stakes",
+					opportunity.GameID, opportunity.ArbitragePercentage, outcome, -net)
+			}
+		}
+	}
+	return verified
+}
+
+// Print a warning to standard error
+func warn(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOutcomeNetResults(t *testing.T) {
+	opportunity := ArbitrageOpportunity{
+		Odds:      Odds{Win: 2, Draw: 4, Lose: 5},
+		WinStake:  50,
+		DrawStake: 25,
+		LoseStake: 20,
+		TotalBet:  95,
+	}
+	got := outcomeNetResults(opportunity)
+	want := map[string]float64{OutcomeWin: 5, OutcomeDraw: 5, OutcomeLose: 5}
+	if len(got) != len(want) {
+		t.Fatalf("outcomeNetResults() = %v, want %v", got, want)
+	}
+	for outcome, net := range want {
+		if got[outcome] != net {
+			t.Errorf("%s = %v, want %v", outcome, got[outcome], net)
+		}
+	}
+
+	twoWay := outcomeNetResults(ArbitrageOpportunity{Odds: Odds{Win: 2, Lose: 2}, WinStake: 50, LoseStake: 50, TotalBet: 100})
+	if _, exists := twoWay[OutcomeDraw]; exists || len(twoWay) != 2 {
+		t.Errorf("two-way results = %v, want win and lose only", twoWay)
+	}
+}
+
+func TestVerifyOpportunity(t *testing.T) {
+	tests := []struct {
+		name        string
+		opportunity ArbitrageOpportunity
+		want        bool
+	}{
+		{
+			name:        "every outcome profits",
+			opportunity: ArbitrageOpportunity{Odds: Odds{Win: 2.1, Lose: 2.1}, WinStake: 50, LoseStake: 50, TotalBet: 100, ArbitragePercentage: 2 / 2.1},
+			want:        true,
+		},
+		{
+			name:        "break even within epsilon",
+			opportunity: ArbitrageOpportunity{Odds: Odds{Win: 2, Lose: 2}, WinStake: 50, LoseStake: 50, TotalBet: 100, ArbitragePercentage: 1},
+			want:        true,
+		},
+		{
+			name:        "one outcome loses",
+			opportunity: ArbitrageOpportunity{Odds: Odds{Win: 1.5, Lose: 3.1}, WinStake: 70, LoseStake: 30, TotalBet: 100, ArbitragePercentage: 1/1.5 + 1/3.1},
+		},
+		{
+			name:        "nothing staked",
+			opportunity: ArbitrageOpportunity{Odds: Odds{Win: 2.1, Lose: 2.1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyOpportunity(tt.opportunity); got != tt.want {
+				t.Errorf("verifyOpportunity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyCatchesRoundingLoss(t *testing.T) {
+	// A 1.1% margin: stakes of 67.4 and 32.6 round to 70 and 30, and the lose
+	// outcome then pays back only 93 of the 100 staked
+	bookmakers := []Bookmaker{
+		{Name: "a", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.5, Lose: 2.5}, Available: true}}},
+		{Name: "b", Games: []Game{{ID: "g1", Odds: Odds{Win: 1.4, Lose: 3.1}, Available: true}}},
+	}
+	opts := scanOptions{TotalBet: 100, ArbThreshold: 1, RoundTo: 10, Rounding: RoundNearest, TwoWayWithoutDraw: true}
+	detected, _ := detectArbitrageOpportunities(context.Background(), bookmakers, opts)
+	if len(detected) != 1 {
+		t.Fatalf("got %d detected opportunities, want 1", len(detected))
+	}
+
+	if got := applyScanOptions(detected, opts); len(got) != 1 {
+		t.Fatalf("without verification got %d opportunities, want the false one reported", len(got))
+	} else if net := outcomeNetResults(got[0])[OutcomeLose]; net >= 0 {
+		t.Fatalf("lose outcome nets %v, want a loss for the test to mean anything", net)
+	}
+
+	opts.Verify = true
+	if got := applyScanOptions(detected, opts); len(got) != 0 {
+		t.Errorf("with verification got %d opportunities, want the losing one dropped", len(got))
+	}
+}
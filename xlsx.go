@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Read bookmakers data from an Excel workbook using the same columns as the
+// CSV format. An empty sheet name reads every sheet in the workbook. A sheet
+// without a bookmaker column holds the games of a single bookmaker named
+// after the sheet, so traders can keep one sheet per bookmaker.
+func readBookmakersFromXLSX(filename, sheet string) ([]Bookmaker, error) {
+	file, err := excelize.OpenFile(filename)
+	if err != nil {
+		return nil, newFileError(filename, err)
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if sheet != "" {
+		if index, err := file.GetSheetIndex(sheet); err != nil || index < 0 {
+			return nil, &FileError{Path: filename, Err: ErrParse, Cause: fmt.Errorf("sheet %q not found", sheet)}
+		}
+		sheets = []string{sheet}
+	}
+
+	var bookmakers []Bookmaker
+	index := make(map[string]int)
+	for _, name := range sheets {
+		rows, err := file.GetRows(name)
+		if err != nil {
+			return nil, &FileError{Path: filename, Err: ErrParse, Cause: fmt.Errorf("sheet %q: %w", name, err)}
+		}
+		if err := decodeBookmakersSheet(name, rows, &bookmakers, index); err != nil {
+			return nil, &FileError{Path: filename, Err: ErrParse, Cause: fmt.Errorf("sheet %q: %w", name, err)}
+		}
+	}
+	if len(bookmakers) == 0 {
+		return nil, &FileError{Path: filename, Err: ErrNoData}
+	}
+	return bookmakers, nil
+}
+
+// Decode the games on one sheet, grouping them by bookmaker in the order
+// bookmakers first appear across the workbook. Blank rows are skipped and
+// trailing empty cells leave optional columns unset.
+func decodeBookmakersSheet(sheet string, rows [][]string, bookmakers *[]Bookmaker, index map[string]int) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	columns := make(map[string]int, len(csvColumns)+len(csvOptionalColumns))
+	for i, name := range append(csvColumns, csvOptionalColumns...) {
+		columns[name] = i
+	}
+	bookmakerColumn := true
+	start := 0
+	if isCSVHeader(rows[0]) {
+		header := rows[0]
+		if !containsColumn(header, "bookmaker") {
+			header = append([]string{"bookmaker"}, header...)
+			bookmakerColumn = false
+		}
+		var err error
+		if columns, err = csvHeaderColumns(header); err != nil {
+			return err
+		}
+		start = 1
+	}
+
+	for i := start; i < len(rows); i++ {
+		record := rows[i]
+		if isBlankRow(record) {
+			continue
+		}
+		if !bookmakerColumn {
+			record = append([]string{sheet}, record...)
+		}
+		// Excel drops empty cells at the end of a row; they are empty fields,
+		// not missing columns
+		for len(record) < len(columns) {
+			record = append(record, "")
+		}
+		game, name, err := parseCSVGame(record, columns)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i+1, err)
+		}
+		j, exists := index[name]
+		if !exists {
+			index[name] = len(*bookmakers)
+			*bookmakers = append(*bookmakers, Bookmaker{Name: name})
+			j = len(*bookmakers) - 1
+		}
+		(*bookmakers)[j].Games = append((*bookmakers)[j].Games, game)
+	}
+	return nil
+}
+
+// Report whether a header row names the given column
+func containsColumn(header []string, column string) bool {
+	for _, name := range header {
+		if strings.ToLower(strings.TrimSpace(name)) == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Report whether every cell of a row is empty
+func isBlankRow(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Write bookmakers data to an Excel workbook with one sheet per bookmaker,
+// using the CSV columns without the bookmaker column
+func writeBookmakersToXLSX(bookmakers []Bookmaker, filename string) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	header := append(csvColumns[1:], csvOptionalColumns...)
+	for i, bookmaker := range bookmakers {
+		sheet := bookmaker.Name
+		if i == 0 {
+			if err := file.SetSheetName(file.GetSheetName(0), sheet); err != nil {
+				return newFileError(filename, err)
+			}
+		} else if _, err := file.NewSheet(sheet); err != nil {
+			return newFileError(filename, err)
+		}
+		rows := [][]string{header}
+		for _, game := range bookmaker.Games {
+			rows = append(rows, csvRecord(bookmaker.Name, game)[1:])
+		}
+		for r, row := range rows {
+			cells := make([]interface{}, len(row))
+			for c, value := range row {
+				cells[c] = value
+			}
+			cell, err := excelize.CoordinatesToCellName(1, r+1)
+			if err != nil {
+				return newFileError(filename, err)
+			}
+			if err := file.SetSheetRow(sheet, cell, &cells); err != nil {
+				return newFileError(filename, err)
+			}
+		}
+	}
+	if err := file.SaveAs(filename); err != nil {
+		return newFileError(filename, err)
+	}
+	return nil
+}
+
+// Report whether a filename refers to an Excel workbook
+func isXLSXFile(filename string) bool {
+	return strings.EqualFold(filepath.Ext(filename), ".xlsx")
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Write a workbook with the given rows on each sheet, in order
+func writeTestWorkbook(t *testing.T, sheets []string, rows map[string][][]interface{}) string {
+	t.Helper()
+	file := excelize.NewFile()
+	defer file.Close()
+	for i, sheet := range sheets {
+		if i == 0 {
+			if err := file.SetSheetName(file.GetSheetName(0), sheet); err != nil {
+				t.Fatal(err)
+			}
+		} else if _, err := file.NewSheet(sheet); err != nil {
+			t.Fatal(err)
+		}
+		for r, row := range rows[sheet] {
+			cell, _ := excelize.CoordinatesToCellName(1, r+1)
+			row := row
+			if err := file.SetSheetRow(sheet, cell, &row); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	filename := filepath.Join(t.TempDir(), "odds.xlsx")
+	if err := file.SaveAs(filename); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestReadBookmakersFromXLSX(t *testing.T) {
+	filename := writeTestWorkbook(t, []string{"alpha", "mixed"}, map[string][][]interface{}{
+		// One sheet per bookmaker, named after the sheet, with the event time
+		// cell left empty
+		"alpha": {
+			{"game_id", "team_a", "team_b", "win", "draw", "lose", "event_at"},
+			{"g1", "A", "B", 2.5, 3.2, 2.9},
+			{},
+			{"g2", "C", "D", "1.9", "3.5", "4.1", "2024-05-01 15:00:00"},
+		},
+		// A sheet with a bookmaker column and columns in another order
+		"mixed": {
+			{"bookmaker", "win", "draw", "lose", "game_id", "team_a", "team_b", "event_at", "sport", "available"},
+			{"beta", 2.6, 3.1, 2.8, "g1", "A", "B", "", "soccer", "false"},
+			{"alpha", 1.8, 3.6, 4.4, "g3", "E", "F", "2024-05-02 15:00:00"},
+		},
+	})
+
+	bookmakers, err := readBookmakersFromXLSX(filename, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Bookmaker{
+		{Name: "alpha", Games: []Game{
+			{ID: "g1", TeamA: "A", TeamB: "B", Odds: Odds{Win: 2.5, Draw: 3.2, Lose: 2.9}, Available: true},
+			{ID: "g2", TeamA: "C", TeamB: "D", Odds: Odds{Win: 1.9, Draw: 3.5, Lose: 4.1}, EventAt: "2024-05-01 15:00:00", Available: true},
+			{ID: "g3", TeamA: "E", TeamB: "F", Odds: Odds{Win: 1.8, Draw: 3.6, Lose: 4.4}, EventAt: "2024-05-02 15:00:00", Available: true},
+		}},
+		{Name: "beta", Games: []Game{
+			{ID: "g1", Sport: "soccer", TeamA: "A", TeamB: "B", Odds: Odds{Win: 2.6, Draw: 3.1, Lose: 2.8}},
+		}},
+	}
+	if !reflect.DeepEqual(bookmakers, want) {
+		t.Errorf("readBookmakersFromXLSX() = %+v, want %+v", bookmakers, want)
+	}
+
+	mixed, err := readBookmakersFromXLSX(filename, "mixed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mixed) != 2 || mixed[0].Name != "beta" || len(mixed[1].Games) != 1 {
+		t.Errorf("reading only the mixed sheet = %+v", mixed)
+	}
+}
+
+func TestReadBookmakersFromXLSXErrors(t *testing.T) {
+	filename := writeTestWorkbook(t, []string{"alpha"}, map[string][][]interface{}{
+		"alpha": {
+			{"game_id", "team_a", "team_b", "win", "draw", "lose", "event_at"},
+			{"g1", "A", "B", 2.5, "evens", 2.9},
+		},
+	})
+	tests := []struct {
+		name  string
+		sheet string
+		want  error
+	}{
+		{name: "bad odds", want: ErrParse},
+		{name: "missing sheet", sheet: "beta", want: ErrParse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := readBookmakersFromXLSX(filename, tt.sheet); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	empty := writeTestWorkbook(t, []string{"alpha"}, nil)
+	if _, err := readBookmakersFromXLSX(empty, ""); !errors.Is(err, ErrNoData) {
+		t.Errorf("empty workbook error = %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestXLSXRoundTrip(t *testing.T) {
+	bookmakers := []Bookmaker{
+		{Name: "alpha", Games: []Game{{ID: "g1", TeamA: "A", TeamB: "B", Odds: Odds{Win: 2.5, Draw: 3.2, Lose: 2.9}, EventAt: "2024-05-01 15:00:00", Available: true}}},
+		{Name: "beta", Games: []Game{{ID: "g1", Sport: "tennis", TeamA: "A", TeamB: "B", Odds: Odds{Win: 1.7, Lose: 2.2}, EventAt: "2024-05-01 15:00:00"}}},
+	}
+	filename := filepath.Join(t.TempDir(), "odds.xlsx")
+	if err := writeBookmakersToXLSX(bookmakers, filename); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readBookmakersFromXLSX(filename, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, bookmakers) {
+		t.Errorf("round trip = %+v, want %+v", got, bookmakers)
+	}
+}